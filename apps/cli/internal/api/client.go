@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,18 +12,29 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
 )
 
+const DefaultRequestTimeout = 30 * time.Second
+
 type Client struct {
-	BaseURL    string
-	Token      string
-	HTTPClient *http.Client
+	BaseURL string
+	Token   string
+	// TokenSource supplies the token on first use when Token is empty, so
+	// commands that never call the API don't read the credential store.
+	TokenSource    func() (string, error)
+	HTTPClient     *http.Client
+	RequestTimeout time.Duration
+	Retry          RetryPolicy
+	OnRetry        func(RetryEvent)
 }
 
 type APIError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details any    `json:"details,omitempty"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Details    any    `json:"details,omitempty"`
+	StatusCode int    `json:"-"`
 }
 
 func (e *APIError) Error() string {
@@ -32,16 +44,30 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
-func AsAPIError(err error) *APIError {
-	if err == nil {
+// Unwrap exposes the error class so callers can match API failures with
+// errors.Is against the sentinels in internal/errors.
+func (e *APIError) Unwrap() error {
+	if e == nil {
 		return nil
 	}
-	if apiErr, ok := err.(*APIError); ok {
+	if class := lerrors.FromResponse(e.StatusCode, e.Code); class != nil {
+		return class
+	}
+	return nil
+}
+
+func AsAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
 		return apiErr
 	}
 	return nil
 }
 
+func IsNotFound(err error) bool {
+	return lerrors.IsNotFound(err)
+}
+
 type Envelope struct {
 	Ok         bool            `json:"ok"`
 	StatusCode int             `json:"statusCode"`
@@ -53,28 +79,36 @@ type Envelope struct {
 
 func NewClient(baseURL string, token string) *Client {
 	return &Client{
-		BaseURL: strings.TrimRight(baseURL, "/"),
-		Token:   token,
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		BaseURL:        strings.TrimRight(baseURL, "/"),
+		Token:          token,
+		HTTPClient:     &http.Client{},
+		RequestTimeout: DefaultRequestTimeout,
+		Retry:          DefaultRetryPolicy(),
 	}
 }
 
-func (c *Client) GetJSON(path string, out any) error {
-	return c.doJSON(http.MethodGet, path, nil, out)
+// AuthToken returns the bearer token sent with each request.
+func (c *Client) AuthToken() (string, error) {
+	if strings.TrimSpace(c.Token) != "" || c.TokenSource == nil {
+		return c.Token, nil
+	}
+	return c.TokenSource()
 }
 
-func (c *Client) PostJSON(path string, body any, out any) error {
-	return c.doJSON(http.MethodPost, path, body, out)
+func (c *Client) GetJSON(ctx context.Context, path string, out any) error {
+	return c.doJSON(ctx, http.MethodGet, path, nil, out)
 }
 
-func (c *Client) PatchJSON(path string, body any, out any) error {
-	return c.doJSON(http.MethodPatch, path, body, out)
+func (c *Client) PostJSON(ctx context.Context, path string, body any, out any) error {
+	return c.doJSON(ctx, http.MethodPost, path, body, out)
 }
 
-func (c *Client) DeleteJSON(path string, out any) error {
-	return c.doJSON(http.MethodDelete, path, nil, out)
+func (c *Client) PatchJSON(ctx context.Context, path string, body any, out any) error {
+	return c.doJSON(ctx, http.MethodPatch, path, body, out)
+}
+
+func (c *Client) DeleteJSON(ctx context.Context, path string, out any) error {
+	return c.doJSON(ctx, http.MethodDelete, path, nil, out)
 }
 
 type validateResponse struct {
@@ -91,251 +125,258 @@ type QueuedWorkflowRun struct {
 	Status            string `json:"status"`
 }
 
-func (c *Client) ListWorkflows(page int, pageSize int, sortBy string, sortOrder string) (Paginated[Workflow], error) {
+func (c *Client) ListWorkflows(ctx context.Context, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Workflow], error) {
 	var result Paginated[Workflow]
 	path := paginatedPath("/workflows", page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) GetWorkflow(id string) (Workflow, error) {
+func (c *Client) GetWorkflow(ctx context.Context, id string) (Workflow, error) {
 	var result Workflow
-	return result, c.GetJSON("/workflows/"+id, &result)
+	return result, c.GetJSON(ctx, "/workflows/"+id, &result)
 }
 
-func (c *Client) GetWorkflowByKey(key string) (Workflow, error) {
+func (c *Client) GetWorkflowByKey(ctx context.Context, key string) (Workflow, error) {
 	var result Workflow
 	path := "/workflows/by-key/" + url.PathEscape(key)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) CreateWorkflow(name string, definition any) (Workflow, error) {
+func (c *Client) CreateWorkflow(ctx context.Context, name string, definition any) (Workflow, error) {
 	var result Workflow
 	payload := map[string]any{"name": name, "definition": definition}
-	return result, c.PostJSON("/workflows", payload, &result)
+	return result, c.PostJSON(ctx, "/workflows", payload, &result)
 }
 
-func (c *Client) UpdateWorkflow(id string, patch map[string]any) (Workflow, error) {
+func (c *Client) UpdateWorkflow(ctx context.Context, id string, patch map[string]any) (Workflow, error) {
 	var result Workflow
-	return result, c.PatchJSON("/workflows/"+id, patch, &result)
+	return result, c.PatchJSON(ctx, "/workflows/"+id, patch, &result)
 }
 
-func (c *Client) UpdateWorkflowByKey(key string, patch map[string]any) (Workflow, error) {
+func (c *Client) UpdateWorkflowByKey(ctx context.Context, key string, patch map[string]any) (Workflow, error) {
 	var result Workflow
 	path := "/workflows/by-key/" + url.PathEscape(key)
-	return result, c.PatchJSON(path, patch, &result)
+	return result, c.PatchJSON(ctx, path, patch, &result)
 }
 
-func (c *Client) DeleteWorkflow(id string) (Workflow, error) {
+func (c *Client) DeleteWorkflow(ctx context.Context, id string) (Workflow, error) {
 	var result Workflow
-	return result, c.DeleteJSON("/workflows/"+id, &result)
+	return result, c.DeleteJSON(ctx, "/workflows/"+id, &result)
 }
 
-func (c *Client) DeleteWorkflowByKey(key string) (Workflow, error) {
+func (c *Client) DeleteWorkflowByKey(ctx context.Context, key string) (Workflow, error) {
 	var result Workflow
 	path := "/workflows/by-key/" + url.PathEscape(key)
-	return result, c.DeleteJSON(path, &result)
+	return result, c.DeleteJSON(ctx, path, &result)
 }
 
-func (c *Client) RunWorkflow(id string, input any, overrides any) (map[string]string, error) {
+func (c *Client) RunWorkflow(ctx context.Context, id string, input any, overrides any) (map[string]string, error) {
 	var result struct {
 		WorkflowRunID string `json:"workflowRunId"`
 		Status        string `json:"status"`
 	}
 	payload := map[string]any{"input": input, "overrides": overrides}
-	err := c.PostJSON("/workflows/"+id+"/run", payload, &result)
+	err := c.PostJSON(ctx, "/workflows/"+id+"/run", payload, &result)
 	if err != nil {
 		return nil, err
 	}
 	return map[string]string{"workflowRunId": result.WorkflowRunID, "status": result.Status}, nil
 }
 
-func (c *Client) RunWorkflowByKey(key string, input any, overrides any) (QueuedWorkflowRun, error) {
+func (c *Client) RunWorkflowByKey(ctx context.Context, key string, input any, overrides any) (QueuedWorkflowRun, error) {
 	var result QueuedWorkflowRun
 	payload := map[string]any{"input": input, "overrides": overrides}
 	path := "/workflows/by-key/" + url.PathEscape(key) + "/run"
-	return result, c.PostJSON(path, payload, &result)
+	return result, c.PostJSON(ctx, path, payload, &result)
 }
 
-func (c *Client) ValidateWorkflow(id string, definition any) (validateResponse, error) {
+func (c *Client) ValidateWorkflow(ctx context.Context, id string, definition any) (validateResponse, error) {
 	var result validateResponse
 	payload := map[string]any{"definition": definition}
-	return result, c.PostJSON("/workflows/"+id+"/versions/validate", payload, &result)
+	return result, c.PostJSON(ctx, "/workflows/"+id+"/versions/validate", payload, &result)
 }
 
-func (c *Client) ValidateWorkflowByKey(key string, definition any) (validateResponse, error) {
+func (c *Client) ValidateWorkflowByKey(ctx context.Context, key string, definition any) (validateResponse, error) {
 	var result validateResponse
 	payload := map[string]any{"definition": definition}
 	path := "/workflows/by-key/" + url.PathEscape(key) + "/versions/validate"
-	return result, c.PostJSON(path, payload, &result)
+	return result, c.PostJSON(ctx, path, payload, &result)
 }
 
-func (c *Client) ListWorkflowVersions(workflowID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowVersion], error) {
+func (c *Client) ListWorkflowVersions(ctx context.Context, workflowID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowVersion], error) {
 	var result Paginated[WorkflowVersion]
 	path := paginatedPath(fmt.Sprintf("/workflows/%s/versions", workflowID), page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) ListWorkflowVersionsByKey(workflowKey string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowVersion], error) {
+func (c *Client) ListWorkflowVersionsByKey(ctx context.Context, workflowKey string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowVersion], error) {
 	var result Paginated[WorkflowVersion]
 	path := paginatedPath("/workflows/by-key/"+url.PathEscape(workflowKey)+"/versions", page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) GetWorkflowVersion(workflowID string, version string) (WorkflowVersion, error) {
+func (c *Client) GetWorkflowVersion(ctx context.Context, workflowID string, version string) (WorkflowVersion, error) {
 	var result WorkflowVersion
-	return result, c.GetJSON("/workflows/"+workflowID+"/versions/"+version, &result)
+	return result, c.GetJSON(ctx, "/workflows/"+workflowID+"/versions/"+version, &result)
 }
 
-func (c *Client) GetWorkflowVersionByKey(workflowKey string, version string) (WorkflowVersion, error) {
+func (c *Client) GetWorkflowVersionByKey(ctx context.Context, workflowKey string, version string) (WorkflowVersion, error) {
 	var result WorkflowVersion
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/versions/" + url.PathEscape(version)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) CreateWorkflowVersion(workflowID string, definition any) (WorkflowVersion, error) {
+func (c *Client) CreateWorkflowVersion(ctx context.Context, workflowID string, definition any) (WorkflowVersion, error) {
 	var result WorkflowVersion
 	payload := map[string]any{"definition": definition}
-	return result, c.PostJSON("/workflows/"+workflowID+"/versions", payload, &result)
+	return result, c.PostJSON(ctx, "/workflows/"+workflowID+"/versions", payload, &result)
 }
 
-func (c *Client) CreateWorkflowVersionByKey(workflowKey string, definition any) (WorkflowVersion, error) {
+func (c *Client) CreateWorkflowVersionByKey(ctx context.Context, workflowKey string, definition any) (WorkflowVersion, error) {
 	var result WorkflowVersion
 	payload := map[string]any{"definition": definition}
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/versions"
-	return result, c.PostJSON(path, payload, &result)
+	return result, c.PostJSON(ctx, path, payload, &result)
 }
 
-func (c *Client) ListTriggers(workflowID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Trigger], error) {
+func (c *Client) ListTriggers(ctx context.Context, workflowID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Trigger], error) {
 	var result Paginated[Trigger]
 	path := paginatedPath(fmt.Sprintf("/workflows/%s/triggers", workflowID), page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) ListTriggersByWorkflowKey(workflowKey string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Trigger], error) {
+func (c *Client) ListTriggersByWorkflowKey(ctx context.Context, workflowKey string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Trigger], error) {
 	var result Paginated[Trigger]
 	path := paginatedPath("/workflows/by-key/"+url.PathEscape(workflowKey)+"/triggers", page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) GetTrigger(workflowID string, triggerID string) (Trigger, error) {
+func (c *Client) GetTrigger(ctx context.Context, workflowID string, triggerID string) (Trigger, error) {
 	var result Trigger
-	return result, c.GetJSON("/workflows/"+workflowID+"/triggers/"+triggerID, &result)
+	return result, c.GetJSON(ctx, "/workflows/"+workflowID+"/triggers/"+triggerID, &result)
 }
 
-func (c *Client) GetTriggerByKey(workflowKey string, triggerKey string) (Trigger, error) {
+func (c *Client) GetTriggerByKey(ctx context.Context, workflowKey string, triggerKey string) (Trigger, error) {
 	var result Trigger
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/triggers/by-key/" + url.PathEscape(triggerKey)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) CreateTrigger(workflowID string, payload map[string]any) (Trigger, error) {
+func (c *Client) CreateTrigger(ctx context.Context, workflowID string, payload map[string]any) (Trigger, error) {
 	var result Trigger
-	return result, c.PostJSON("/workflows/"+workflowID+"/triggers", payload, &result)
+	return result, c.PostJSON(ctx, "/workflows/"+workflowID+"/triggers", payload, &result)
 }
 
-func (c *Client) CreateTriggerByWorkflowKey(workflowKey string, payload map[string]any) (Trigger, error) {
+func (c *Client) CreateTriggerByWorkflowKey(ctx context.Context, workflowKey string, payload map[string]any) (Trigger, error) {
 	var result Trigger
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/triggers"
-	return result, c.PostJSON(path, payload, &result)
+	return result, c.PostJSON(ctx, path, payload, &result)
 }
 
-func (c *Client) UpdateTrigger(workflowID string, triggerID string, patch map[string]any) (Trigger, error) {
+func (c *Client) UpdateTrigger(ctx context.Context, workflowID string, triggerID string, patch map[string]any) (Trigger, error) {
 	var result Trigger
-	return result, c.PatchJSON("/workflows/"+workflowID+"/triggers/"+triggerID, patch, &result)
+	return result, c.PatchJSON(ctx, "/workflows/"+workflowID+"/triggers/"+triggerID, patch, &result)
 }
 
-func (c *Client) UpdateTriggerByKey(workflowKey string, triggerKey string, patch map[string]any) (Trigger, error) {
+func (c *Client) UpdateTriggerByKey(ctx context.Context, workflowKey string, triggerKey string, patch map[string]any) (Trigger, error) {
 	var result Trigger
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/triggers/by-key/" + url.PathEscape(triggerKey)
-	return result, c.PatchJSON(path, patch, &result)
+	return result, c.PatchJSON(ctx, path, patch, &result)
 }
 
-func (c *Client) DeleteTrigger(workflowID string, triggerID string) (Trigger, error) {
+func (c *Client) DeleteTrigger(ctx context.Context, workflowID string, triggerID string) (Trigger, error) {
 	var result Trigger
-	return result, c.DeleteJSON("/workflows/"+workflowID+"/triggers/"+triggerID, &result)
+	return result, c.DeleteJSON(ctx, "/workflows/"+workflowID+"/triggers/"+triggerID, &result)
 }
 
-func (c *Client) DeleteTriggerByKey(workflowKey string, triggerKey string) (Trigger, error) {
+func (c *Client) DeleteTriggerByKey(ctx context.Context, workflowKey string, triggerKey string) (Trigger, error) {
 	var result Trigger
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/triggers/by-key/" + url.PathEscape(triggerKey)
-	return result, c.DeleteJSON(path, &result)
+	return result, c.DeleteJSON(ctx, path, &result)
 }
 
-func (c *Client) RotateTriggerWebhookKey(workflowID string, triggerID string) (RotateWebhookKeyResponse, error) {
+func (c *Client) RotateTriggerWebhookKey(ctx context.Context, workflowID string, triggerID string) (RotateWebhookKeyResponse, error) {
 	var result RotateWebhookKeyResponse
 	path := "/workflows/" + workflowID + "/triggers/" + triggerID + "/webhook-key/rotate"
-	return result, c.PostJSON(path, map[string]any{}, &result)
+	return result, c.PostJSON(ctx, path, map[string]any{}, &result)
 }
 
-func (c *Client) RotateTriggerWebhookKeyByKey(workflowKey string, triggerKey string) (RotateWebhookKeyResponse, error) {
+func (c *Client) RotateTriggerWebhookKeyByKey(ctx context.Context, workflowKey string, triggerKey string) (RotateWebhookKeyResponse, error) {
 	var result RotateWebhookKeyResponse
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/triggers/by-key/" + url.PathEscape(triggerKey) + "/webhook-key/rotate"
-	return result, c.PostJSON(path, map[string]any{}, &result)
+	return result, c.PostJSON(ctx, path, map[string]any{}, &result)
 }
 
-func (c *Client) ListWorkflowRuns(workflowID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowRun], error) {
+func (c *Client) TriggerWebhookByKey(ctx context.Context, workflowKey string, triggerKey string, input any, overrides any) (TriggerWebhookResponse, error) {
+	var result TriggerWebhookResponse
+	payload := map[string]any{"input": input, "overrides": overrides}
+	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/triggers/by-key/" + url.PathEscape(triggerKey) + "/webhook"
+	return result, c.PostJSON(ctx, path, payload, &result)
+}
+
+func (c *Client) ListWorkflowRuns(ctx context.Context, workflowID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowRun], error) {
 	var result Paginated[WorkflowRun]
 	path := paginatedPath(fmt.Sprintf("/workflows/%s/runs", workflowID), page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) ListWorkflowRunsByKey(workflowKey string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowRun], error) {
+func (c *Client) ListWorkflowRunsByKey(ctx context.Context, workflowKey string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[WorkflowRun], error) {
 	var result Paginated[WorkflowRun]
 	path := paginatedPath("/workflows/by-key/"+url.PathEscape(workflowKey)+"/runs", page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) GetWorkflowRun(workflowID string, runID string) (WorkflowRun, error) {
+func (c *Client) GetWorkflowRun(ctx context.Context, workflowID string, runID string) (WorkflowRun, error) {
 	var result WorkflowRun
-	return result, c.GetJSON("/workflows/"+workflowID+"/runs/"+runID, &result)
+	return result, c.GetJSON(ctx, "/workflows/"+workflowID+"/runs/"+runID, &result)
 }
 
-func (c *Client) GetWorkflowRunByNumber(workflowKey string, runNumber int) (WorkflowRun, error) {
+func (c *Client) GetWorkflowRunByNumber(ctx context.Context, workflowKey string, runNumber int) (WorkflowRun, error) {
 	var result WorkflowRun
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/runs/" + strconv.Itoa(runNumber)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) ListStepRuns(workflowID string, runID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[StepRun], error) {
+func (c *Client) ListStepRuns(ctx context.Context, workflowID string, runID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[StepRun], error) {
 	var result Paginated[StepRun]
 	path := paginatedPath(fmt.Sprintf("/workflows/%s/runs/%s/steps", workflowID, runID), page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) ListStepRunsByWorkflowKeyAndRunNumber(workflowKey string, runNumber int, page int, pageSize int, sortBy string, sortOrder string) (Paginated[StepRun], error) {
+func (c *Client) ListStepRunsByWorkflowKeyAndRunNumber(ctx context.Context, workflowKey string, runNumber int, page int, pageSize int, sortBy string, sortOrder string) (Paginated[StepRun], error) {
 	var result Paginated[StepRun]
 	base := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/runs/" + strconv.Itoa(runNumber) + "/steps"
 	path := paginatedPath(base, page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) GetStepRun(workflowID string, runID string, stepID string) (StepRun, error) {
+func (c *Client) GetStepRun(ctx context.Context, workflowID string, runID string, stepID string) (StepRun, error) {
 	var result StepRun
-	return result, c.GetJSON("/workflows/"+workflowID+"/runs/"+runID+"/steps/"+stepID, &result)
+	return result, c.GetJSON(ctx, "/workflows/"+workflowID+"/runs/"+runID+"/steps/"+stepID, &result)
 }
 
-func (c *Client) GetStepRunByStepKey(workflowKey string, runNumber int, stepKey string) (StepRun, error) {
+func (c *Client) GetStepRunByStepKey(ctx context.Context, workflowKey string, runNumber int, stepKey string) (StepRun, error) {
 	var result StepRun
 	path := "/workflows/by-key/" + url.PathEscape(workflowKey) + "/runs/" + strconv.Itoa(runNumber) + "/steps/" + url.PathEscape(stepKey)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) ListEvents(workflowID string, triggerID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Event], error) {
+func (c *Client) ListEvents(ctx context.Context, workflowID string, triggerID string, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Event], error) {
 	var result Paginated[Event]
 	path := paginatedPath(fmt.Sprintf("/workflows/%s/triggers/%s/events", workflowID, triggerID), page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) GetEvent(workflowID string, triggerID string, eventID string) (Event, error) {
+func (c *Client) GetEvent(ctx context.Context, workflowID string, triggerID string, eventID string) (Event, error) {
 	var result Event
 	path := "/workflows/" + workflowID + "/triggers/" + triggerID + "/events/" + eventID
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) ListSecrets(page int, pageSize int, sortBy string, sortOrder string) (Paginated[Secret], error) {
+func (c *Client) ListSecrets(ctx context.Context, page int, pageSize int, sortBy string, sortOrder string) (Paginated[Secret], error) {
 	var result Paginated[Secret]
 	path := paginatedPath("/secrets", page, pageSize, sortBy, sortOrder)
-	return result, c.GetJSON(path, &result)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
 func paginatedPath(base string, page int, pageSize int, sortBy string, sortOrder string) string {
@@ -351,77 +392,126 @@ func paginatedPath(base string, page int, pageSize int, sortBy string, sortOrder
 	return base + "?" + q.Encode()
 }
 
-func (c *Client) GetSecret(id string) (Secret, error) {
+func (c *Client) GetSecret(ctx context.Context, id string) (Secret, error) {
 	var result Secret
-	return result, c.GetJSON("/secrets/"+id, &result)
+	return result, c.GetJSON(ctx, "/secrets/"+id, &result)
 }
 
-func (c *Client) CreateSecret(payload map[string]any) (Secret, error) {
+func (c *Client) CreateSecret(ctx context.Context, payload map[string]any) (Secret, error) {
 	var result Secret
-	return result, c.PostJSON("/secrets", payload, &result)
+	return result, c.PostJSON(ctx, "/secrets", payload, &result)
 }
 
-func (c *Client) UpdateSecret(id string, patch map[string]any) (Secret, error) {
+func (c *Client) UpdateSecret(ctx context.Context, id string, patch map[string]any) (Secret, error) {
 	var result Secret
-	return result, c.PatchJSON("/secrets/"+id, patch, &result)
+	return result, c.PatchJSON(ctx, "/secrets/"+id, patch, &result)
 }
 
-func (c *Client) DeleteSecret(id string) (Secret, error) {
+func (c *Client) DeleteSecret(ctx context.Context, id string) (Secret, error) {
 	var result Secret
-	return result, c.DeleteJSON("/secrets/"+id, &result)
+	return result, c.DeleteJSON(ctx, "/secrets/"+id, &result)
+}
+
+func (c *Client) ListAPITokens(ctx context.Context, page int, pageSize int, sortOrder string) (Paginated[APIToken], error) {
+	var result Paginated[APIToken]
+	path := paginatedPath("/api-tokens", page, pageSize, "createdAt", sortOrder)
+	return result, c.GetJSON(ctx, path, &result)
 }
 
-func (c *Client) GetHealth() (Health, error) {
+func (c *Client) GetAPIToken(ctx context.Context, id string) (APIToken, error) {
+	var result APIToken
+	return result, c.GetJSON(ctx, "/api-tokens/"+id, &result)
+}
+
+func (c *Client) CreateAPIToken(ctx context.Context, name string, scopes []string) (CreatedAPIToken, error) {
+	var result CreatedAPIToken
+	payload := map[string]any{"name": name}
+	if len(scopes) > 0 {
+		payload["scopes"] = scopes
+	}
+	return result, c.PostJSON(ctx, "/api-tokens", payload, &result)
+}
+
+func (c *Client) RevokeAPIToken(ctx context.Context, id string) (APIToken, error) {
+	var result APIToken
+	return result, c.PostJSON(ctx, "/api-tokens/"+id+"/revoke", nil, &result)
+}
+
+func (c *Client) GetHealth(ctx context.Context) (Health, error) {
 	var result Health
-	if err := c.GetJSON("/health", &result); err != nil {
+	if err := c.GetJSON(ctx, "/health", &result); err != nil {
 		return result, err
 	}
 	return result, nil
 }
 
-func (c *Client) WhoAmI() (WhoAmI, error) {
+func (c *Client) WhoAmI(ctx context.Context) (WhoAmI, error) {
 	var result WhoAmI
-	if err := c.GetJSON("/auth/whoami", &result); err != nil {
+	if err := c.GetJSON(ctx, "/auth/whoami", &result); err != nil {
 		return result, err
 	}
 	return result, nil
 }
 
-func (c *Client) doJSON(method string, path string, body any, out any) error {
+func (c *Client) doJSON(ctx context.Context, method string, path string, body any, out any) error {
 	fullURL, err := c.buildURL(path)
 	if err != nil {
 		return err
 	}
 
-	var bodyReader io.Reader
+	var payload []byte
 	if body != nil {
-		payload, err := json.Marshal(body)
+		payload, err = json.Marshal(body)
 		if err != nil {
 			return err
 		}
-		bodyReader = bytes.NewReader(payload)
 	}
 
-	req, err := http.NewRequest(method, fullURL, bodyReader)
+	token, err := c.AuthToken()
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Accept", "application/json")
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	if strings.TrimSpace(c.Token) != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	idempotent := isIdempotent(method)
+	policy := c.Retry
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
 	}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+	var resp *http.Response
+	var data []byte
+	for attempt := 1; ; attempt++ {
+		resp, data, err = c.send(ctx, method, fullURL, path, token, payload)
+		if err == nil && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			break
+		}
 
-	data, err := io.ReadAll(resp.Body)
+		statusCode := 0
+		var header http.Header
+		if resp != nil {
+			statusCode = resp.StatusCode
+			header = resp.Header
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		delay, retry := policy.retryDelay(attempt, idempotent, statusCode, header, err)
+		if !retry {
+			break
+		}
+		c.notifyRetry(ctx, RetryEvent{
+			Method:      method,
+			Path:        path,
+			Attempt:     attempt + 1,
+			MaxAttempts: policy.MaxAttempts,
+			Delay:       delay,
+			StatusCode:  statusCode,
+			Err:         err,
+		})
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return fmt.Errorf("%s %s: %w", method, path, sleepErr)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -436,12 +526,16 @@ func (c *Client) doJSON(method string, path string, body any, out any) error {
 		if len(snippet) > 200 {
 			snippet = snippet[:200] + "..."
 		}
-		return fmt.Errorf(
+		err := fmt.Errorf(
 			"unexpected content type %q (status %d): %s",
 			contentType,
 			resp.StatusCode,
 			snippet,
 		)
+		if class := lerrors.FromResponse(resp.StatusCode, ""); class != nil {
+			return fmt.Errorf("%w: %w", class, err)
+		}
+		return err
 	}
 
 	var env Envelope
@@ -451,8 +545,15 @@ func (c *Client) doJSON(method string, path string, body any, out any) error {
 
 	if !env.Ok {
 		if env.Error != nil {
+			env.Error.StatusCode = env.StatusCode
+			if env.Error.StatusCode == 0 {
+				env.Error.StatusCode = resp.StatusCode
+			}
 			return env.Error
 		}
+		if class := lerrors.FromResponse(resp.StatusCode, ""); class != nil {
+			return fmt.Errorf("request failed (status %d): %w", resp.StatusCode, class)
+		}
 		return errors.New("request failed")
 	}
 
@@ -465,6 +566,58 @@ func (c *Client) doJSON(method string, path string, body any, out any) error {
 	return nil
 }
 
+// send performs a single attempt under the per-request deadline and reads the
+// whole response body.
+func (c *Client) send(ctx context.Context, method string, fullURL string, path string, token string, payload []byte) (*http.Response, []byte, error) {
+	reqCtx := ctx
+	if c.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.RequestTimeout)
+		defer cancel()
+	}
+
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(reqCtx, method, fullURL, bodyReader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if strings.TrimSpace(token) != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, c.requestError(ctx, reqCtx, method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, c.requestError(ctx, reqCtx, method, path, err)
+	}
+	return resp, data, nil
+}
+
+// requestError reports cancellation by the caller and the per-request deadline
+// as plain context errors instead of the transport's wrapped URL error.
+func (c *Client) requestError(ctx context.Context, reqCtx context.Context, method string, path string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s %s: %w", method, path, ctx.Err())
+	}
+	if errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s %s: request timed out after %s: %w", method, path, c.RequestTimeout, context.DeadlineExceeded)
+	}
+	return lerrors.Network(err)
+}
+
 func (c *Client) buildURL(path string) (string, error) {
 	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
 		return path, nil
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+)
+
+func TestClientAppliesRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, "")
+	client.RequestTimeout = 50 * time.Millisecond
+	client.Retry = RetryPolicy{MaxAttempts: 1}
+
+	_, err := client.GetHealth(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Fatalf("expected request timeout, got %v", err)
+	}
+}
+
+func TestClientStopsWhenContextIsCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	started := time.Now()
+	_, err := NewClient(server.URL, "").ListWorkflows(ctx, 1, 10, "", "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if time.Since(started) > 5*time.Second {
+		t.Fatalf("request was not aborted promptly")
+	}
+}
+
+func TestClientClassifiesAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"ok":false,"statusCode":404,"error":{"code":"WORKFLOW_NOT_FOUND","message":"Workflow not found"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "").GetWorkflowByKey(context.Background(), "missing")
+	if !errors.Is(err, lerrors.ErrNotFound) || lerrors.ExitCode(err) != lerrors.ExitNotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	apiErr := AsAPIError(fmt.Errorf("lookup: %w", err))
+	if apiErr == nil || apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "WORKFLOW_NOT_FOUND" {
+		t.Fatalf("expected wrapped API error, got %#v", apiErr)
+	}
+}
+
+func TestClientClassifiesNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client := NewClient(url, "")
+	client.Retry = RetryPolicy{MaxAttempts: 1}
+	_, err := client.GetHealth(context.Background())
+	if !lerrors.IsNetwork(err) || lerrors.ExitCode(err) != lerrors.ExitNetwork {
+		t.Fatalf("expected network error, got %v", err)
+	}
+}
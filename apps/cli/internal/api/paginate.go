@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"iter"
+)
+
+const MaxPageSize = 100
+
+// PageFunc fetches a single 1-based page of a list endpoint.
+type PageFunc[T any] func(ctx context.Context, page int) (Paginated[T], error)
+
+type pageResult[T any] struct {
+	page Paginated[T]
+	err  error
+}
+
+// Pages yields every page of a list endpoint. The next page is fetched in the
+// background while the caller handles the current one; stopping the iteration
+// cancels the prefetch. Each call to the returned sequence starts over from the
+// first page, so it is safe to range over it from several goroutines.
+func Pages[T any](ctx context.Context, fetch PageFunc[T]) iter.Seq2[Paginated[T], error] {
+	return func(yield func(Paginated[T], error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan pageResult[T], 1)
+		go func() {
+			defer close(results)
+			for page := 1; ; page++ {
+				result, err := fetch(ctx, page)
+				select {
+				case results <- pageResult[T]{page: result, err: err}:
+				case <-ctx.Done():
+					return
+				}
+				if err != nil || !result.Pagination.HasNext || len(result.Items) == 0 {
+					return
+				}
+			}
+		}()
+
+		for result := range results {
+			if !yield(result.page, result.err) || result.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Paginate yields the items of every page in order, followed by at most one
+// error that ends the sequence.
+func Paginate[T any](ctx context.Context, fetch PageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range Pages(ctx, fetch) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All collects every item of a paginated list.
+func All[T any](ctx context.Context, fetch PageFunc[T]) ([]T, error) {
+	items := make([]T, 0)
+	for item, err := range Paginate(ctx, fetch) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
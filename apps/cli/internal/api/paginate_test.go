@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func numberPages(total int, pageSize int, calls *int32) PageFunc[int] {
+	return func(ctx context.Context, page int) (Paginated[int], error) {
+		atomic.AddInt32(calls, 1)
+		if err := ctx.Err(); err != nil {
+			return Paginated[int]{}, err
+		}
+		items := []int{}
+		for i := (page-1)*pageSize + 1; i <= page*pageSize && i <= total; i++ {
+			items = append(items, i)
+		}
+		return Paginated[int]{
+			Items:      items,
+			Pagination: Pagination{Page: page, PageSize: pageSize, Total: total, HasNext: page*pageSize < total},
+		}, nil
+	}
+}
+
+func TestAllCollectsEveryPage(t *testing.T) {
+	var calls int32
+	items, err := All(context.Background(), numberPages(7, 3, &calls))
+	if err != nil {
+		t.Fatalf("all: %v", err)
+	}
+	if len(items) != 7 || items[0] != 1 || items[6] != 7 {
+		t.Fatalf("unexpected items: %v", items)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 page fetches, got %d", calls)
+	}
+}
+
+func TestPaginateStopsPrefetchingOnBreak(t *testing.T) {
+	var calls int32
+	seen := 0
+	for item, err := range Paginate(context.Background(), numberPages(1000, 2, &calls)) {
+		if err != nil {
+			t.Fatalf("paginate: %v", err)
+		}
+		seen++
+		if item == 3 {
+			break
+		}
+	}
+	if seen != 3 {
+		t.Fatalf("expected 3 items, got %d", seen)
+	}
+	if got := atomic.LoadInt32(&calls); got > 3 {
+		t.Fatalf("expected at most one page of prefetch, got %d fetches", got)
+	}
+}
+
+func TestPaginateYieldsPageError(t *testing.T) {
+	boom := errors.New("boom")
+	fetch := func(ctx context.Context, page int) (Paginated[int], error) {
+		if page == 2 {
+			return Paginated[int]{}, boom
+		}
+		return Paginated[int]{Items: []int{page}, Pagination: Pagination{HasNext: true}}, nil
+	}
+
+	items := []int{}
+	var gotErr error
+	for item, err := range Paginate(context.Background(), fetch) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		items = append(items, item)
+	}
+	if !errors.Is(gotErr, boom) || len(items) != 1 {
+		t.Fatalf("expected one item then boom, got %v and %v", items, gotErr)
+	}
+}
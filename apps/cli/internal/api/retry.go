@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetryAttempts  = 3
+	DefaultRetryBaseDelay = 200 * time.Millisecond
+	DefaultRetryMaxDelay  = 5 * time.Second
+
+	maxRetryAfter = time.Minute
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultRetryAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+	}
+}
+
+type RetryEvent struct {
+	Method      string
+	Path        string
+	Attempt     int
+	MaxAttempts int
+	Delay       time.Duration
+	StatusCode  int
+	Err         error
+}
+
+type retryObserverKey struct{}
+
+// WithRetryObserver registers fn to be called before every retry of requests
+// made with the returned context.
+func WithRetryObserver(ctx context.Context, fn func(RetryEvent)) context.Context {
+	return context.WithValue(ctx, retryObserverKey{}, fn)
+}
+
+func (c *Client) notifyRetry(ctx context.Context, event RetryEvent) {
+	if c.OnRetry != nil {
+		c.OnRetry(event)
+	}
+	if fn, ok := ctx.Value(retryObserverKey{}).(func(RetryEvent)); ok && fn != nil {
+		fn(event)
+	}
+}
+
+// retryDelay decides whether a failed attempt is retried and how long to wait.
+// 429 responses were not processed by the server, so they are retried for any
+// method; other failures only for idempotent requests.
+func (p RetryPolicy) retryDelay(attempt int, idempotent bool, statusCode int, header http.Header, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+	case !idempotent:
+		return 0, false
+	case err != nil:
+	case statusCode == http.StatusBadGateway, statusCode == http.StatusServiceUnavailable, statusCode == http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+
+	if delay, ok := parseRetryAfter(header, time.Now()); ok {
+		return delay, true
+	}
+	return p.backoff(attempt), true
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = at.Sub(now)
+	} else {
+		return 0, false
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > maxRetryAfter {
+		delay = maxRetryAfter
+	}
+	return delay, true
+}
+
+// isIdempotent reports whether repeating method is safe. The server has no
+// idempotency keys, so POST and PATCH are never retried on failure.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func flakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"ok":false,"error":{"code":"UNAVAILABLE","message":"try again"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"workflowRunId":"r1","workflowRunNumber":3,"status":"QUEUED"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func testRetryClient(url string) *Client {
+	client := NewClient(url, "")
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	return client
+}
+
+func TestClientRetriesIdempotentRequests(t *testing.T) {
+	server, calls := flakyServer(t, 2, http.StatusServiceUnavailable, "")
+	client := testRetryClient(server.URL)
+
+	events := []RetryEvent{}
+	ctx := WithRetryObserver(context.Background(), func(event RetryEvent) { events = append(events, event) })
+	if _, err := client.GetWorkflowRunByNumber(ctx, "wf", 3); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if *calls != 3 || len(events) != 2 {
+		t.Fatalf("expected 3 calls and 2 retry events, got %d and %d", *calls, len(events))
+	}
+	if events[1].Attempt != 3 || events[1].MaxAttempts != 3 || events[1].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected retry event: %+v", events[1])
+	}
+}
+
+func TestClientDoesNotRetryPost(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusServiceUnavailable, "")
+	client := testRetryClient(server.URL)
+
+	if _, err := client.RunWorkflowByKey(context.Background(), "wf", nil, nil); err == nil {
+		t.Fatalf("expected failure without retries")
+	}
+	if *calls != 1 {
+		t.Fatalf("expected a single call, got %d", *calls)
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete} {
+		if !isIdempotent(method) {
+			t.Fatalf("expected %s to be idempotent", method)
+		}
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPatch} {
+		if isIdempotent(method) {
+			t.Fatalf("expected %s not to be retried", method)
+		}
+	}
+}
+
+func TestClientHonorsRetryAfterOnTooManyRequests(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusTooManyRequests, "0")
+	client := testRetryClient(server.URL)
+	client.Retry.BaseDelay = time.Hour
+
+	var delay time.Duration = -1
+	client.OnRetry = func(event RetryEvent) { delay = event.Delay }
+	result, err := client.RunWorkflowByKey(context.Background(), "wf", nil, nil)
+	if err != nil || result.WorkflowRunNumber != 3 {
+		t.Fatalf("expected POST to be retried after 429, got %+v, %v", result, err)
+	}
+	if *calls != 2 || delay != 0 {
+		t.Fatalf("expected Retry-After delay of 0 and 2 calls, got %s and %d", delay, *calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	header := http.Header{}
+	header.Set("Retry-After", now.Add(10*time.Second).Format(http.TimeFormat))
+	if delay, ok := parseRetryAfter(header, now); !ok || delay != 10*time.Second {
+		t.Fatalf("unexpected HTTP-date delay: %s %t", delay, ok)
+	}
+	header.Set("Retry-After", "3600")
+	if delay, ok := parseRetryAfter(header, now); !ok || delay != maxRetryAfter {
+		t.Fatalf("expected delay capped at %s, got %s", maxRetryAfter, delay)
+	}
+	header.Set("Retry-After", "soon")
+	if _, ok := parseRetryAfter(header, now); ok {
+		t.Fatalf("expected invalid Retry-After to be ignored")
+	}
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxTraceBody = 64 << 10
+	redacted     = "[REDACTED]"
+)
+
+type TraceLevel int
+
+const (
+	TraceOff TraceLevel = iota
+	TraceBasic
+	TraceHeaders
+	TraceBodies
+)
+
+// ParseTraceLevel accepts the values of --debug and LUNIE_DEBUG.
+func ParseTraceLevel(value string) (TraceLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "off":
+		return TraceOff, nil
+	case "1", "true", "on", "basic":
+		return TraceBasic, nil
+	case "headers":
+		return TraceHeaders, nil
+	case "body", "bodies":
+		return TraceBodies, nil
+	default:
+		return TraceOff, fmt.Errorf("invalid debug level %q (expected basic|headers|body)", value)
+	}
+}
+
+// TraceEntry describes one HTTP round trip. Headers and bodies are only set
+// at the matching trace level and are already redacted.
+type TraceEntry struct {
+	Time           time.Time
+	Method         string
+	URL            string
+	StatusCode     int
+	Status         string
+	Latency        time.Duration
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+	RequestBody    string
+	ResponseBody   string
+	Err            error
+}
+
+// EnableTrace reports every request made by the client to sink. Tracing wraps
+// the current transport, so several sinks can be stacked.
+func (c *Client) EnableTrace(level TraceLevel, sink func(TraceEntry)) {
+	if level == TraceOff || sink == nil {
+		return
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{}
+	}
+	base := c.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.HTTPClient.Transport = &traceTransport{base: base, level: level, sink: sink}
+}
+
+type traceTransport struct {
+	base  http.RoundTripper
+	level TraceLevel
+	sink  func(TraceEntry)
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := TraceEntry{Time: time.Now(), Method: req.Method, URL: req.URL.String()}
+	if t.level >= TraceHeaders {
+		entry.RequestHeader = redactHeader(req.Header)
+	}
+	if t.level >= TraceBodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			_ = body.Close()
+			entry.RequestBody = redactBody(data)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	entry.Latency = time.Since(entry.Time)
+	if err != nil {
+		entry.Err = err
+		t.sink(entry)
+		return nil, err
+	}
+
+	entry.StatusCode = resp.StatusCode
+	entry.Status = resp.Status
+	if t.level >= TraceHeaders {
+		entry.ResponseHeader = redactHeader(resp.Header)
+	}
+	if t.level >= TraceBodies && resp.Body != nil {
+		data, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if readErr != nil {
+			entry.Err = readErr
+		}
+		entry.ResponseBody = redactBody(data)
+	}
+	t.sink(entry)
+	return resp, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for name := range clone {
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization":
+			scheme, _, _ := strings.Cut(clone.Get(name), " ")
+			if strings.EqualFold(scheme, "Bearer") {
+				clone.Set(name, "Bearer "+redacted)
+			} else {
+				clone.Set(name, redacted)
+			}
+		case "Cookie", "Set-Cookie":
+			clone.Set(name, redacted)
+		}
+	}
+	return clone
+}
+
+// redactBody hides secret values and tokens in JSON bodies. The whole body is
+// redacted before the output is truncated to maxTraceBody bytes. Small non-JSON
+// bodies, such as a proxy's error page, are returned as-is; larger ones are
+// omitted since they cannot be redacted.
+func redactBody(data []byte) string {
+	var value any
+	if json.Unmarshal(data, &value) == nil {
+		if out, err := json.Marshal(redactJSON(value)); err == nil {
+			return truncateTraceBody(string(out))
+		}
+	}
+	if len(data) > maxTraceBody {
+		return fmt.Sprintf("[body omitted: %d bytes, not JSON]", len(data))
+	}
+	return string(data)
+}
+
+func truncateTraceBody(body string) string {
+	if len(body) <= maxTraceBody {
+		return body
+	}
+	return body[:maxTraceBody] + "...(truncated)"
+}
+
+func redactJSON(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			if isSensitiveField(key) {
+				if child != nil {
+					typed[key] = redacted
+				}
+				continue
+			}
+			typed[key] = redactJSON(child)
+		}
+	case []any:
+		for i, child := range typed {
+			typed[i] = redactJSON(child)
+		}
+	}
+	return value
+}
+
+// isSensitiveField matches secret values and credentials such as webhookKey
+// or apiToken, but not resource identifiers like key or workflowKey.
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	switch key {
+	case "value", "authorization":
+		return true
+	}
+	for _, suffix := range []string{"token", "secret", "password", "passphrase", "webhookkey", "apikey"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTraceWriter returns a sink that prints entries to w, one line per
+// request followed by headers and bodies when they were captured.
+func NewTraceWriter(w io.Writer) func(TraceEntry) {
+	var mu sync.Mutex
+	return func(entry TraceEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintln(w, "[debug] "+FormatTraceLine(entry))
+		writeTraceHeader(w, "> ", entry.RequestHeader)
+		if entry.RequestBody != "" {
+			fmt.Fprintf(w, "> %s\n", entry.RequestBody)
+		}
+		writeTraceHeader(w, "< ", entry.ResponseHeader)
+		if entry.ResponseBody != "" {
+			fmt.Fprintf(w, "< %s\n", entry.ResponseBody)
+		}
+	}
+}
+
+// FormatTraceLine summarises an entry as "15:04:05.000 GET url -> 200 (12ms)".
+func FormatTraceLine(entry TraceEntry) string {
+	outcome := entry.Status
+	if entry.Err != nil {
+		outcome = "error: " + entry.Err.Error()
+	} else if outcome == "" {
+		outcome = fmt.Sprintf("%d", entry.StatusCode)
+	}
+	return fmt.Sprintf(
+		"%s %s %s -> %s (%s)",
+		entry.Time.Format("15:04:05.000"),
+		entry.Method,
+		entry.URL,
+		outcome,
+		entry.Latency.Round(time.Millisecond),
+	)
+}
+
+func writeTraceHeader(w io.Writer, prefix string, header http.Header) {
+	if len(header) == 0 {
+		return
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
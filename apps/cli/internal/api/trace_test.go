@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTraceRedactsTokenAndSecretValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"id":"s1","name":"API_KEY","value":"plain-secret"}}`))
+	}))
+	defer server.Close()
+
+	var entries []TraceEntry
+	client := NewClient(server.URL, "super-token")
+	client.EnableTrace(TraceBodies, func(entry TraceEntry) { entries = append(entries, entry) })
+
+	secret, err := client.CreateSecret(context.Background(), map[string]any{"name": "API_KEY", "value": "plain-secret"})
+	if err != nil {
+		t.Fatalf("create secret: %v", err)
+	}
+	if secret.Name != "API_KEY" {
+		t.Fatalf("tracing must not consume the response body, got %+v", secret)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected one trace entry, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.StatusCode != http.StatusOK || entry.Method != http.MethodPost {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if got := entry.RequestHeader.Get("Authorization"); got != "Bearer [REDACTED]" {
+		t.Fatalf("expected redacted authorization, got %q", got)
+	}
+	for _, body := range []string{entry.RequestBody, entry.ResponseBody} {
+		if strings.Contains(body, "plain-secret") || !strings.Contains(body, `"value":"[REDACTED]"`) {
+			t.Fatalf("expected redacted secret value, got %s", body)
+		}
+	}
+}
+
+func TestTraceRedactsRotatedWebhookKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"id":"t1","key":"nightly","webhookKey":"whk_plain"}}`))
+	}))
+	defer server.Close()
+
+	var entries []TraceEntry
+	client := NewClient(server.URL, "super-token")
+	client.EnableTrace(TraceBodies, func(entry TraceEntry) { entries = append(entries, entry) })
+
+	if _, err := client.RotateTriggerWebhookKeyByKey(context.Background(), "sync", "nightly"); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	body := entries[0].ResponseBody
+	if strings.Contains(body, "whk_plain") || !strings.Contains(body, `"webhookKey":"[REDACTED]"`) {
+		t.Fatalf("expected redacted webhook key, got %s", body)
+	}
+	if !strings.Contains(body, `"key":"nightly"`) {
+		t.Fatalf("expected resource keys to stay readable, got %s", body)
+	}
+}
+
+func TestRedactBodyRedactsLargeBodiesBeforeTruncating(t *testing.T) {
+	items := make([]map[string]any, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		items = append(items, map[string]any{"name": "KEY", "value": "plain-secret", "padding": strings.Repeat("x", 40)})
+	}
+	data, err := json.Marshal(map[string]any{"items": items})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if len(data) <= maxTraceBody {
+		t.Fatalf("test body must exceed %d bytes, got %d", maxTraceBody, len(data))
+	}
+
+	got := redactBody(data)
+	if strings.Contains(got, "plain-secret") {
+		t.Fatal("expected large body to be redacted")
+	}
+	if !strings.HasSuffix(got, "...(truncated)") || len(got) > maxTraceBody+len("...(truncated)") {
+		t.Fatalf("expected redacted body to be truncated, got %d bytes", len(got))
+	}
+
+	raw := []byte("token=" + strings.Repeat("s", maxTraceBody))
+	if got := redactBody(raw); got != fmt.Sprintf("[body omitted: %d bytes, not JSON]", len(raw)) {
+		t.Fatalf("expected large non-JSON body to be omitted, got %.80q", got)
+	}
+	if got := redactBody([]byte("<html>bad gateway</html>")); got != "<html>bad gateway</html>" {
+		t.Fatalf("expected small non-JSON body as-is, got %q", got)
+	}
+}
+
+func TestTraceWriterPrintsOnlyRequestedDetail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"status":"ok"}}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(server.URL, "super-token")
+	client.EnableTrace(TraceBasic, NewTraceWriter(&buf))
+	if _, err := client.GetHealth(context.Background()); err != nil {
+		t.Fatalf("health: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "GET "+server.URL) || !strings.Contains(out, "-> 200 OK") {
+		t.Fatalf("expected request line, got %q", out)
+	}
+	if strings.Contains(out, "Authorization") || strings.Contains(out, "super-token") {
+		t.Fatalf("basic level must not print headers, got %q", out)
+	}
+}
+
+func TestParseTraceLevel(t *testing.T) {
+	cases := map[string]TraceLevel{"": TraceOff, "0": TraceOff, "1": TraceBasic, "true": TraceBasic, "headers": TraceHeaders, "BODY": TraceBodies}
+	for value, want := range cases {
+		got, err := ParseTraceLevel(value)
+		if err != nil || got != want {
+			t.Errorf("ParseTraceLevel(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseTraceLevel("verbose"); err == nil {
+		t.Fatalf("expected an error for an unknown level")
+	}
+}
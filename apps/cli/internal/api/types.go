@@ -1,5 +1,7 @@
 package api
 
+import "encoding/json"
+
 type Pagination struct {
 	Page       int    `json:"page"`
 	PageSize   int    `json:"pageSize"`
@@ -50,6 +52,14 @@ type RotateWebhookKeyResponse struct {
 	WebhookKey string `json:"webhookKey"`
 }
 
+// TriggerWebhookResponse is the webhook ingress result. The run fields are
+// only set when the status is accepted.
+type TriggerWebhookResponse struct {
+	Status            string `json:"status"`
+	WorkflowRunID     string `json:"workflowRunId,omitempty"`
+	WorkflowRunNumber int    `json:"workflowRunNumber,omitempty"`
+}
+
 type Event struct {
 	ID         string  `json:"id"`
 	TriggerID  string  `json:"triggerId"`
@@ -96,6 +106,21 @@ type StepRun struct {
 	FinishedAt      *string `json:"finishedAt"`
 }
 
+func (s StepRun) LogText() string {
+	switch v := s.Logs.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		encoded, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	}
+}
+
 type Secret struct {
 	ID          string  `json:"id"`
 	Name        string  `json:"name"`
@@ -105,6 +130,32 @@ type Secret struct {
 	UpdatedAt   string  `json:"updatedAt"`
 }
 
+type APIToken struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  string   `json:"createdAt"`
+	LastUsedAt *string  `json:"lastUsedAt"`
+	RevokedAt  *string  `json:"revokedAt"`
+}
+
+// APITokenScopes are the scopes the server enforces. A scoped token needs
+// "<resource>:read" for GET requests and "<resource>:write" for the rest; a
+// token without scopes has full access.
+var APITokenScopes = []string{
+	"workflows:read", "workflows:write",
+	"runs:read", "runs:write",
+	"triggers:read", "triggers:write",
+	"secrets:read", "secrets:write",
+	"tokens:read", "tokens:write",
+}
+
+// CreatedAPIToken carries the raw token, which the server returns only once.
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token"`
+}
+
 type WhoAmI struct {
 	ID     string   `json:"id"`
 	Name   string   `json:"name"`
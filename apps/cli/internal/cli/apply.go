@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/gentij/lunie/apps/cli/internal/manifest"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const builtinManualTriggerKey = "manual"
+
+var applyFile string
+var applyDryRun bool
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply workflow manifests so the server matches them",
+	RunE:  applyManifests,
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "Manifest file or directory (YAML or JSON)")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print the plan without changing anything")
+	_ = applyCmd.MarkFlagRequired("file")
+}
+
+type applyChange struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Detail   string `json:"detail,omitempty"`
+
+	run func(ctx context.Context, client *api.Client) error
+}
+
+type serverWorkflowState struct {
+	Workflow      *api.Workflow
+	LatestVersion *api.WorkflowVersion
+	Triggers      []api.Trigger
+}
+
+func applyManifests(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	workflows, err := manifest.Load(applyFile)
+	if err != nil {
+		return err
+	}
+
+	if err := checkManifestSecrets(cmd.Context(), ctx.Client, workflows); err != nil {
+		return err
+	}
+
+	changes := []applyChange{}
+	for _, workflow := range workflows {
+		state, err := loadServerWorkflowState(cmd.Context(), ctx.Client, workflow.Key)
+		if err != nil {
+			return err
+		}
+		planned, err := planWorkflowChanges(workflow, state)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, planned...)
+	}
+
+	if !applyDryRun {
+		for i := range changes {
+			if changes[i].run == nil {
+				continue
+			}
+			if err := changes[i].run(cmd.Context(), ctx.Client); err != nil {
+				return fmt.Errorf("%s %s: %w", changes[i].Action, changes[i].Resource, err)
+			}
+		}
+	}
+
+	return printApplyChanges(ctx, changes)
+}
+
+func loadServerWorkflowState(ctx context.Context, client *api.Client, workflowKey string) (serverWorkflowState, error) {
+	state := serverWorkflowState{}
+
+	workflow, err := client.GetWorkflowByKey(ctx, workflowKey)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return state, nil
+		}
+		return state, err
+	}
+	state.Workflow = &workflow
+
+	versions, err := client.ListWorkflowVersionsByKey(ctx, workflowKey, 1, 1, "version", "desc")
+	if err != nil {
+		return state, err
+	}
+	if len(versions.Items) > 0 {
+		state.LatestVersion = &versions.Items[0]
+	}
+
+	state.Triggers, err = api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.Trigger], error) {
+		return client.ListTriggersByWorkflowKey(ctx, workflowKey, page, api.MaxPageSize, "createdAt", "asc")
+	})
+	return state, err
+}
+
+// keyConflictError reports a resource the server created under another key,
+// usually because a soft-deleted one still holds the slug. The stray resource
+// has already been deleted unless deleteErr says otherwise.
+func keyConflictError(got string, want string, deleteErr error) error {
+	err := lerrors.Conflict(fmt.Errorf("server assigned key %q instead of %q; the key is still taken on the server", got, want))
+	if deleteErr != nil {
+		return fmt.Errorf("%w; deleting %q also failed: %v", err, got, deleteErr)
+	}
+	return err
+}
+
+func planWorkflowChanges(workflow manifest.Workflow, state serverWorkflowState) ([]applyChange, error) {
+	changes := []applyChange{}
+	workflowRef := "workflow/" + workflow.Key
+	definition := workflow.Definition
+
+	existingTriggers := state.Triggers
+	if state.Workflow == nil {
+		if slug := manifest.Slugify(workflow.DisplayName()); slug != workflow.Key {
+			return nil, fmt.Errorf("%s: workflow name %q would create key %q, not %q", workflow.Source, workflow.DisplayName(), slug, workflow.Key)
+		}
+		changes = append(changes, applyChange{
+			Action:   "create",
+			Resource: workflowRef,
+			Detail:   "version 1",
+			run: func(ctx context.Context, client *api.Client) error {
+				created, err := client.CreateWorkflow(ctx, workflow.DisplayName(), definition)
+				if err != nil {
+					return err
+				}
+				if created.Key != workflow.Key {
+					_, deleteErr := client.DeleteWorkflow(ctx, created.ID)
+					return keyConflictError(created.Key, workflow.Key, deleteErr)
+				}
+				return nil
+			},
+		})
+		if !workflow.Active() {
+			changes = append(changes, applyChange{
+				Action:   "update",
+				Resource: workflowRef,
+				Detail:   "isActive=false",
+				run: func(ctx context.Context, client *api.Client) error {
+					_, err := client.UpdateWorkflowByKey(ctx, workflow.Key, map[string]any{"isActive": false})
+					return err
+				},
+			})
+		}
+		existingTriggers = []api.Trigger{{Key: builtinManualTriggerKey, Type: "MANUAL", Name: stringPtr("Manual"), IsActive: true, Config: map[string]any{}}}
+	} else {
+		patch := map[string]any{}
+		details := []string{}
+		if state.Workflow.Name != workflow.DisplayName() {
+			patch["name"] = workflow.DisplayName()
+			details = append(details, fmt.Sprintf("name=%q", workflow.DisplayName()))
+		}
+		if state.Workflow.IsActive != workflow.Active() {
+			patch["isActive"] = workflow.Active()
+			details = append(details, fmt.Sprintf("isActive=%t", workflow.Active()))
+		}
+		if len(patch) > 0 {
+			changes = append(changes, applyChange{
+				Action:   "update",
+				Resource: workflowRef,
+				Detail:   strings.Join(details, " "),
+				run: func(ctx context.Context, client *api.Client) error {
+					_, err := client.UpdateWorkflowByKey(ctx, workflow.Key, patch)
+					return err
+				},
+			})
+		}
+
+		if state.LatestVersion == nil || !manifest.Equal(manifest.ServerDefinition(state.LatestVersion.Definition), manifest.ServerDefinition(definition)) {
+			next := 1
+			if state.LatestVersion != nil {
+				next = state.LatestVersion.Version + 1
+			}
+			changes = append(changes, applyChange{
+				Action:   "create",
+				Resource: "version/" + workflow.Key,
+				Detail:   fmt.Sprintf("version %d", next),
+				run: func(ctx context.Context, client *api.Client) error {
+					_, err := client.CreateWorkflowVersionByKey(ctx, workflow.Key, definition)
+					return err
+				},
+			})
+		} else {
+			changes = append(changes, applyChange{
+				Action:   "unchanged",
+				Resource: "version/" + workflow.Key,
+				Detail:   fmt.Sprintf("version %d", state.LatestVersion.Version),
+			})
+		}
+	}
+
+	triggerChanges, err := planTriggerChanges(workflow, existingTriggers)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, triggerChanges...), nil
+}
+
+func planTriggerChanges(workflow manifest.Workflow, existing []api.Trigger) ([]applyChange, error) {
+	changes := []applyChange{}
+	byKey := map[string]api.Trigger{}
+	for _, trigger := range existing {
+		byKey[trigger.Key] = trigger
+	}
+
+	declared := map[string]bool{}
+	for _, desired := range workflow.Triggers {
+		declared[desired.Key] = true
+		ref := "trigger/" + workflow.Key + "/" + desired.Key
+
+		current, ok := byKey[desired.Key]
+		if !ok {
+			if slug := manifest.Slugify(desired.DisplayName()); slug != desired.Key {
+				return nil, fmt.Errorf("%s: trigger name %q would create key %q, not %q", workflow.Source, desired.DisplayName(), slug, desired.Key)
+			}
+			changes = append(changes, applyChange{
+				Action:   "create",
+				Resource: ref,
+				Detail:   desired.Type,
+				run: func(ctx context.Context, client *api.Client) error {
+					created, err := client.CreateTriggerByWorkflowKey(ctx, workflow.Key, map[string]any{
+						"type":     desired.Type,
+						"name":     desired.DisplayName(),
+						"isActive": desired.Active(),
+						"config":   desired.ConfigValue(),
+					})
+					if err != nil {
+						return err
+					}
+					if created.Key != desired.Key {
+						_, deleteErr := client.DeleteTrigger(ctx, created.WorkflowID, created.ID)
+						return keyConflictError(created.Key, desired.Key, deleteErr)
+					}
+					return nil
+				},
+			})
+			continue
+		}
+
+		if !strings.EqualFold(current.Type, desired.Type) {
+			return nil, fmt.Errorf("%s: trigger %q is %s on the server; changing it to %s requires deleting it first", workflow.Source, desired.Key, current.Type, desired.Type)
+		}
+
+		patch := map[string]any{}
+		details := []string{}
+		if triggerNameValue(current.Name) != desired.DisplayName() {
+			patch["name"] = desired.DisplayName()
+			details = append(details, fmt.Sprintf("name=%q", desired.DisplayName()))
+		}
+		if current.IsActive != desired.Active() {
+			patch["isActive"] = desired.Active()
+			details = append(details, fmt.Sprintf("isActive=%t", desired.Active()))
+		}
+		if !manifest.Equal(current.Config, desired.ConfigValue()) {
+			patch["config"] = desired.ConfigValue()
+			details = append(details, "config")
+		}
+		if len(patch) == 0 {
+			changes = append(changes, applyChange{Action: "unchanged", Resource: ref})
+			continue
+		}
+		changes = append(changes, applyChange{
+			Action:   "update",
+			Resource: ref,
+			Detail:   strings.Join(details, " "),
+			run: func(ctx context.Context, client *api.Client) error {
+				_, err := client.UpdateTriggerByKey(ctx, workflow.Key, desired.Key, patch)
+				return err
+			},
+		})
+	}
+
+	for _, trigger := range existing {
+		if declared[trigger.Key] || !trigger.IsActive {
+			continue
+		}
+		if trigger.Key == builtinManualTriggerKey && strings.EqualFold(trigger.Type, "MANUAL") {
+			continue
+		}
+		key := trigger.Key
+		changes = append(changes, applyChange{
+			Action:   "deactivate",
+			Resource: "trigger/" + workflow.Key + "/" + key,
+			Detail:   "not in manifest",
+			run: func(ctx context.Context, client *api.Client) error {
+				_, err := client.UpdateTriggerByKey(ctx, workflow.Key, key, map[string]any{"isActive": false})
+				return err
+			},
+		})
+	}
+
+	return changes, nil
+}
+
+func checkManifestSecrets(ctx context.Context, client *api.Client, workflows []manifest.Workflow) error {
+	required := map[string]bool{}
+	for _, workflow := range workflows {
+		for _, name := range workflow.Secrets {
+			required[name] = true
+		}
+	}
+	if len(required) == 0 {
+		return nil
+	}
+
+	secrets := api.Paginate(ctx, func(ctx context.Context, page int) (api.Paginated[api.Secret], error) {
+		return client.ListSecrets(ctx, page, api.MaxPageSize, "createdAt", "desc")
+	})
+	for item, err := range secrets {
+		if err != nil {
+			return err
+		}
+		delete(required, item.Name)
+	}
+
+	if len(required) == 0 {
+		return nil
+	}
+	missing := make([]string, 0, len(required))
+	for name := range required {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("missing secrets referenced by manifests: %s", strings.Join(missing, ", "))
+}
+
+func printApplyChanges(ctx *Context, changes []applyChange) error {
+	if IsStructured(ctx) {
+		return output.Print(map[string]any{"dryRun": applyDryRun, "changes": changes})
+	}
+
+	if ctx.Quiet {
+		for _, change := range changes {
+			if change.Action != "unchanged" {
+				fmt.Fprintln(os.Stdout, change.Resource)
+			}
+		}
+		return nil
+	}
+
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, []string{change.Action, change.Resource, change.Detail})
+	}
+	if err := output.PrintListTable([]string{"ACTION", "RESOURCE", "DETAIL"}, rows); err != nil {
+		return err
+	}
+	if applyDryRun {
+		fmt.Fprintln(os.Stdout, "Dry run: no changes were applied.")
+	}
+	return nil
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/gentij/lunie/apps/cli/internal/manifest"
+)
+
+func TestPlanWorkflowChangesForNewWorkflow(t *testing.T) {
+	workflow := manifest.Workflow{
+		Key:        "sync-crm",
+		Name:       "Sync CRM",
+		Definition: map[string]any{"steps": []any{}},
+		Triggers:   []manifest.Trigger{{Key: "nightly", Type: "CRON", Config: map[string]any{"cron": "0 2 * * *"}}},
+	}
+
+	changes, err := planWorkflowChanges(workflow, serverWorkflowState{})
+	if err != nil {
+		t.Fatalf("expected plan, got %v", err)
+	}
+	got := changeSummary(changes)
+	want := []string{"create workflow/sync-crm", "create trigger/sync-crm/nightly"}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected plan: %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected plan: %v", got)
+		}
+	}
+}
+
+func TestPlanWorkflowChangesSkipsUnchangedDefinition(t *testing.T) {
+	name := "Nightly"
+	workflow := manifest.Workflow{
+		Key:        "sync-crm",
+		Name:       "Sync CRM",
+		Definition: map[string]any{"steps": []any{}},
+		Triggers:   []manifest.Trigger{{Key: "nightly", Type: "CRON", Name: "Nightly", Config: map[string]any{"cron": "0 3 * * *"}}},
+	}
+	state := serverWorkflowState{
+		Workflow:      &api.Workflow{Key: "sync-crm", Name: "Sync CRM", IsActive: true},
+		LatestVersion: &api.WorkflowVersion{Version: 4, Definition: map[string]any{"steps": []any{}}},
+		Triggers: []api.Trigger{
+			{Key: "manual", Type: "MANUAL", IsActive: true},
+			{Key: "nightly", Type: "CRON", Name: &name, IsActive: true, Config: map[string]any{"cron": "0 2 * * *"}},
+			{Key: "legacy", Type: "WEBHOOK", IsActive: true},
+		},
+	}
+
+	changes, err := planWorkflowChanges(workflow, state)
+	if err != nil {
+		t.Fatalf("expected plan, got %v", err)
+	}
+	got := changeSummary(changes)
+	want := []string{"unchanged version/sync-crm", "update trigger/sync-crm/nightly", "deactivate trigger/sync-crm/legacy"}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected plan: %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected plan: %v", got)
+		}
+	}
+}
+
+func TestPlanWorkflowChangesRejectsTriggerTypeChange(t *testing.T) {
+	workflow := manifest.Workflow{
+		Key:        "sync-crm",
+		Definition: map[string]any{},
+		Triggers:   []manifest.Trigger{{Key: "inbound", Type: "CRON"}},
+	}
+	state := serverWorkflowState{
+		Workflow:      &api.Workflow{Key: "sync-crm", Name: "sync-crm", IsActive: true},
+		LatestVersion: &api.WorkflowVersion{Version: 1, Definition: map[string]any{}},
+		Triggers:      []api.Trigger{{Key: "inbound", Type: "WEBHOOK", IsActive: true}},
+	}
+
+	if _, err := planWorkflowChanges(workflow, state); err == nil {
+		t.Fatal("expected trigger type change to be rejected")
+	}
+}
+
+func changeSummary(changes []applyChange) []string {
+	summary := make([]string, 0, len(changes))
+	for _, change := range changes {
+		summary = append(summary, change.Action+" "+change.Resource)
+	}
+	return summary
+}
+
+func TestPlanWorkflowChangesDeletesWorkflowCreatedUnderAnotherKey(t *testing.T) {
+	deleted := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/workflows":
+			_, _ = w.Write([]byte(`{"ok":true,"data":{"id":"wf_2","key":"sync-crm-2","name":"Sync CRM"}}`))
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Path
+			_, _ = w.Write([]byte(`{"ok":true,"data":{"id":"wf_2","key":"sync-crm-2"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	workflow := manifest.Workflow{Key: "sync-crm", Name: "Sync CRM", Definition: map[string]any{"steps": []any{}}}
+	changes, err := planWorkflowChanges(workflow, serverWorkflowState{})
+	if err != nil {
+		t.Fatalf("expected plan, got %v", err)
+	}
+
+	err = changes[0].run(context.Background(), api.NewClient(server.URL, "token"))
+	if !errors.Is(err, lerrors.ErrConflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	if deleted != "/workflows/wf_2" {
+		t.Fatalf("expected the stray workflow to be deleted, got %q", deleted)
+	}
+}
+
+func TestPlanWorkflowChangesIgnoresKeysTheServerDrops(t *testing.T) {
+	workflow := manifest.Workflow{
+		Key:        "sync-crm",
+		Name:       "Sync CRM",
+		Definition: map[string]any{"description": "local note"},
+	}
+	state := serverWorkflowState{
+		Workflow:      &api.Workflow{Key: "sync-crm", Name: "Sync CRM", IsActive: true},
+		LatestVersion: &api.WorkflowVersion{Version: 2, Definition: map[string]any{"steps": []any{}}},
+		Triggers:      []api.Trigger{{Key: "manual", Type: "MANUAL", IsActive: true}},
+	}
+
+	changes, err := planWorkflowChanges(workflow, state)
+	if err != nil {
+		t.Fatalf("expected plan, got %v", err)
+	}
+	if got := changeSummary(changes); len(got) != 1 || got[0] != "unchanged version/sync-crm" {
+		t.Fatalf("expected no new version, got %v", got)
+	}
+}
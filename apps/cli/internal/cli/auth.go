@@ -23,10 +23,15 @@ func init() {
 		Use:   "login",
 		Short: "Login with an API token",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, path, err := loadConfig(cmd.Flags().Changed("server"))
+			stored, path, err := loadConfigFile()
 			if err != nil {
 				return err
 			}
+			resolved, err := resolveConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			name := resolved.Context.Value
 
 			token := strings.TrimSpace(authToken)
 			if token == "" {
@@ -52,39 +57,51 @@ func init() {
 				return fmt.Errorf("token is required")
 			}
 
-			cfg.Token = token
-			cfg.ServerURL = resolveServerURL(
-				cfg.ServerURL,
-				serverURL,
-				cmd.Flags().Changed("server"),
-			)
-
-			if err := saveConfig(path, cfg); err != nil {
+			store, err := selectCredentialStore(cmd.Flags(), stored)
+			if err != nil {
+				return err
+			}
+			if err := saveToken(&stored, path, name, resolved.Server.Value, token, store); err != nil {
+				return err
+			}
+			if err := saveConfig(path, stored); err != nil {
 				return err
 			}
 
-			fmt.Printf("Saved credentials to %s\n", path)
+			fmt.Printf("Saved token%s in the %s store (config: %s)\n", forContext(name), store, path)
 			return nil
 		},
 	}
 	loginCmd.Flags().StringVar(&authToken, "token", "", "API token")
+	addCredentialStoreFlag(loginCmd)
 
 	logoutCmd := &cobra.Command{
 		Use:   "logout",
 		Short: "Clear saved credentials",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, path, err := loadConfig(cmd.Flags().Changed("server"))
+			stored, path, err := loadConfigFile()
 			if err != nil {
 				return err
 			}
+			name := activeContextName(stored)
+			if _, ok := stored.Contexts[name]; name != "" && !ok {
+				return fmt.Errorf("context %q not found", name)
+			}
+			store, err := eraseToken(stored, path, name)
+			if err != nil {
+				return err
+			}
+			stored.SetCredentials(name, contextServer(stored, name), "", "")
 
-			cfg.Token = ""
-
-			if err := saveConfig(path, cfg); err != nil {
+			if err := saveConfig(path, stored); err != nil {
 				return err
 			}
 
-			fmt.Printf("Cleared credentials in %s\n", path)
+			if store == "" {
+				fmt.Printf("No token saved%s\n", forContext(name))
+				return nil
+			}
+			fmt.Printf("Removed token%s from the %s store (config: %s)\n", forContext(name), store, path)
 			return nil
 		},
 	}
@@ -93,14 +110,24 @@ func init() {
 		Use:   "status",
 		Short: "Show auth status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, path, err := loadConfig(cmd.Flags().Changed("server"))
+			resolved, err := resolveConfig(cmd.Flags())
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("Config: %s\n", path)
-			fmt.Printf("Server: %s\n", cfg.ServerURL)
-			fmt.Printf("Token:  %s\n", tokenStatus(cfg.Token))
+			fmt.Printf("Config:  %s\n", resolved.Path)
+			fmt.Printf("Context: %s\n", contextLabel(resolved.Context.Value))
+			fmt.Printf("Server:  %s (%s)\n", resolved.Server.Value, resolved.Server.Source)
+			if resolved.Token.Value == "" && resolved.Context.Value != "" {
+				fmt.Printf("Token:   not logged in%s\n", forContext(resolved.Context.Value))
+			} else {
+				fmt.Printf("Token:   %s (%s)\n", tokenStatus(resolved.Token.Value), resolved.Token.Source)
+			}
+			if resolved.TokenError != nil {
+				fmt.Printf("Store:   %s (%v)\n", resolved.TokenStore, resolved.TokenError)
+			} else if resolved.TokenStore != "" {
+				fmt.Printf("Store:   %s\n", resolved.TokenStore)
+			}
 			return nil
 		},
 	}
@@ -113,7 +140,11 @@ func init() {
 			if ctx == nil {
 				return fmt.Errorf("missing context")
 			}
-			if strings.TrimSpace(ctx.Config.Token) == "" {
+			token, err := ctx.Client.AuthToken()
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(token) == "" {
 				return fmt.Errorf("token not set")
 			}
 
@@ -122,12 +153,12 @@ func init() {
 				Name   string   `json:"name"`
 				Scopes []string `json:"scopes"`
 			}
-			if err := ctx.Client.GetJSON("/auth/whoami", &result); err != nil {
+			if err := ctx.Client.GetJSON(cmd.Context(), "/auth/whoami", &result); err != nil {
 				return err
 			}
 
-			if IsJSON(ctx) {
-				return output.PrintJSON(result)
+			if IsStructured(ctx) {
+				return output.Print(result)
 			}
 			if ctx.Quiet {
 				fmt.Fprintln(os.Stdout, result.ID)
@@ -157,3 +188,17 @@ func tokenStatus(token string) string {
 
 	return "set"
 }
+
+func forContext(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " for context " + name
+}
+
+func contextLabel(name string) string {
+	if name == "" {
+		return "(none)"
+	}
+	return name
+}
@@ -2,37 +2,264 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gentij/lunie/apps/cli/internal/api"
 	"github.com/gentij/lunie/apps/cli/internal/config"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/spf13/pflag"
 )
 
-func loadConfig(serverFlagChanged bool) (config.Config, string, error) {
+const (
+	serverEnv  = "LUNIE_SERVER"
+	tokenEnv   = "LUNIE_TOKEN"
+	outputEnv  = "LUNIE_OUTPUT"
+	contextEnv = "LUNIE_CONTEXT"
+	noColorEnv = "LUNIE_NO_COLOR"
+)
+
+// configValue is a resolved setting and where it came from.
+type configValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// resolvedConfig is the effective configuration. Each setting is taken from
+// the first of: flag, environment variable, active context, config file,
+// default.
+type resolvedConfig struct {
+	Config  config.Config
+	Path    string
+	Context configValue
+	Server  configValue
+	Token   configValue
+	Output  configValue
+	NoColor configValue
+	Theme   configValue
+	// TokenStore names where the token was read from: a credential store,
+	// "env" or empty when no token is set.
+	TokenStore string
+	// TokenError is set when the configured credential store could not be
+	// read; the token is then empty.
+	TokenError error
+
+	stored config.Config
+}
+
+// resolveConfig resolves every setting, including the token from the
+// credential store.
+func resolveConfig(flags *pflag.FlagSet) (resolvedConfig, error) {
+	resolved, err := resolveSettings(flags)
+	if err != nil {
+		return resolvedConfig{}, err
+	}
+	resolved.loadToken()
+	return resolved, nil
+}
+
+// resolveSettings resolves every setting but leaves a stored token unread, so
+// commands that never call the API work with a locked credential store.
+func resolveSettings(flags *pflag.FlagSet) (resolvedConfig, error) {
+	stored, path, err := loadConfigFile()
+	if err != nil {
+		return resolvedConfig{}, err
+	}
+
+	resolved := resolvedConfig{Path: path, stored: stored}
+	resolved.Context = firstValue(
+		flagValue(flags, "context", contextName),
+		envValue(contextEnv),
+		configValue{Value: stored.CurrentContext, Source: "config file"},
+	)
+	cfg, err := stored.WithContext(resolved.Context.Value)
+	if err != nil {
+		return resolvedConfig{}, err
+	}
+	layer := stored.Contexts[resolved.Context.Value]
+	layerSource := "context " + resolved.Context.Value
+
+	resolved.Server = firstValue(
+		flagValue(flags, "server", serverURL),
+		envValue(serverEnv),
+		configValue{Value: layer.ServerURL, Source: layerSource},
+		configValue{Value: stored.ServerURL, Source: "config file"},
+		configValue{Value: defaultServerURL, Source: "default"},
+	)
+	resolved.Token = envValue(tokenEnv)
+	if resolved.Token.Value != "" {
+		resolved.TokenStore = "env"
+	}
+	resolved.Output = resolveOutputValue(flags, layer, layerSource, stored)
+	resolved.Theme = firstValue(
+		configValue{Value: layer.Theme, Source: layerSource},
+		configValue{Value: stored.Theme, Source: "config file"},
+		configValue{Value: "lunie", Source: "default"},
+	)
+	resolved.NoColor, err = resolveNoColorValue(flags)
+	if err != nil {
+		return resolvedConfig{}, err
+	}
+
+	cfg.ServerURL = resolved.Server.Value
+	cfg.Token = resolved.Token.Value
+	cfg.Output = resolved.Output.Value
+	resolved.Config = cfg
+	return resolved, nil
+}
+
+// loadToken reads the token from the configured credential store unless one
+// was already set through the environment.
+func (r *resolvedConfig) loadToken() {
+	if r.TokenStore == "env" {
+		return
+	}
+	token, store, owner, err := readToken(r.stored, r.Path, r.Context.Value)
+	source := "config file"
+	if owner != "" {
+		source = "context " + owner
+	}
+	r.Token = firstValue(configValue{Value: token, Source: source})
+	r.TokenStore = store
+	r.TokenError = err
+	r.Config.Token = token
+}
+
+// loadConfig returns the effective config and the config file path.
+func loadConfig(flags *pflag.FlagSet) (config.Config, string, error) {
+	resolved, err := resolveConfig(flags)
+	if err != nil {
+		return config.Config{}, config.ResolvePath(configPath), err
+	}
+	return resolved.Config, resolved.Path, nil
+}
+
+func resolveOutputValue(flags *pflag.FlagSet, layer config.ContextConfig, layerSource string, stored config.Config) configValue {
+	return firstValue(
+		flagValue(flags, "output", outputMode),
+		envValue(outputEnv),
+		configValue{Value: layer.Output, Source: layerSource},
+		configValue{Value: stored.Output, Source: "config file"},
+		configValue{Value: "table", Source: "default"},
+	)
+}
+
+// resolveNoColorValue also honours the NO_COLOR convention (any value).
+func resolveNoColorValue(flags *pflag.FlagSet) (configValue, error) {
+	if flags != nil && flags.Changed("no-color") {
+		return configValue{Value: strconv.FormatBool(noColor), Source: "flag --no-color"}, nil
+	}
+	if raw := strings.TrimSpace(os.Getenv(noColorEnv)); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return configValue{}, lerrors.Usage(fmt.Errorf("invalid %s: %q", noColorEnv, raw))
+		}
+		return configValue{Value: strconv.FormatBool(value), Source: "env " + noColorEnv}, nil
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return configValue{Value: "true", Source: "env NO_COLOR"}, nil
+	}
+	return configValue{Value: "false", Source: "default"}, nil
+}
+
+func flagValue(flags *pflag.FlagSet, name string, value string) configValue {
+	if flags == nil || !flags.Changed(name) {
+		return configValue{}
+	}
+	return configValue{Value: strings.TrimSpace(value), Source: "flag --" + name}
+}
+
+func envValue(name string) configValue {
+	return configValue{Value: strings.TrimSpace(os.Getenv(name)), Source: "env " + name}
+}
+
+func firstValue(values ...configValue) configValue {
+	for _, value := range values {
+		if strings.TrimSpace(value.Value) != "" {
+			return configValue{Value: strings.TrimSpace(value.Value), Source: value.Source}
+		}
+	}
+	return configValue{Source: "unset"}
+}
+
+// loadConfigFile returns the config file as stored, for commands that write it
+// back without flattening the active context into the top-level values.
+func loadConfigFile() (config.Config, string, error) {
 	path := config.ResolvePath(configPath)
 	cfg, err := config.Load(path)
 	if err != nil {
 		return config.Config{}, path, err
 	}
-
-	cfg.ServerURL = resolveServerURL(cfg.ServerURL, serverURL, serverFlagChanged)
-
 	return cfg, path, nil
 }
 
-func resolveServerURL(configServerURL string, flagServerURL string, flagChanged bool) string {
-	if flagChanged {
-		return strings.TrimSpace(flagServerURL)
+// activeContextName prefers --context, then LUNIE_CONTEXT, then the file's
+// currentContext.
+func activeContextName(cfg config.Config) string {
+	if name := strings.TrimSpace(contextName); name != "" {
+		return name
+	}
+	if name := strings.TrimSpace(os.Getenv(contextEnv)); name != "" {
+		return name
+	}
+	return cfg.CurrentContext
+}
+
+// resolveRetryPolicy starts from the defaults, applies the config file and
+// then any retry flags set explicitly on the command line.
+func resolveRetryPolicy(cfg *config.RetryConfig, flags *pflag.FlagSet) (api.RetryPolicy, error) {
+	policy := api.DefaultRetryPolicy()
+
+	if cfg != nil {
+		if cfg.MaxAttempts != 0 {
+			policy.MaxAttempts = cfg.MaxAttempts
+		}
+		for _, field := range []struct {
+			name   string
+			value  string
+			target *time.Duration
+		}{
+			{"retry.baseDelay", cfg.BaseDelay, &policy.BaseDelay},
+			{"retry.maxDelay", cfg.MaxDelay, &policy.MaxDelay},
+		} {
+			if strings.TrimSpace(field.value) == "" {
+				continue
+			}
+			delay, err := time.ParseDuration(strings.TrimSpace(field.value))
+			if err != nil {
+				return policy, fmt.Errorf("invalid %s in config: %w", field.name, err)
+			}
+			*field.target = delay
+		}
 	}
 
-	if strings.TrimSpace(configServerURL) != "" {
-		return strings.TrimSpace(configServerURL)
+	if flags.Changed("retry-attempts") {
+		policy.MaxAttempts = retryAttempts
+	}
+	if flags.Changed("retry-base-delay") {
+		policy.BaseDelay = retryBaseDelay
+	}
+	if flags.Changed("retry-max-delay") {
+		policy.MaxDelay = retryMaxDelay
 	}
 
-	return strings.TrimSpace(flagServerURL)
+	if policy.MaxAttempts < 1 {
+		return policy, fmt.Errorf("retry attempts must be at least 1, got %d", policy.MaxAttempts)
+	}
+	if policy.BaseDelay < 0 || policy.MaxDelay < 0 {
+		return policy, fmt.Errorf("retry delays must not be negative")
+	}
+	return policy, nil
 }
 
 func saveConfig(path string, cfg config.Config) error {
-	if cfg.ServerURL == "" {
+	effective, err := cfg.WithContext(activeContextName(cfg))
+	if err != nil {
+		return err
+	}
+	if effective.ServerURL == "" {
 		return fmt.Errorf("server URL is required")
 	}
 
@@ -1,35 +1,279 @@
 package cli
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
 
-func TestResolveServerURLUsesFlagWhenChanged(t *testing.T) {
-	got := resolveServerURL(
-		"http://localhost:3000/v1/api",
-		"http://localhost:3100/v1/api",
-		true,
-	)
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/config"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/spf13/pflag"
+)
 
-	if got != "http://localhost:3100/v1/api" {
-		t.Fatalf("expected explicit server flag to win, got %q", got)
+func useTestConfig(t *testing.T, cfg config.Config) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := config.Save(path, cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	previousPath, previousContext, previousServer := configPath, contextName, serverURL
+	t.Cleanup(func() { configPath, contextName, serverURL = previousPath, previousContext, previousServer })
+	configPath, contextName, serverURL = path, "", defaultServerURL
+	for _, name := range []string{serverEnv, tokenEnv, outputEnv, contextEnv, noColorEnv, "NO_COLOR"} {
+		t.Setenv(name, "")
+	}
+}
+
+func serverFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVar(&serverURL, "server", defaultServerURL, "")
+	flags.StringVar(&contextName, "context", "", "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	return flags
+}
+
+func TestResolveConfigUsesServerFlagWhenChanged(t *testing.T) {
+	useTestConfig(t, config.Config{ServerURL: "http://localhost:3000/v1/api"})
+	t.Setenv(serverEnv, "http://localhost:3200/v1/api")
+
+	resolved, err := resolveConfig(serverFlags(t, "--server", "http://localhost:3100/v1/api"))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Server.Value != "http://localhost:3100/v1/api" || resolved.Server.Source != "flag --server" {
+		t.Fatalf("expected explicit server flag to win, got %+v", resolved.Server)
+	}
+}
+
+func TestResolveConfigPrefersEnvOverConfigFile(t *testing.T) {
+	useTestConfig(t, config.Config{ServerURL: "http://localhost:3200/v1/api", Token: "file-token", Output: "table"})
+	t.Setenv(serverEnv, "http://ci.example/v1/api")
+	t.Setenv(tokenEnv, "env-token")
+	t.Setenv(outputEnv, "json")
+
+	resolved, err := resolveConfig(serverFlags(t))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Config.ServerURL != "http://ci.example/v1/api" || resolved.Server.Source != "env LUNIE_SERVER" {
+		t.Fatalf("expected env server, got %+v", resolved.Server)
+	}
+	if resolved.Config.Token != "env-token" || resolved.Output.Value != "json" || resolved.Output.Source != "env LUNIE_OUTPUT" {
+		t.Fatalf("expected env token and output, got %+v", resolved)
 	}
 }
 
-func TestResolveServerURLUsesConfigWhenFlagNotChanged(t *testing.T) {
-	got := resolveServerURL(
-		"http://localhost:3200/v1/api",
-		defaultServerURL,
-		false,
-	)
+func TestResolveConfigUsesConfigThenDefault(t *testing.T) {
+	useTestConfig(t, config.Config{ServerURL: "http://localhost:3200/v1/api"})
+	resolved, err := resolveConfig(serverFlags(t))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Server.Value != "http://localhost:3200/v1/api" || resolved.Server.Source != "config file" {
+		t.Fatalf("expected config server to win, got %+v", resolved.Server)
+	}
 
-	if got != "http://localhost:3200/v1/api" {
-		t.Fatalf("expected config server to win, got %q", got)
+	useTestConfig(t, config.Config{})
+	resolved, err = resolveConfig(serverFlags(t))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Server.Value != defaultServerURL || resolved.Server.Source != "default" || resolved.Token.Source != "unset" {
+		t.Fatalf("expected defaults, got %+v", resolved)
 	}
 }
 
-func TestResolveServerURLFallsBackToFlagValue(t *testing.T) {
-	got := resolveServerURL("", defaultServerURL, false)
+func TestResolveConfigSelectsContextFromEnv(t *testing.T) {
+	useTestConfig(t, config.Config{
+		ServerURL: "http://localhost:3000/v1/api",
+		Contexts: map[string]config.ContextConfig{
+			"prod": {ServerURL: "https://prod.example/v1/api", Token: "prod-token"},
+		},
+	})
+	t.Setenv(contextEnv, "prod")
+
+	resolved, err := resolveConfig(serverFlags(t))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Context.Source != "env LUNIE_CONTEXT" || resolved.Server.Source != "context prod" || resolved.Config.Token != "prod-token" {
+		t.Fatalf("expected prod context from env, got %+v", resolved)
+	}
+}
+
+func TestMaskTokenKeepsSuffix(t *testing.T) {
+	if got := maskToken("lunie_abcdefgh1234"); got != "********1234" {
+		t.Fatalf("unexpected mask: %q", got)
+	}
+	if got := maskToken("short"); got != "*****" {
+		t.Fatalf("unexpected short mask: %q", got)
+	}
+}
+
+func TestResolveRetryPolicyPrefersFlagsOverConfig(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.IntVar(&retryAttempts, "retry-attempts", api.DefaultRetryAttempts, "")
+	flags.DurationVar(&retryBaseDelay, "retry-base-delay", api.DefaultRetryBaseDelay, "")
+	flags.DurationVar(&retryMaxDelay, "retry-max-delay", api.DefaultRetryMaxDelay, "")
+	if err := flags.Parse([]string{"--retry-attempts", "5"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	policy, err := resolveRetryPolicy(&config.RetryConfig{MaxAttempts: 2, BaseDelay: "1s"}, flags)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if policy.MaxAttempts != 5 || policy.BaseDelay != time.Second || policy.MaxDelay != api.DefaultRetryMaxDelay {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+
+	if _, err := resolveRetryPolicy(&config.RetryConfig{MaxDelay: "soon"}, pflag.NewFlagSet("empty", pflag.ContinueOnError)); err == nil {
+		t.Fatalf("expected invalid config delay to fail")
+	}
+}
+
+func TestLoadConfigAppliesContextFlag(t *testing.T) {
+	useTestConfig(t, config.Config{
+		ServerURL:      "http://localhost:3000/v1/api",
+		CurrentContext: "staging",
+		Contexts: map[string]config.ContextConfig{
+			"staging": {ServerURL: "https://staging.example/v1/api"},
+			"prod":    {ServerURL: "https://prod.example/v1/api", Token: "prod-token"},
+		},
+	})
+
+	effective, _, err := loadConfig(serverFlags(t))
+	if err != nil || effective.ServerURL != "https://staging.example/v1/api" {
+		t.Fatalf("expected current context, got %+v (%v)", effective, err)
+	}
+
+	effective, _, err = loadConfig(serverFlags(t, "--context", "prod"))
+	if err != nil || effective.CurrentContext != "prod" || effective.Token != "prod-token" {
+		t.Fatalf("expected --context to win, got %+v (%v)", effective, err)
+	}
+}
+
+func TestResolveConfigDoesNotSendTopLevelTokenToContextServer(t *testing.T) {
+	useTestConfig(t, config.Config{
+		ServerURL: "http://localhost:3000/v1/api",
+		Token:     "local-secret-token",
+		Contexts: map[string]config.ContextConfig{
+			"prod": {ServerURL: "https://prod.example/v1/api"},
+		},
+	})
+
+	resolved, err := resolveConfig(serverFlags(t, "--context", "prod"))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Server.Value != "https://prod.example/v1/api" || resolved.Token.Value != "" || resolved.Config.Token != "" {
+		t.Fatalf("expected no token for prod server, got %+v", resolved)
+	}
+}
+
+func TestResolveConfigReadsTokenFromCredentialStore(t *testing.T) {
+	useTestConfig(t, config.Config{})
+	t.Setenv(credentialPassphraseEnv, "correct horse")
+
+	stored, path, err := loadConfigFile()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := saveToken(&stored, path, "", defaultServerURL, "file-token", config.StoreFile); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+	if err := config.Save(path, stored); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if stored.Token != "" {
+		t.Fatalf("expected token to stay out of the config file, got %+v", stored)
+	}
+
+	resolved, err := resolveConfig(serverFlags(t))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Config.Token != "file-token" || resolved.TokenStore != config.StoreFile || resolved.TokenError != nil {
+		t.Fatalf("expected token from the encrypted file, got %+v", resolved)
+	}
+
+	t.Setenv(credentialPassphraseEnv, "wrong")
+	resolved, err = resolveConfig(serverFlags(t))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Config.Token != "" || resolved.TokenError == nil {
+		t.Fatalf("expected unreadable store to be reported, got %+v", resolved)
+	}
+}
+
+func TestOfflineCommandsRunWithLockedCredentialStore(t *testing.T) {
+	useTestConfig(t, config.Config{})
+	t.Setenv(credentialPassphraseEnv, "correct horse")
+
+	stored, path, err := loadConfigFile()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := saveToken(&stored, path, "", defaultServerURL, "file-token", config.StoreFile); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+	if err := config.Save(path, stored); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	t.Setenv(credentialPassphraseEnv, "")
+
+	definition := filepath.Join(t.TempDir(), "def.json")
+	source := `{"steps": [{"key": "fetch", "type": "http", "request": {"method": "GET", "url": "https://example.com"}}]}`
+	if err := os.WriteFile(definition, []byte(source), 0o600); err != nil {
+		t.Fatalf("write definition: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"workflow", "lint", definition})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("expected lint to run without the passphrase, got %v", err)
+	}
+
+	lintCmd, _, err := rootCmd.Find([]string{"workflow", "lint"})
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	ctx := GetContext(lintCmd.Context())
+	if ctx == nil {
+		t.Fatalf("expected command context")
+	}
+	if _, err := ctx.Client.AuthToken(); err == nil || !strings.Contains(err.Error(), credentialPassphraseEnv) {
+		t.Fatalf("expected the locked store to fail on first API use, got %v", err)
+	}
+}
+
+func TestSelectCredentialStoreNeverFallsBackToPlaintext(t *testing.T) {
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "")
+	t.Setenv(credentialStoreEnv, "")
+	previous := credentialStoreName
+	t.Cleanup(func() { credentialStoreName = previous })
+	credentialStoreName = ""
+
+	if _, err := selectCredentialStore(nil, config.Config{}); !errors.Is(err, lerrors.ErrUsage) {
+		t.Fatalf("expected usage error without a secure store, got %v", err)
+	}
+
+	store, err := selectCredentialStore(nil, config.Config{Credentials: &config.CredentialsConfig{Store: config.StorePlaintext}})
+	if err != nil || store != config.StorePlaintext {
+		t.Fatalf("expected explicit plaintext opt-in, got %q (%v)", store, err)
+	}
 
-	if got != defaultServerURL {
-		t.Fatalf("expected fallback server %q, got %q", defaultServerURL, got)
+	t.Setenv(credentialStoreEnv, "vault")
+	if _, err := selectCredentialStore(nil, config.Config{}); err == nil {
+		t.Fatal("expected unknown store to fail")
 	}
 }
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect CLI configuration",
+}
+
+type configViewResult struct {
+	Path           string      `json:"path"`
+	Context        configValue `json:"context"`
+	Server         configValue `json:"server"`
+	Token          configValue `json:"token"`
+	TokenStore     string      `json:"tokenStore,omitempty"`
+	TokenError     string      `json:"tokenError,omitempty"`
+	Output         configValue `json:"output"`
+	NoColor        configValue `json:"noColor"`
+	Theme          configValue `json:"theme"`
+	RequestTimeout configValue `json:"requestTimeout"`
+	RetryAttempts  configValue `json:"retryAttempts"`
+	RetryBaseDelay configValue `json:"retryBaseDelay"`
+	RetryMaxDelay  configValue `json:"retryMaxDelay"`
+}
+
+func init() {
+	viewCmd := &cobra.Command{
+		Use:   "view",
+		Short: "Show the effective configuration and where each value comes from",
+		Long:  "Settings are resolved in order: flag, environment variable, active context, config file, default. The token is masked.",
+		Args:  cobra.NoArgs,
+		RunE:  configView,
+	}
+
+	configCmd.AddCommand(viewCmd)
+}
+
+func configView(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	resolved, err := resolveConfig(cmd.Flags())
+	if err != nil {
+		return err
+	}
+	retry, err := resolveRetryPolicy(ctx.Config.Retry, cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	result := configViewResult{
+		Path:           resolved.Path,
+		Context:        resolved.Context,
+		Server:         resolved.Server,
+		Token:          configValue{Value: maskToken(resolved.Token.Value), Source: resolved.Token.Source},
+		TokenStore:     resolved.TokenStore,
+		Output:         resolved.Output,
+		NoColor:        resolved.NoColor,
+		Theme:          resolved.Theme,
+		RequestTimeout: configValue{Value: requestTimeout.String(), Source: flagOrDefault(cmd.Flags(), "request-timeout")},
+		RetryAttempts:  configValue{Value: strconv.Itoa(retry.MaxAttempts), Source: retrySource(cmd.Flags(), "retry-attempts", ctx.Config.Retry != nil && ctx.Config.Retry.MaxAttempts != 0)},
+		RetryBaseDelay: configValue{Value: retry.BaseDelay.String(), Source: retrySource(cmd.Flags(), "retry-base-delay", ctx.Config.Retry != nil && ctx.Config.Retry.BaseDelay != "")},
+		RetryMaxDelay:  configValue{Value: retry.MaxDelay.String(), Source: retrySource(cmd.Flags(), "retry-max-delay", ctx.Config.Retry != nil && ctx.Config.Retry.MaxDelay != "")},
+	}
+
+	tokenValue := valueOrUnset(result.Token.Value)
+	if resolved.TokenError != nil {
+		result.TokenError = resolved.TokenError.Error()
+		tokenValue = "(error: " + result.TokenError + ")"
+	}
+
+	if IsStructured(ctx) {
+		return output.Print(result)
+	}
+
+	rows := [][]string{
+		{"config", result.Path, ""},
+		{"context", valueOrUnset(result.Context.Value), result.Context.Source},
+		{"server", result.Server.Value, result.Server.Source},
+		{"token", tokenValue, result.Token.Source},
+		{"token-store", valueOrUnset(result.TokenStore), result.Token.Source},
+		{"output", result.Output.Value, result.Output.Source},
+		{"no-color", result.NoColor.Value, result.NoColor.Source},
+		{"theme", result.Theme.Value, result.Theme.Source},
+		{"request-timeout", result.RequestTimeout.Value, result.RequestTimeout.Source},
+		{"retry-attempts", result.RetryAttempts.Value, result.RetryAttempts.Source},
+		{"retry-base-delay", result.RetryBaseDelay.Value, result.RetryBaseDelay.Source},
+		{"retry-max-delay", result.RetryMaxDelay.Value, result.RetryMaxDelay.Source},
+	}
+	return output.PrintListTable([]string{"SETTING", "VALUE", "SOURCE"}, rows)
+}
+
+// maskToken keeps the last four characters so tokens can be told apart.
+func maskToken(token string) string {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ""
+	}
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return strings.Repeat("*", 8) + token[len(token)-4:]
+}
+
+func valueOrUnset(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	return value
+}
+
+func flagOrDefault(flags *pflag.FlagSet, name string) string {
+	if flags.Changed(name) {
+		return "flag --" + name
+	}
+	return "default"
+}
+
+func retrySource(flags *pflag.FlagSet, name string, inConfig bool) string {
+	if flags.Changed(name) {
+		return "flag --" + name
+	}
+	if inConfig {
+		return "config file"
+	}
+	return "default"
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gentij/lunie/apps/cli/internal/api"
 	"github.com/gentij/lunie/apps/cli/internal/config"
+	"github.com/gentij/lunie/apps/cli/internal/output"
 )
 
 type Context struct {
@@ -34,6 +35,8 @@ func GetContext(ctx context.Context) *Context {
 	return nil
 }
 
-func IsJSON(ctx *Context) bool {
-	return ctx != nil && ctx.Output == "json"
+// IsStructured reports whether results are printed as data documents (json,
+// yaml, jsonpath or go-template) with output.Print rather than as tables.
+func IsStructured(ctx *Context) bool {
+	return ctx != nil && output.Format(ctx.Output).Structured()
 }
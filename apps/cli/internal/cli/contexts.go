@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/config"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manage named server contexts",
+	Long:  "Contexts are named server profiles (server URL, token, default output and theme) stored in the config file.",
+	// Context commands only touch the config file, so they must keep working
+	// when the current context is missing or its server is unreachable.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		stored, _, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		name := activeContextName(stored)
+		noColorValue, err := resolveNoColorValue(cmd.Flags())
+		if err != nil {
+			return err
+		}
+		outputValue := resolveOutputValue(cmd.Flags(), stored.Contexts[name], "context "+name, stored)
+		if err := applyOutputSettings(outputValue, noColorValue); err != nil {
+			return err
+		}
+		cmd.SetContext(WithContext(cmd.Context(), &Context{Output: outputMode, Quiet: quiet, NoColor: noColor}))
+		return nil
+	},
+}
+
+var contextAddServer string
+var contextAddToken string
+var contextAddOutput string
+var contextAddTheme string
+var contextAddUse bool
+
+type contextSummary struct {
+	Name       string `json:"name"`
+	Current    bool   `json:"current"`
+	ServerURL  string `json:"serverUrl"`
+	TokenSet   bool   `json:"tokenSet"`
+	TokenStore string `json:"tokenStore,omitempty"`
+	Output     string `json:"output,omitempty"`
+	Theme      string `json:"theme,omitempty"`
+}
+
+func init() {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List contexts",
+		Args:  cobra.NoArgs,
+		RunE:  contextList,
+	}
+
+	useCmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Switch the current context",
+		Args:  cobra.ExactArgs(1),
+		RunE:  contextUse,
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a context",
+		Args:  cobra.ExactArgs(1),
+		RunE:  contextAdd,
+	}
+	addCmd.Flags().StringVar(&contextAddServer, "server", "", "API server URL")
+	addCmd.Flags().StringVar(&contextAddToken, "token", "", "API token (or run 'lunie auth login --context <name>' later)")
+	addCmd.Flags().StringVar(&contextAddOutput, "default-output", "", "Default output format ("+output.FormatNames+")")
+	addCmd.Flags().StringVar(&contextAddTheme, "theme", "", "TUI theme")
+	addCmd.Flags().BoolVar(&contextAddUse, "use", false, "Switch to the new context")
+	addCredentialStoreFlag(addCmd)
+	_ = addCmd.MarkFlagRequired("server")
+
+	removeCmd := &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a context",
+		Args:    cobra.ExactArgs(1),
+		RunE:    contextRemove,
+	}
+
+	renameCmd := &cobra.Command{
+		Use:   "rename <old-name> <new-name>",
+		Short: "Rename a context",
+		Args:  cobra.ExactArgs(2),
+		RunE:  contextRename,
+	}
+
+	contextCmd.AddCommand(listCmd)
+	contextCmd.AddCommand(useCmd)
+	contextCmd.AddCommand(addCmd)
+	contextCmd.AddCommand(removeCmd)
+	contextCmd.AddCommand(renameCmd)
+}
+
+func contextList(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	cfg, _, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+	current := activeContextName(cfg)
+
+	items := make([]contextSummary, 0, len(cfg.Contexts))
+	for _, name := range cfg.ContextNames() {
+		effective, _ := cfg.WithContext(name)
+		store, _ := cfg.TokenStoreFor(name)
+		items = append(items, contextSummary{
+			Name:       name,
+			Current:    name == current,
+			ServerURL:  effective.ServerURL,
+			TokenSet:   store != "",
+			TokenStore: store,
+			Output:     effective.Output,
+			Theme:      effective.Theme,
+		})
+	}
+
+	if IsStructured(ctx) {
+		return output.Print(items)
+	}
+	if ctx.Quiet {
+		for _, item := range items {
+			fmt.Fprintln(os.Stdout, item.Name)
+		}
+		return nil
+	}
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		marker := ""
+		if item.Current {
+			marker = "*"
+		}
+		token := "not set"
+		if item.TokenSet {
+			token = item.TokenStore
+		}
+		rows = append(rows, []string{marker, item.Name, item.ServerURL, token, item.Output, item.Theme})
+	}
+	return output.PrintListTable([]string{"CURRENT", "NAME", "SERVER", "TOKEN", "OUTPUT", "THEME"}, rows)
+}
+
+func contextUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	return updateContexts(cmd, func(cfg *config.Config, path string) error {
+		return cfg.UseContext(name)
+	}, fmt.Sprintf("Switched to context %s", name))
+}
+
+func contextAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	outputFormat := strings.TrimSpace(contextAddOutput)
+	if outputFormat != "" {
+		if _, err := output.ParseMode(outputFormat); err != nil {
+			return lerrors.Usage(err)
+		}
+	}
+	server := strings.TrimSpace(contextAddServer)
+	if server == "" {
+		return fmt.Errorf("server URL is required")
+	}
+
+	token := strings.TrimSpace(contextAddToken)
+	store := ""
+	if token != "" {
+		stored, _, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if store, err = selectCredentialStore(cmd.Flags(), stored); err != nil {
+			return err
+		}
+	}
+
+	message := fmt.Sprintf("Added context %s", name)
+	if contextAddUse {
+		message += " and switched to it"
+	}
+	return updateContexts(cmd, func(cfg *config.Config, path string) error {
+		err := cfg.AddContext(name, config.ContextConfig{
+			ServerURL: server,
+			Output:    outputFormat,
+			Theme:     strings.TrimSpace(contextAddTheme),
+		})
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			if err := saveToken(cfg, path, name, server, token, store); err != nil {
+				return err
+			}
+		}
+		if contextAddUse {
+			return cfg.UseContext(name)
+		}
+		return nil
+	}, message)
+}
+
+func contextRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	return updateContexts(cmd, func(cfg *config.Config, path string) error {
+		if _, ok := cfg.Contexts[name]; !ok {
+			return fmt.Errorf("context %q not found", name)
+		}
+		if _, err := eraseToken(*cfg, path, name); err != nil {
+			return err
+		}
+		return cfg.RemoveContext(name)
+	}, fmt.Sprintf("Removed context %s", name))
+}
+
+// contextRename also moves a token kept in a credential store, since stores
+// key tokens by context name.
+func contextRename(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+	return updateContexts(cmd, func(cfg *config.Config, path string) error {
+		token, store, owner, err := readToken(*cfg, path, oldName)
+		if err != nil {
+			return err
+		}
+		if err := cfg.RenameContext(oldName, newName); err != nil {
+			return err
+		}
+		if oldName == newName || owner != oldName || store == config.StorePlaintext {
+			return nil
+		}
+		backend, err := openCredentialStore(*cfg, path, store)
+		if err != nil {
+			return err
+		}
+		key := config.CredentialKey{Context: newName, Server: contextServer(*cfg, newName)}
+		if err := backend.Store(key, token); err != nil {
+			return err
+		}
+		err = backend.Erase(config.CredentialKey{Context: oldName, Server: key.Server})
+		if errors.Is(err, config.ErrCredentialNotFound) {
+			return nil
+		}
+		return err
+	}, fmt.Sprintf("Renamed context %s to %s", oldName, newName))
+}
+
+func updateContexts(cmd *cobra.Command, fn func(cfg *config.Config, path string) error, message string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	path := config.ResolvePath(configPath)
+	var current string
+	err := config.Update(path, func(cfg *config.Config) error {
+		if err := fn(cfg, path); err != nil {
+			return err
+		}
+		current = cfg.CurrentContext
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if IsStructured(ctx) {
+		return output.Print(map[string]string{"currentContext": current})
+	}
+	if ctx.Quiet {
+		return nil
+	}
+	fmt.Fprintln(os.Stdout, message)
+	return nil
+}
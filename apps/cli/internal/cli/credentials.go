@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/config"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+const (
+	credentialStoreEnv      = "LUNIE_CREDENTIAL_STORE"
+	credentialPassphraseEnv = "LUNIE_CREDENTIALS_PASSPHRASE"
+)
+
+var credentialStoreName string
+
+func addCredentialStoreFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&credentialStoreName, "store", "", "Credential store for the token ("+strings.Join(config.CredentialStoreNames(), "|")+") [$"+credentialStoreEnv+"]")
+}
+
+// selectCredentialStore picks the backend for a new token: --store, then
+// LUNIE_CREDENTIAL_STORE, then credentials.store in the config file, then the
+// Secret Service when it is available. Plaintext is never picked implicitly.
+func selectCredentialStore(flags *pflag.FlagSet, cfg config.Config) (string, error) {
+	configured := ""
+	if cfg.Credentials != nil {
+		configured = cfg.Credentials.Store
+	}
+	value := firstValue(
+		flagValue(flags, "store", credentialStoreName),
+		envValue(credentialStoreEnv),
+		configValue{Value: configured, Source: "config file"},
+		configValue{Value: config.DefaultCredentialStore(), Source: "default"},
+	)
+	if value.Value == "" {
+		return "", lerrors.Usage(fmt.Errorf(
+			"no secure credential store available: install secret-tool for the Secret Service, or pass --store file, --store helper or --store plaintext",
+		))
+	}
+	if !slices.Contains(config.CredentialStoreNames(), value.Value) {
+		return "", lerrors.Usage(fmt.Errorf(
+			"invalid credential store %q from %s (expected %s)",
+			value.Value, value.Source, strings.Join(config.CredentialStoreNames(), "|"),
+		))
+	}
+	return value.Value, nil
+}
+
+func openCredentialStore(cfg config.Config, path string, store string) (config.CredentialStore, error) {
+	opts := config.CredentialOptions{Dir: filepath.Dir(path), Passphrase: credentialPassphrase}
+	if cfg.Credentials != nil {
+		opts.Helper = cfg.Credentials.Helper
+	}
+	return config.OpenCredentialStore(store, opts)
+}
+
+func credentialPassphrase() (string, error) {
+	if passphrase := os.Getenv(credentialPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("credentials passphrase required: set %s", credentialPassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, "Credentials passphrase: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(passphrase), nil
+}
+
+// saveToken writes token to store and records the store on the named context,
+// or on the top-level config when name is empty.
+func saveToken(cfg *config.Config, path string, name string, server string, token string, store string) error {
+	if store != config.StorePlaintext {
+		backend, err := openCredentialStore(*cfg, path, store)
+		if err != nil {
+			return err
+		}
+		if err := backend.Store(config.CredentialKey{Context: name, Server: server}, token); err != nil {
+			return err
+		}
+	}
+	cfg.SetCredentials(name, server, token, store)
+	return nil
+}
+
+// eraseToken removes the token owned by the named context from its store. It
+// reports the store that held the token, if any.
+func eraseToken(cfg config.Config, path string, name string) (string, error) {
+	store, owner := cfg.TokenStoreFor(name)
+	if store == "" || owner != name {
+		return "", nil
+	}
+	if store == config.StorePlaintext {
+		return store, nil
+	}
+	backend, err := openCredentialStore(cfg, path, store)
+	if err != nil {
+		return store, err
+	}
+	err = backend.Erase(config.CredentialKey{Context: name, Server: contextServer(cfg, name)})
+	if err != nil && !errors.Is(err, config.ErrCredentialNotFound) {
+		return store, err
+	}
+	return store, nil
+}
+
+// readToken returns the token for the named context from wherever the config
+// file says it is kept, and the store and the context that own it.
+func readToken(cfg config.Config, path string, name string) (token string, store string, owner string, err error) {
+	store, owner = cfg.TokenStoreFor(name)
+	switch store {
+	case "":
+		return "", "", owner, nil
+	case config.StorePlaintext:
+		if owner == "" {
+			return cfg.Token, store, owner, nil
+		}
+		return cfg.Contexts[owner].Token, store, owner, nil
+	}
+
+	backend, err := openCredentialStore(cfg, path, store)
+	if err != nil {
+		return "", store, owner, err
+	}
+	token, err = backend.Get(config.CredentialKey{Context: owner, Server: contextServer(cfg, owner)})
+	if err != nil {
+		return "", store, owner, fmt.Errorf("read token from %s: %w", store, err)
+	}
+	return token, store, owner, nil
+}
+
+func contextServer(cfg config.Config, name string) string {
+	if context, ok := cfg.Contexts[name]; ok && name != "" && context.ServerURL != "" {
+		return context.ServerURL
+	}
+	return cfg.ServerURL
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	debugEnv     = "LUNIE_DEBUG"
+	debugFileEnv = "LUNIE_DEBUG_FILE"
+)
+
+// debugTraceFile is the open --debug-file, closed when the command finishes.
+var debugTraceFile *os.File
+
+func init() {
+	cobra.OnFinalize(closeDebugTrace)
+}
+
+func closeDebugTrace() {
+	if debugTraceFile != nil {
+		_ = debugTraceFile.Close()
+		debugTraceFile = nil
+	}
+}
+
+// enableDebugTrace logs API traffic when --debug or LUNIE_DEBUG is set. The TUI
+// owns the terminal, so it only traces to a file and shows the rest in its
+// network pane.
+func enableDebugTrace(cmd *cobra.Command, client *api.Client) error {
+	value := os.Getenv(debugEnv)
+	if cmd.Flags().Changed("debug") {
+		value = debugLevel
+	}
+	level, err := api.ParseTraceLevel(value)
+	if err != nil {
+		return lerrors.Usage(err)
+	}
+	if level == api.TraceOff {
+		return nil
+	}
+
+	path := strings.TrimSpace(os.Getenv(debugFileEnv))
+	if cmd.Flags().Changed("debug-file") {
+		path = strings.TrimSpace(debugFile)
+	}
+
+	var w io.Writer = os.Stderr
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("open debug file: %w", err)
+		}
+		debugTraceFile = file
+		w = file
+	} else if cmd == tuiCmd {
+		return nil
+	}
+
+	client.EnableTrace(level, api.NewTraceWriter(w))
+	return nil
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/manifest"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var diffFile string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare workflow manifests with the server",
+	RunE:  diffManifests,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFile, "file", "f", "", "Manifest file or directory (YAML or JSON)")
+	_ = diffCmd.MarkFlagRequired("file")
+}
+
+type resourceDiff struct {
+	Resource string                `json:"resource"`
+	Label    string                `json:"label,omitempty"`
+	Changes  []manifest.Difference `json:"changes"`
+}
+
+func diffManifests(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	workflows, err := manifest.Load(diffFile)
+	if err != nil {
+		return err
+	}
+
+	diffs := []resourceDiff{}
+	for _, workflow := range workflows {
+		state, err := loadServerWorkflowState(cmd.Context(), ctx.Client, workflow.Key)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, workflowDiffs(workflow, state)...)
+	}
+
+	if err := printResourceDiffs(ctx, diffs); err != nil {
+		return err
+	}
+
+	count := 0
+	for _, diff := range diffs {
+		count += len(diff.Changes)
+	}
+	if count == 0 {
+		return nil
+	}
+	return &exitError{
+		Code: exitCodeDifferences,
+		Err:  fmt.Errorf("%d difference(s) found", count),
+	}
+}
+
+func workflowDiffs(workflow manifest.Workflow, state serverWorkflowState) []resourceDiff {
+	workflowRef := "workflow/" + workflow.Key
+	if state.Workflow == nil {
+		return []resourceDiff{{
+			Resource: workflowRef,
+			Label:    "not on server",
+			Changes:  []manifest.Difference{{Kind: manifest.DiffAdded, After: workflowDocument(workflow)}},
+		}}
+	}
+
+	diffs := []resourceDiff{}
+	settings := manifest.Diff(
+		map[string]any{"name": state.Workflow.Name, "isActive": state.Workflow.IsActive},
+		map[string]any{"name": workflow.DisplayName(), "isActive": workflow.Active()},
+	)
+	if len(settings) > 0 {
+		diffs = append(diffs, resourceDiff{Resource: workflowRef, Changes: settings})
+	}
+
+	var serverDefinition any = map[string]any{}
+	label := "no versions on server"
+	if state.LatestVersion != nil {
+		serverDefinition = manifest.ServerDefinition(state.LatestVersion.Definition)
+		label = fmt.Sprintf("definition vs version %d", state.LatestVersion.Version)
+	}
+	if changes := manifest.Diff(serverDefinition, manifest.ServerDefinition(workflow.Definition)); len(changes) > 0 {
+		diffs = append(diffs, resourceDiff{Resource: "version/" + workflow.Key, Label: label, Changes: changes})
+	}
+
+	declared := map[string]bool{}
+	byKey := map[string]api.Trigger{}
+	for _, trigger := range state.Triggers {
+		byKey[trigger.Key] = trigger
+	}
+	for _, desired := range workflow.Triggers {
+		declared[desired.Key] = true
+		ref := "trigger/" + workflow.Key + "/" + desired.Key
+		current, ok := byKey[desired.Key]
+		if !ok {
+			diffs = append(diffs, resourceDiff{
+				Resource: ref,
+				Label:    "not on server",
+				Changes:  []manifest.Difference{{Kind: manifest.DiffAdded, After: triggerDocument(desired)}},
+			})
+			continue
+		}
+		if changes := manifest.Diff(serverTriggerDocument(current), triggerDocument(desired)); len(changes) > 0 {
+			diffs = append(diffs, resourceDiff{Resource: ref, Changes: changes})
+		}
+	}
+	for _, trigger := range state.Triggers {
+		if declared[trigger.Key] || !trigger.IsActive {
+			continue
+		}
+		if trigger.Key == builtinManualTriggerKey && strings.EqualFold(trigger.Type, "MANUAL") {
+			continue
+		}
+		diffs = append(diffs, resourceDiff{
+			Resource: "trigger/" + workflow.Key + "/" + trigger.Key,
+			Label:    "not in manifest",
+			Changes:  []manifest.Difference{{Kind: manifest.DiffRemoved, Before: serverTriggerDocument(trigger)}},
+		})
+	}
+
+	return diffs
+}
+
+func workflowDocument(workflow manifest.Workflow) map[string]any {
+	return map[string]any{
+		"name":       workflow.DisplayName(),
+		"isActive":   workflow.Active(),
+		"definition": workflow.Definition,
+	}
+}
+
+func triggerDocument(trigger manifest.Trigger) map[string]any {
+	return map[string]any{
+		"type":     trigger.Type,
+		"name":     trigger.DisplayName(),
+		"isActive": trigger.Active(),
+		"config":   trigger.ConfigValue(),
+	}
+}
+
+func serverTriggerDocument(trigger api.Trigger) map[string]any {
+	var config any = map[string]any{}
+	if trigger.Config != nil {
+		config = trigger.Config
+	}
+	return map[string]any{
+		"type":     strings.ToUpper(trigger.Type),
+		"name":     triggerNameValue(trigger.Name),
+		"isActive": trigger.IsActive,
+		"config":   config,
+	}
+}
+
+func printResourceDiffs(ctx *Context, diffs []resourceDiff) error {
+	if IsStructured(ctx) {
+		return output.Print(diffs)
+	}
+
+	if ctx.Quiet {
+		for _, diff := range diffs {
+			fmt.Fprintln(os.Stdout, diff.Resource)
+		}
+		return nil
+	}
+
+	if len(diffs) == 0 {
+		fmt.Fprintln(os.Stdout, "No differences.")
+		return nil
+	}
+
+	for i, diff := range diffs {
+		if i > 0 {
+			fmt.Fprintln(os.Stdout)
+		}
+		header := diff.Resource
+		if diff.Label != "" {
+			header += "  (" + diff.Label + ")"
+		}
+		fmt.Fprintln(os.Stdout, header)
+		for _, change := range diff.Changes {
+			fmt.Fprintln(os.Stdout, "  "+formatDifference(change))
+		}
+	}
+	return nil
+}
+
+func formatDifference(change manifest.Difference) string {
+	path := change.Path
+	if path == "" {
+		path = "."
+	}
+	switch change.Kind {
+	case manifest.DiffAdded:
+		return output.ColorDiff(change.Kind, "+ "+path+": "+compactJSON(change.After))
+	case manifest.DiffRemoved:
+		return output.ColorDiff(change.Kind, "- "+path+": "+compactJSON(change.Before))
+	default:
+		return output.ColorDiff(change.Kind, "~ "+path+": "+compactJSON(change.Before)+" -> "+compactJSON(change.After))
+	}
+}
+
+func compactJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/config"
+	"github.com/gentij/lunie/apps/cli/internal/engine"
+	"github.com/gentij/lunie/apps/cli/internal/manifest"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	evalModeAuto     = "auto"
+	evalModeTemplate = "template"
+	evalModeJMES     = "jmes"
+
+	evalHistoryFile  = "eval_history"
+	evalHistoryLimit = 500
+)
+
+var evalContextFile string
+var evalWorkflow string
+var evalRun int
+var evalMode string
+var evalTransform bool
+var evalInteractive bool
+
+var evalCmd = &cobra.Command{
+	Use:   "eval [expression]",
+	Short: "Evaluate a template or JMESPath expression against run data",
+	Long: "Evaluate a {{...}} template or a JMESPath expression against a context of workflow input and step outputs.\n" +
+		"The context comes from a saved run (--workflow and --run) or a JSON/YAML file (--context-file) with input, steps and secret keys.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: evalExpression,
+}
+
+func init() {
+	evalCmd.Flags().StringVar(&evalContextFile, "context-file", "", "Context file (JSON or YAML) with input, steps and secret")
+	evalCmd.Flags().StringVar(&evalWorkflow, "workflow", "", "Workflow key of the run to load step outputs from")
+	evalCmd.Flags().IntVar(&evalRun, "run", 0, "Run number to load step outputs from")
+	evalCmd.Flags().StringVar(&evalMode, "mode", evalModeAuto, "Expression kind (auto|template|jmes)")
+	evalCmd.Flags().BoolVar(&evalTransform, "transform", false, "Use the transform root for JMESPath (step bodies keep their _lunieHttp wrapper)")
+	evalCmd.Flags().BoolVarP(&evalInteractive, "interactive", "i", false, "Start an interactive REPL")
+}
+
+type evalSession struct {
+	Scope     engine.Scope
+	Mode      string
+	Transform bool
+	History   []string
+}
+
+func evalExpression(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+	if !validEvalMode(evalMode) {
+		return fmt.Errorf("invalid --mode: %s (expected auto, template or jmes)", evalMode)
+	}
+	if !evalInteractive && len(args) == 0 {
+		return fmt.Errorf("expression is required unless --interactive is set")
+	}
+
+	scope, err := loadEvalScope(cmd, ctx)
+	if err != nil {
+		return err
+	}
+	session := &evalSession{Scope: scope, Mode: strings.ToLower(evalMode), Transform: evalTransform}
+
+	if evalInteractive {
+		historyPath := evalHistoryPath()
+		session.History = loadEvalHistory(historyPath)
+		if len(args) > 0 {
+			session.History = append(session.History, args[0])
+		}
+		err := session.repl(os.Stdin, os.Stdout)
+		if saveErr := saveEvalHistory(historyPath, session.History); saveErr != nil && err == nil {
+			err = saveErr
+		}
+		return err
+	}
+
+	value, err := session.evaluate(args[0])
+	if err != nil {
+		return err
+	}
+	if IsStructured(ctx) {
+		return output.Print(value)
+	}
+	return writeEvalValue(os.Stdout, value)
+}
+
+func loadEvalScope(cmd *cobra.Command, ctx *Context) (engine.Scope, error) {
+	scope := engine.Scope{Input: map[string]any{}, Steps: map[string]any{}, Secret: map[string]string{}}
+
+	fromRun := strings.TrimSpace(evalWorkflow) != "" || evalRun != 0
+	if fromRun && strings.TrimSpace(evalContextFile) != "" {
+		return scope, fmt.Errorf("use either --context-file or --workflow/--run, not both")
+	}
+
+	switch {
+	case fromRun:
+		if strings.TrimSpace(evalWorkflow) == "" || evalRun <= 0 {
+			return scope, fmt.Errorf("--workflow and --run must be used together")
+		}
+		snapshot, err := fetchRunSnapshot(cmd.Context(), ctx.Client, evalWorkflow, evalRun)
+		if err != nil {
+			return scope, err
+		}
+		scope = runEvalScope(snapshot)
+	case strings.TrimSpace(evalContextFile) != "":
+		loaded, err := loadEvalContextFile(evalContextFile)
+		if err != nil {
+			return scope, err
+		}
+		scope = loaded
+	}
+
+	secrets, err := loadLocalSecrets("", os.Environ())
+	if err != nil {
+		return scope, err
+	}
+	for name, value := range secrets {
+		scope.Secret[name] = value
+	}
+	return scope, nil
+}
+
+func runEvalScope(snapshot runSnapshot) engine.Scope {
+	scope := engine.Scope{Input: map[string]any{}, Steps: map[string]any{}, Secret: map[string]string{}}
+	if input, ok := snapshot.Run.Input.(map[string]any); ok {
+		scope.Input = input
+	}
+	for _, step := range snapshot.Steps {
+		if step.Output != nil {
+			scope.Steps[step.StepKey] = unwrapPersistedOutput(step.Output)
+		}
+	}
+	return scope
+}
+
+// unwrapPersistedOutput strips the {_lunie, data} envelope the worker stores
+// step outputs in, the same way the worker does before resolving templates.
+func unwrapPersistedOutput(output any) any {
+	object, ok := output.(map[string]any)
+	if !ok {
+		return output
+	}
+	if _, ok := object["_lunie"]; !ok {
+		return output
+	}
+	data, ok := object["data"]
+	if !ok {
+		return output
+	}
+	return data
+}
+
+func loadEvalContextFile(path string) (engine.Scope, error) {
+	scope := engine.Scope{Input: map[string]any{}, Steps: map[string]any{}, Secret: map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return scope, err
+	}
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return scope, fmt.Errorf("%s: %w", path, err)
+	}
+	normalized, err := manifest.Normalize(raw)
+	if err != nil {
+		return scope, fmt.Errorf("%s: %w", path, err)
+	}
+	object, ok := normalized.(map[string]any)
+	if !ok {
+		return scope, fmt.Errorf("%s: context must be an object with input, steps and secret keys", path)
+	}
+
+	if input, ok := object["input"].(map[string]any); ok {
+		scope.Input = input
+	}
+	if steps, ok := object["steps"].(map[string]any); ok {
+		scope.Steps = steps
+	}
+	if secrets, ok := object["secret"].(map[string]any); ok {
+		for name, value := range secrets {
+			scope.Secret[name] = fmt.Sprint(value)
+		}
+	}
+	return scope, nil
+}
+
+func (s *evalSession) evaluate(expression string) (any, error) {
+	mode := s.Mode
+	if mode == evalModeAuto {
+		mode = evalModeJMES
+		if strings.Contains(expression, "{{") {
+			mode = evalModeTemplate
+		}
+	}
+
+	if mode == evalModeTemplate {
+		return engine.Resolve(expression, s.Scope)
+	}
+	value, err := engine.Search(expression, s.Scope, !s.Transform)
+	if err != nil {
+		return nil, fmt.Errorf("JMESPath evaluation failed: %w", err)
+	}
+	return value, nil
+}
+
+func (s *evalSession) repl(in io.Reader, out io.Writer) error {
+	fmt.Fprintln(out, "Type an expression to evaluate, :help for commands, :quit to exit.")
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for {
+		fmt.Fprintf(out, "eval(%s)> ", s.Mode)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if isEvalRecall(line) {
+			recalled, err := s.recall(line)
+			if err != nil {
+				fmt.Fprintf(out, "error: %v\n", err)
+				continue
+			}
+			fmt.Fprintln(out, recalled)
+			line = recalled
+		}
+
+		if strings.HasPrefix(line, ":") {
+			if done := s.command(line, out); done {
+				return nil
+			}
+			continue
+		}
+
+		s.History = append(s.History, line)
+		value, err := s.evaluate(line)
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			continue
+		}
+		if err := writeEvalValue(out, value); err != nil {
+			return err
+		}
+	}
+}
+
+func isEvalRecall(line string) bool {
+	if line == "!!" {
+		return true
+	}
+	_, err := strconv.Atoi(strings.TrimPrefix(line, "!"))
+	return strings.HasPrefix(line, "!") && err == nil
+}
+
+func (s *evalSession) recall(line string) (string, error) {
+	if len(s.History) == 0 {
+		return "", fmt.Errorf("history is empty")
+	}
+	if line == "!!" {
+		return s.History[len(s.History)-1], nil
+	}
+	index, _ := strconv.Atoi(strings.TrimPrefix(line, "!"))
+	if index <= 0 || index > len(s.History) {
+		return "", fmt.Errorf("no history entry %s", line)
+	}
+	return s.History[index-1], nil
+}
+
+func (s *evalSession) command(line string, out io.Writer) bool {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case ":quit", ":q", ":exit":
+		return true
+	case ":help":
+		fmt.Fprintln(out, ":mode [auto|template|jmes]  show or switch the expression kind")
+		fmt.Fprintln(out, ":transform                  toggle the transform JMESPath root")
+		fmt.Fprintln(out, ":context                    print the evaluation context")
+		fmt.Fprintln(out, ":history                    list previous expressions")
+		fmt.Fprintln(out, "!!, !N                      re-run the last or Nth expression")
+		fmt.Fprintln(out, ":quit                       exit")
+	case ":mode":
+		if len(fields) > 1 {
+			if !validEvalMode(fields[1]) {
+				fmt.Fprintf(out, "error: invalid mode %s\n", fields[1])
+				return false
+			}
+			s.Mode = strings.ToLower(fields[1])
+		}
+		fmt.Fprintf(out, "mode: %s\n", s.Mode)
+	case ":transform":
+		s.Transform = !s.Transform
+		fmt.Fprintf(out, "transform root: %t\n", s.Transform)
+	case ":context":
+		_ = writeEvalValue(out, map[string]any{"input": s.Scope.Input, "steps": s.Scope.Steps, "secret": secretNames(s.Scope.Secret)})
+	case ":history":
+		for i, entry := range s.History {
+			fmt.Fprintf(out, "%4d  %s\n", i+1, entry)
+		}
+	default:
+		fmt.Fprintf(out, "error: unknown command %s (try :help)\n", fields[0])
+	}
+	return false
+}
+
+func writeEvalValue(out io.Writer, value any) error {
+	if text, ok := value.(string); ok {
+		_, err := fmt.Fprintln(out, text)
+		return err
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+func validEvalMode(mode string) bool {
+	switch strings.ToLower(mode) {
+	case evalModeAuto, evalModeTemplate, evalModeJMES:
+		return true
+	default:
+		return false
+	}
+}
+
+func secretNames(secrets map[string]string) []string {
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func evalHistoryPath() string {
+	return filepath.Join(filepath.Dir(config.ResolvePath(configPath)), evalHistoryFile)
+}
+
+func loadEvalHistory(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{}
+	}
+	history := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			history = append(history, line)
+		}
+	}
+	return history
+}
+
+func saveEvalHistory(path string, history []string) error {
+	if len(history) > evalHistoryLimit {
+		history = history[len(history)-evalHistoryLimit:]
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0o600)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/engine"
+)
+
+func testEvalSession(t *testing.T) *evalSession {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "context.yaml")
+	content := `input:
+  postId: 7
+steps:
+  fetch_posts:
+    statusCode: 200
+    body:
+      _lunieHttp:
+        contentType: application/json
+      data:
+        - title: First
+          tags: [go]
+        - title: Second
+          tags: []
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	scope, err := loadEvalContextFile(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return &evalSession{Scope: scope, Mode: evalModeAuto}
+}
+
+func TestEvalSessionResolvesTemplatesAndJMESPath(t *testing.T) {
+	session := testEvalSession(t)
+
+	value, err := session.evaluate("{{steps.fetch_posts.output.0.title}} #{{input.postId}}")
+	if err != nil || value != "First #7" {
+		t.Fatalf("unexpected template result: %v, %v", value, err)
+	}
+
+	value, err = session.evaluate("steps.fetch_posts[?length(tags) > `0`].title")
+	if err != nil {
+		t.Fatalf("jmes: %v", err)
+	}
+	titles, ok := value.([]any)
+	if !ok || len(titles) != 1 || titles[0] != "First" {
+		t.Fatalf("unexpected jmes result: %#v", value)
+	}
+
+	session.Transform = true
+	value, err = session.evaluate("steps.fetch_posts._lunieHttp.contentType")
+	if err != nil || value != "application/json" {
+		t.Fatalf("unexpected transform root result: %v, %v", value, err)
+	}
+
+	if _, err := session.evaluate("steps.["); err == nil || !strings.Contains(err.Error(), "JMESPath evaluation failed") {
+		t.Fatalf("expected JMESPath error, got %v", err)
+	}
+}
+
+func TestEvalREPLRecallsHistory(t *testing.T) {
+	session := testEvalSession(t)
+	session.History = []string{"input.postId"}
+
+	var out bytes.Buffer
+	in := strings.NewReader("!1\n:mode template\n{{input.postId}}\n:mode jmes\n!(input.missing)\n:history\n:quit\n")
+	if err := session.repl(in, &out); err != nil {
+		t.Fatalf("repl: %v", err)
+	}
+
+	text := out.String()
+	for _, want := range []string{"input.postId\n7\n", "mode: template", "eval(jmes)> true\n", "   3  {{input.postId}}"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+	if len(session.History) != 4 {
+		t.Fatalf("unexpected history: %v", session.History)
+	}
+}
+
+func persistedStepOutput(statusCode int, data any) map[string]any {
+	return map[string]any{
+		"_lunie": map[string]any{"truncated": false, "bytesEstimate": float64(128), "maxBytes": float64(262144), "hardMaxBytes": float64(10485760), "reason": "step output"},
+		"data": map[string]any{
+			"statusCode": float64(statusCode),
+			"headers":    map[string]any{"content-type": "application/json"},
+			"body": map[string]any{
+				"_lunieHttp": map[string]any{"contentType": "application/json", "bytesRead": float64(64)},
+				"data":       data,
+			},
+		},
+	}
+}
+
+func TestRunEvalScopeUsesLatestStepOutputs(t *testing.T) {
+	scope := runEvalScope(runSnapshot{
+		Run: api.WorkflowRun{Input: map[string]any{"limit": float64(2)}},
+		Steps: []api.StepRun{
+			{StepKey: "fetch", Attempt: 1, Output: persistedStepOutput(500, map[string]any{"error": "boom"})},
+			{StepKey: "fetch", Attempt: 2, Output: persistedStepOutput(200, []any{map[string]any{"title": "hello"}})},
+			{StepKey: "notify"},
+		},
+	})
+	if scope.Input["limit"] != float64(2) || len(scope.Steps) != 1 {
+		t.Fatalf("unexpected scope: %+v", scope)
+	}
+	if scope.Steps["fetch"].(map[string]any)["statusCode"] != float64(200) {
+		t.Fatalf("expected latest attempt output, got %v", scope.Steps["fetch"])
+	}
+}
+
+func TestRunEvalScopeResolvesPersistedOutputs(t *testing.T) {
+	scope := runEvalScope(runSnapshot{
+		Steps: []api.StepRun{
+			{StepKey: "fetch_posts", Attempt: 1, Output: persistedStepOutput(200, []any{map[string]any{"title": "hello"}})},
+		},
+	})
+
+	value, err := engine.Resolve("{{steps.fetch_posts.output.0.title}}", scope)
+	if err != nil || value != "hello" {
+		t.Fatalf("expected template to resolve, got %v (%v)", value, err)
+	}
+	value, err = engine.Search("steps.fetch_posts[0].title", scope, true)
+	if err != nil || value != "hello" {
+		t.Fatalf("expected JMESPath to resolve, got %v (%v)", value, err)
+	}
+}
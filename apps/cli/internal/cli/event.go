@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var eventCmd = &cobra.Command{
+	Use:   "event",
+	Short: "Browse and replay trigger events",
+}
+
+var eventListOptions listOptions
+var eventListSortBy string
+var eventListSortOrder string
+var eventGetPayloadOnly bool
+
+type eventReplayResult struct {
+	EventID           string `json:"eventId"`
+	Status            string `json:"status"`
+	WorkflowRunID     string `json:"workflowRunId,omitempty"`
+	WorkflowRunNumber int    `json:"workflowRunNumber,omitempty"`
+}
+
+func init() {
+	listCmd := &cobra.Command{
+		Use:   "list <workflow-key> <trigger-key>",
+		Short: "List events received by a trigger",
+		Args:  cobra.ExactArgs(2),
+		RunE:  eventList,
+	}
+	addListFlags(listCmd, &eventListOptions)
+	listCmd.Flags().StringVar(&eventListSortBy, "sort-by", "receivedAt", "Sort field (receivedAt|createdAt)")
+	listCmd.Flags().StringVar(&eventListSortOrder, "sort-order", "desc", "Sort order (asc|desc)")
+
+	getCmd := &cobra.Command{
+		Use:   "get <workflow-key> <trigger-key> <event-id>",
+		Short: "Get an event and its payload",
+		Args:  cobra.ExactArgs(3),
+		RunE:  eventGet,
+	}
+	getCmd.Flags().BoolVar(&eventGetPayloadOnly, "payload", false, "Print only the payload as JSON")
+
+	replayCmd := &cobra.Command{
+		Use:   "replay <workflow-key> <trigger-key> <event-id>",
+		Short: "Re-submit an event payload through the trigger webhook",
+		Long:  "Re-submit a stored event's payload as input through the trigger's webhook endpoint. The step overrides of the run the event started are sent again. The replay is recorded as a new event and starts a new run.",
+		Args:  cobra.ExactArgs(3),
+		RunE:  eventReplay,
+	}
+
+	eventCmd.AddCommand(listCmd)
+	eventCmd.AddCommand(getCmd)
+	eventCmd.AddCommand(replayCmd)
+}
+
+func eventList(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	trigger, err := ctx.Client.GetTriggerByKey(cmd.Context(), args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	return printList(cmd, ctx, eventListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.Event], error) {
+			return ctx.Client.ListEvents(reqCtx, trigger.WorkflowID, trigger.ID, page, pageSize, eventListSortBy, eventListSortOrder)
+		},
+		listView[api.Event]{
+			Headers: []string{"ID", "TYPE", "EXTERNAL ID", "RECEIVED"},
+			Row: func(item api.Event) []string {
+				return []string{item.ID, stringValue(item.Type), stringValue(item.ExternalID), item.ReceivedAt}
+			},
+			WideHeaders: []string{"CREATED"},
+			WideRow: func(item api.Event) []string {
+				return []string{item.CreatedAt}
+			},
+			Ref:        func(item api.Event) string { return item.ID },
+			Pagination: true,
+		},
+	)
+}
+
+func eventGet(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	event, err := getEventByKeys(cmd, ctx, args[0], args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	if eventGetPayloadOnly {
+		payload, err := formatEventPayload(event.Payload)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout, payload)
+		return nil
+	}
+	if IsStructured(ctx) {
+		return output.Print(event)
+	}
+	if ctx.Quiet {
+		fmt.Fprintln(os.Stdout, event.ID)
+		return nil
+	}
+
+	if err := output.PrintKVTable([][2]string{
+		{"id", event.ID},
+		{"triggerId", event.TriggerID},
+		{"type", stringValue(event.Type)},
+		{"externalId", stringValue(event.ExternalID)},
+		{"receivedAt", event.ReceivedAt},
+		{"createdAt", event.CreatedAt},
+	}); err != nil {
+		return err
+	}
+
+	payload, err := formatEventPayload(event.Payload)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "\npayload:\n%s\n", payload)
+	return nil
+}
+
+func eventReplay(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	workflowKey := args[0]
+	triggerKey := args[1]
+	trigger, err := ctx.Client.GetTriggerByKey(cmd.Context(), workflowKey, triggerKey)
+	if err != nil {
+		return err
+	}
+	event, err := ctx.Client.GetEvent(cmd.Context(), trigger.WorkflowID, trigger.ID, strings.TrimSpace(args[2]))
+	if err != nil {
+		return err
+	}
+	overrides, err := eventRunOverrides(cmd.Context(), ctx.Client, trigger.WorkflowID, event)
+	if err != nil {
+		return err
+	}
+
+	input := event.Payload
+	if input == nil {
+		input = map[string]any{}
+	}
+
+	response, err := ctx.Client.TriggerWebhookByKey(cmd.Context(), workflowKey, triggerKey, input, overrides)
+	if err != nil {
+		return err
+	}
+	if err := webhookResponseError(workflowKey, triggerKey, response); err != nil {
+		return err
+	}
+
+	result := eventReplayResult{
+		EventID:           event.ID,
+		Status:            response.Status,
+		WorkflowRunID:     response.WorkflowRunID,
+		WorkflowRunNumber: response.WorkflowRunNumber,
+	}
+	if IsStructured(ctx) {
+		return output.Print(result)
+	}
+	if ctx.Quiet {
+		fmt.Fprintln(os.Stdout, result.WorkflowRunNumber)
+		return nil
+	}
+
+	return output.PrintKVTable([][2]string{
+		{"eventId", result.EventID},
+		{"status", result.Status},
+		{"workflowRunNumber", fmt.Sprintf("%d", result.WorkflowRunNumber)},
+		{"workflowRunId", result.WorkflowRunID},
+	})
+}
+
+// eventRunOverrides returns the overrides of the run an event started, or an
+// empty object if it started none. Runs cannot be filtered by event, so they
+// are read newest first until they are older than the event.
+func eventRunOverrides(ctx context.Context, client *api.Client, workflowID string, event api.Event) (any, error) {
+	runs := api.Paginate(ctx, func(reqCtx context.Context, page int) (api.Paginated[api.WorkflowRun], error) {
+		return client.ListWorkflowRuns(reqCtx, workflowID, page, api.MaxPageSize, "createdAt", "desc")
+	})
+	for run, err := range runs {
+		if err != nil {
+			return nil, err
+		}
+		if run.EventID != nil && *run.EventID == event.ID {
+			if run.Overrides != nil {
+				return run.Overrides, nil
+			}
+			break
+		}
+		if run.CreatedAt < event.CreatedAt {
+			break
+		}
+	}
+	return map[string]any{}, nil
+}
+
+// getEventByKeys resolves the trigger by key first, since the events API is
+// addressed by workflow and trigger ID.
+func getEventByKeys(cmd *cobra.Command, ctx *Context, workflowKey string, triggerKey string, eventID string) (api.Event, error) {
+	trigger, err := ctx.Client.GetTriggerByKey(cmd.Context(), workflowKey, triggerKey)
+	if err != nil {
+		return api.Event{}, err
+	}
+	return ctx.Client.GetEvent(cmd.Context(), trigger.WorkflowID, trigger.ID, strings.TrimSpace(eventID))
+}
+
+func formatEventPayload(payload any) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(payload); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
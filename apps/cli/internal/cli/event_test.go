@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+)
+
+func TestFormatEventPayloadIndentsJSON(t *testing.T) {
+	got, err := formatEventPayload(map[string]any{"ref": "refs/heads/main", "size": 2})
+	if err != nil {
+		t.Fatalf("format payload: %v", err)
+	}
+	want := "{\n  \"ref\": \"refs/heads/main\",\n  \"size\": 2\n}"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFormatEventPayloadKeepsHTML(t *testing.T) {
+	got, err := formatEventPayload("<b>&</b>")
+	if err != nil {
+		t.Fatalf("format payload: %v", err)
+	}
+	if got != `"<b>&</b>"` {
+		t.Fatalf("expected unescaped payload, got %q", got)
+	}
+}
+
+func TestEventRunOverridesFindsTheRunTheEventStarted(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"ok":true,"data":{"items":[{"id":"run_3","eventId":"evt_2","createdAt":"2026-10-03T00:00:00.000Z"}],"pagination":{"page":1,"hasNext":true}}}`,
+		"2": `{"ok":true,"data":{"items":[{"id":"run_2","eventId":"evt_1","overrides":{"fetch":{"timeoutMs":500}},"createdAt":"2026-10-02T00:00:00.000Z"}],"pagination":{"page":2,"hasNext":false}}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}))
+	defer server.Close()
+
+	event := api.Event{ID: "evt_1", CreatedAt: "2026-10-02T00:00:00.000Z"}
+	overrides, err := eventRunOverrides(context.Background(), api.NewClient(server.URL, "token"), "wf_1", event)
+	if err != nil {
+		t.Fatalf("overrides: %v", err)
+	}
+	want := map[string]any{"fetch": map[string]any{"timeoutMs": float64(500)}}
+	if !reflect.DeepEqual(overrides, want) {
+		t.Fatalf("expected %v, got %v", want, overrides)
+	}
+}
+
+func TestEventRunOverridesStopsAtRunsOlderThanTheEvent(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"items":[{"id":"run_1","createdAt":"2026-10-01T00:00:00.000Z"}],"pagination":{"page":1,"hasNext":true}}}`))
+	}))
+	defer server.Close()
+
+	event := api.Event{ID: "evt_1", CreatedAt: "2026-10-02T00:00:00.000Z"}
+	overrides, err := eventRunOverrides(context.Background(), api.NewClient(server.URL, "token"), "wf_1", event)
+	if err != nil {
+		t.Fatalf("overrides: %v", err)
+	}
+	if !reflect.DeepEqual(overrides, map[string]any{}) {
+		t.Fatalf("expected empty overrides, got %v", overrides)
+	}
+	if n := requests.Load(); n > 2 {
+		t.Fatalf("expected the search to stop at older runs, got %d requests", n)
+	}
+}
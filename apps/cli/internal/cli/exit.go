@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	exitCodeError       = lerrors.ExitError
+	exitCodeRunFailed   = lerrors.ExitRunFailed
+	exitCodeRunTimeout  = lerrors.ExitRunTimeout
+	exitCodeDifferences = lerrors.ExitDifferences
+	exitCodeInterrupted = lerrors.ExitInterrupted
+)
+
+type exitError struct {
+	Code int
+	Err  error
+}
+
+func (e *exitError) Error() string {
+	if e == nil || e.Err == nil {
+		return ""
+	}
+	return e.Err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) && exitErr.Code != 0 {
+		return exitErr.Code
+	}
+	return lerrors.ExitCode(err)
+}
+
+// errorClass names the failure in the JSON error envelope. Outcome exit codes
+// set by commands take precedence over the class of the wrapped error.
+func errorClass(err error) string {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.Code {
+		case exitCodeRunFailed:
+			return "run_failed"
+		case exitCodeRunTimeout:
+			return "run_timeout"
+		case exitCodeDifferences:
+			return "differences"
+		}
+	}
+	if class := lerrors.Classify(err); class != nil {
+		return class.Name()
+	}
+	return "error"
+}
+
+// markUsageErrors classifies flag and positional argument errors as usage
+// errors for every command in the tree.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return lerrors.Usage(err)
+	})
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, values []string) error {
+			if err := args(cmd, values); err != nil {
+				return lerrors.Usage(err)
+			}
+			return nil
+		}
+	}
+	for _, child := range cmd.Commands() {
+		markUsageErrors(child)
+	}
+}
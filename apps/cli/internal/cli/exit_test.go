@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+)
+
+func TestExitCodeUsesWrappedExitError(t *testing.T) {
+	err := fmt.Errorf("wait: %w", &exitError{Code: exitCodeRunTimeout, Err: errors.New("timed out")})
+	if got := exitCode(err); got != exitCodeRunTimeout {
+		t.Fatalf("expected exit code %d, got %d", exitCodeRunTimeout, got)
+	}
+}
+
+func TestExitCodeDefaultsToGenericError(t *testing.T) {
+	if got := exitCode(errors.New("boom")); got != exitCodeError {
+		t.Fatalf("expected exit code %d, got %d", exitCodeError, got)
+	}
+	if got := exitCode(nil); got != 0 {
+		t.Fatalf("expected exit code 0 for nil error, got %d", got)
+	}
+}
+
+func TestExitCodeReportsInterrupts(t *testing.T) {
+	err := fmt.Errorf("GET /workflows: %w", context.Canceled)
+	if got := exitCode(err); got != exitCodeInterrupted {
+		t.Fatalf("expected exit code %d, got %d", exitCodeInterrupted, got)
+	}
+}
+
+func TestErrorClassPrefersCommandOutcome(t *testing.T) {
+	err := &exitError{Code: exitCodeRunFailed, Err: errors.New("run failed")}
+	if got := errorClass(err); got != "run_failed" {
+		t.Fatalf("expected run_failed, got %q", got)
+	}
+	if got := errorClass(fmt.Errorf("wrap: %w", lerrors.Usage(errors.New("bad")))); got != "usage" {
+		t.Fatalf("expected usage, got %q", got)
+	}
+	if got := errorClass(errors.New("boom")); got != "error" {
+		t.Fatalf("expected error, got %q", got)
+	}
+}
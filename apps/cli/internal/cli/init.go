@@ -441,7 +441,12 @@ func validateComposeDatastoreMode(composePath string) error {
 }
 
 func maybeUpdateConfig(values map[string]string) error {
-	cfg, path, err := loadConfig(false)
+	stored, path, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+	name := activeContextName(stored)
+	cfg, err := stored.WithContext(name)
 	if err != nil {
 		return err
 	}
@@ -450,14 +455,20 @@ func maybeUpdateConfig(values map[string]string) error {
 		cfg.ServerURL = defaultServerURL
 	}
 
-	if strings.TrimSpace(cfg.Token) == "" {
-		if token := strings.TrimSpace(values["LUNIE_ADMIN_TOKEN"]); token != "" {
-			cfg.Token = token
-			return saveConfig(path, cfg)
-		}
+	token := strings.TrimSpace(values["LUNIE_ADMIN_TOKEN"])
+	if token == "" || cfg.Token != "" || cfg.TokenStore != "" {
+		return nil
 	}
 
-	return nil
+	store, err := selectCredentialStore(nil, stored)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Admin token not saved: %v\n", err)
+		return nil
+	}
+	if err := saveToken(&stored, path, name, cfg.ServerURL, token, store); err != nil {
+		return err
+	}
+	return saveConfig(path, stored)
 }
 
 func runDockerCompose(baseDir string, composePath string, args ...string) error {
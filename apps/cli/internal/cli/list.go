@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// listOptions holds the paging flags shared by list commands.
+type listOptions struct {
+	Page     int
+	PageSize int
+	All      bool
+	Limit    int
+}
+
+// listView describes how a list command renders its items. WideHeaders and
+// WideRow add columns for --output wide. Filter drops items client-side, for
+// filters the API does not support; Done reports that no later item can
+// match, so the walk over the pages can stop early.
+type listView[T any] struct {
+	Headers     []string
+	Row         func(T) []string
+	WideHeaders []string
+	WideRow     func(T) []string
+	Ref         func(T) string
+	Pagination  bool
+	Filter      func(T) bool
+	Done        func(T) bool
+}
+
+func (v listView[T]) wide() bool {
+	return v.WideRow != nil && output.CurrentMode().Format == output.FormatWide
+}
+
+func (v listView[T]) headers() []string {
+	if v.wide() {
+		return append(append([]string{}, v.Headers...), v.WideHeaders...)
+	}
+	return v.Headers
+}
+
+func (v listView[T]) rows(items []T) [][]string {
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := v.Row(item)
+		if v.wide() {
+			row = append(row, v.WideRow(item)...)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+type listFetch[T any] func(ctx context.Context, page int, pageSize int) (api.Paginated[T], error)
+
+func addListFlags(cmd *cobra.Command, opts *listOptions) {
+	cmd.Flags().IntVar(&opts.Page, "page", 1, "Page number")
+	cmd.Flags().IntVar(&opts.PageSize, "page-size", 25, "Page size")
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Fetch every page, streaming rows as they arrive")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 0, "Fetch pages until this many items are listed (0 means no limit)")
+}
+
+// printList prints a single page, or with --all/--limit walks the pages from
+// --page onwards and streams rows as each page arrives. With a client-side
+// filter it walks the pages until --page-size items match, unless --all or
+// --limit say otherwise.
+func printList[T any](cmd *cobra.Command, ctx *Context, opts listOptions, fetch listFetch[T], view listView[T]) error {
+	if opts.Limit < 0 {
+		return fmt.Errorf("--limit must be zero or greater")
+	}
+	filtered := view.Filter != nil
+	if !opts.All && opts.Limit == 0 && !filtered {
+		result, err := fetch(cmd.Context(), opts.Page, opts.PageSize)
+		if err != nil {
+			return err
+		}
+		return printListPage(ctx, result, view)
+	}
+	if filtered && !opts.All && opts.Limit == 0 {
+		opts.Limit = opts.PageSize
+	}
+
+	pageSize := api.MaxPageSize
+	if cmd.Flags().Changed("page-size") && !filtered {
+		pageSize = opts.PageSize
+	}
+	if opts.Limit > 0 && opts.Limit < pageSize && !cmd.Flags().Changed("page-size") && !filtered {
+		pageSize = opts.Limit
+	}
+	startPage, skip := listStart(opts.Page, opts.PageSize, pageSize)
+	pages := api.Pages(cmd.Context(), func(reqCtx context.Context, page int) (api.Paginated[T], error) {
+		return fetch(reqCtx, startPage+page-1, pageSize)
+	})
+
+	stream := newListStream(ctx, view)
+	listed := 0
+	for page, err := range pages {
+		if err != nil {
+			return err
+		}
+		if skip > 0 {
+			page.Items = page.Items[min(skip, len(page.Items)):]
+			skip = 0
+		}
+		items, done := view.filter(page.Items)
+		if opts.Limit > 0 && listed+len(items) > opts.Limit {
+			items = items[:opts.Limit-listed]
+		}
+		if err := stream.write(items); err != nil {
+			return err
+		}
+		listed += len(items)
+		if done || opts.Limit > 0 && listed >= opts.Limit {
+			break
+		}
+	}
+	return stream.close()
+}
+
+// listStart turns --page, counted in pages of --page-size items, into the
+// page to fetch at pageSize and the items to skip on it, so switching to
+// larger pages for --all still starts at the same item.
+func listStart(page int, requestedSize int, pageSize int) (int, int) {
+	offset := (max(page, 1) - 1) * max(requestedSize, 1)
+	return offset/pageSize + 1, offset % pageSize
+}
+
+// filter returns the items that pass Filter, up to the first one for which
+// Done is true.
+func (v listView[T]) filter(items []T) ([]T, bool) {
+	if v.Filter == nil && v.Done == nil {
+		return items, false
+	}
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if v.Done != nil && v.Done(item) {
+			return kept, true
+		}
+		if v.Filter == nil || v.Filter(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept, false
+}
+
+func printListPage[T any](ctx *Context, result api.Paginated[T], view listView[T]) error {
+	if IsStructured(ctx) {
+		return output.Print(result)
+	}
+
+	if ctx.Quiet {
+		for _, item := range result.Items {
+			fmt.Fprintln(os.Stdout, view.Ref(item))
+		}
+		return nil
+	}
+
+	if err := output.PrintListTable(view.headers(), view.rows(result.Items)); err != nil {
+		return err
+	}
+	if !view.Pagination {
+		return nil
+	}
+	return output.PrintPagination(result.Pagination)
+}
+
+// listStream writes items page by page. Structured output has to be a single
+// document, so it is buffered and printed as {"items": [...]} on close.
+type listStream[T any] struct {
+	ctx           *Context
+	view          listView[T]
+	items         []T
+	headerPrinted bool
+}
+
+func newListStream[T any](ctx *Context, view listView[T]) *listStream[T] {
+	return &listStream[T]{ctx: ctx, view: view, items: make([]T, 0)}
+}
+
+func (s *listStream[T]) write(items []T) error {
+	if IsStructured(s.ctx) {
+		s.items = append(s.items, items...)
+		return nil
+	}
+
+	if s.ctx.Quiet {
+		for _, item := range items {
+			fmt.Fprintln(os.Stdout, s.view.Ref(item))
+		}
+		return nil
+	}
+
+	header := !s.headerPrinted
+	s.headerPrinted = true
+	return output.PrintListRows(s.view.headers(), s.view.rows(items), header)
+}
+
+func (s *listStream[T]) close() error {
+	if IsStructured(s.ctx) {
+		return output.Print(map[string]any{"items": s.items})
+	}
+	if !s.ctx.Quiet && !s.headerPrinted {
+		return output.PrintListTable(s.view.headers(), nil)
+	}
+	return nil
+}
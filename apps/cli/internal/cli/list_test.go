@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func TestListStartKeepsTheRequestedOffset(t *testing.T) {
+	cases := []struct {
+		page, requested, pageSize int
+		wantPage, wantSkip        int
+	}{
+		{1, 25, 100, 1, 0},
+		{2, 25, 100, 1, 25},
+		{5, 25, 100, 2, 0},
+		{3, 40, 100, 1, 80},
+		{2, 25, 25, 2, 0},
+		{0, 25, 100, 1, 0},
+	}
+	for _, tc := range cases {
+		page, skip := listStart(tc.page, tc.requested, tc.pageSize)
+		if page != tc.wantPage || skip != tc.wantSkip {
+			t.Fatalf("listStart(%d, %d, %d) = %d, %d; want %d, %d", tc.page, tc.requested, tc.pageSize, page, skip, tc.wantPage, tc.wantSkip)
+		}
+	}
+}
+
+func TestPrintListAllStartsAtTheRequestedPage(t *testing.T) {
+	items := make([]int, 130)
+	for i := range items {
+		items[i] = i + 1
+	}
+	fetch := func(_ context.Context, page int, pageSize int) (api.Paginated[int], error) {
+		from := min((page-1)*pageSize, len(items))
+		to := min(from+pageSize, len(items))
+		return api.Paginated[int]{Items: items[from:to], Pagination: api.Pagination{HasNext: to < len(items)}}, nil
+	}
+	view := listView[int]{Ref: func(n int) string { return strconv.Itoa(n) }}
+
+	cmd := &cobra.Command{}
+	opts := listOptions{}
+	addListFlags(cmd, &opts)
+	if err := cmd.Flags().Parse([]string{"--page", "2", "--all"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cmd.SetContext(context.Background())
+
+	got := captureListOutput(t, func() error {
+		return printList(cmd, &Context{Quiet: true}, opts, fetch, view)
+	})
+	lines := strings.Fields(got)
+	if len(lines) != 105 || lines[0] != "26" || lines[len(lines)-1] != "130" {
+		t.Fatalf("expected items 26..130, got %d starting at %v", len(lines), lines[:min(3, len(lines))])
+	}
+}
+
+func captureListOutput(t *testing.T, fn func() error) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	previous := os.Stdout
+	os.Stdout = writer
+	runErr := fn()
+	writer.Close()
+	os.Stdout = previous
+	data, _ := io.ReadAll(reader)
+	if runErr != nil {
+		t.Fatalf("print: %v", runErr)
+	}
+	return string(data)
+}
@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
 )
 
 func parsePositiveIntArg(name string, raw string) (int, error) {
 	trimmed := strings.TrimSpace(raw)
 	value, err := strconv.Atoi(trimmed)
 	if err != nil || value <= 0 {
-		return 0, fmt.Errorf("invalid %s: %s", name, raw)
+		return 0, lerrors.Usage(fmt.Errorf("invalid %s: %s", name, raw))
 	}
 
 	return value, nil
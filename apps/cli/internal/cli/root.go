@@ -1,11 +1,17 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
 	"github.com/gentij/lunie/apps/cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -13,64 +19,136 @@ import (
 const defaultServerURL = "http://localhost:3000/v1/api"
 
 var (
-	configPath string
-	serverURL  string
-	outputMode string
-	quiet      bool
-	noColor    bool
+	configPath     string
+	serverURL      string
+	outputMode     string
+	quiet          bool
+	noColor        bool
+	requestTimeout time.Duration
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	debugLevel     string
+	debugFile      string
+	contextName    string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "lunie",
-	Short: "Lunie CLI",
-	Long:  "Lunie CLI for managing workflows, runs, triggers, and secrets.",
+	Use:           "lunie",
+	Short:         "Lunie CLI",
+	Long:          "Lunie CLI for managing workflows, runs, triggers, and secrets.",
+	SilenceUsage:  true,
+	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		cfg, _, err := loadConfig(cmd.Flags().Changed("server"))
+		resolved, err := resolveSettings(cmd.Flags())
 		if err != nil {
 			return err
 		}
+		cfg := resolved.Config
 
-		if outputMode == "" {
-			outputMode = "table"
+		if err := applyOutputSettings(resolved.Output, resolved.NoColor); err != nil {
+			return err
+		}
+
+		if requestTimeout < 0 {
+			return lerrors.Usage(fmt.Errorf("invalid --request-timeout: %s", requestTimeout))
+		}
+		retry, err := resolveRetryPolicy(cfg.Retry, cmd.Flags())
+		if err != nil {
+			return err
 		}
-		outputMode = strings.ToLower(outputMode)
-		if outputMode != "table" && outputMode != "json" {
-			return fmt.Errorf("invalid output format: %s", outputMode)
+		client := api.NewClient(cfg.ServerURL, cfg.Token)
+		client.TokenSource = storedTokenSource(resolved)
+		client.RequestTimeout = requestTimeout
+		client.Retry = retry
+		if err := enableDebugTrace(cmd, client); err != nil {
+			return err
 		}
 
 		ctx := &Context{
 			Config:  cfg,
-			Client:  api.NewClient(cfg.ServerURL, cfg.Token),
+			Client:  client,
 			Output:  outputMode,
 			Quiet:   quiet,
 			NoColor: noColor,
 		}
 
-		output.SetNoColor(noColor)
-
 		cmd.SetContext(WithContext(cmd.Context(), ctx))
 		return nil
 	},
 }
 
+// storedTokenSource reads the credential store once, on the first request.
+func storedTokenSource(resolved resolvedConfig) func() (string, error) {
+	var once sync.Once
+	return func() (string, error) {
+		once.Do(resolved.loadToken)
+		return resolved.Token.Value, resolved.TokenError
+	}
+}
+
+func applyOutputSettings(outputValue configValue, noColorValue configValue) error {
+	noColor = noColorValue.Value == "true"
+	output.SetNoColor(noColor)
+	mode, err := output.ParseMode(outputValue.Value)
+	if err != nil {
+		return lerrors.Usage(err)
+	}
+	output.SetMode(mode)
+	outputMode = string(mode.Format)
+	return nil
+}
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		output.PrintError(err)
-		os.Exit(1)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	markUsageErrors(rootCmd)
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	stop()
+	if err != nil {
+		code := exitCode(err)
+		if wantsJSONErrors() {
+			output.PrintErrorJSON(err, errorClass(err), code)
+		} else {
+			output.PrintError(err)
+			if cmd != nil && errors.Is(err, lerrors.ErrUsage) {
+				fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+			}
+		}
+		os.Exit(code)
 	}
 }
 
+// wantsJSONErrors also checks LUNIE_OUTPUT directly so failures before the
+// output mode is resolved, such as a broken config file, are still JSON.
+func wantsJSONErrors() bool {
+	value := outputMode
+	if !rootCmd.PersistentFlags().Changed("output") && os.Getenv(outputEnv) != "" {
+		value = os.Getenv(outputEnv)
+	}
+	mode, err := output.ParseMode(value)
+	return err == nil && mode.Format.Structured()
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to config file")
-	rootCmd.PersistentFlags().StringVar(&serverURL, "server", defaultServerURL, "API server URL")
+	rootCmd.PersistentFlags().StringVar(&serverURL, "server", defaultServerURL, "API server URL [$LUNIE_SERVER]")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "Config context to use instead of the current one [$LUNIE_CONTEXT]")
 	rootCmd.PersistentFlags().StringVar(
 		&outputMode,
 		"output",
 		"table",
-		"Output format (table|json)",
+		"Output format ("+output.FormatNames+") [$LUNIE_OUTPUT]",
 	)
 	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Minimal output")
-	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable color output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable color output [$LUNIE_NO_COLOR]")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", api.DefaultRequestTimeout, "Deadline for each API request (0 disables it)")
+	rootCmd.PersistentFlags().IntVar(&retryAttempts, "retry-attempts", api.DefaultRetryAttempts, "Attempts per API request, including the first (1 disables retries)")
+	rootCmd.PersistentFlags().DurationVar(&retryBaseDelay, "retry-base-delay", api.DefaultRetryBaseDelay, "Initial backoff between retries")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxDelay, "retry-max-delay", api.DefaultRetryMaxDelay, "Maximum backoff between retries")
+
+	rootCmd.PersistentFlags().StringVar(&debugLevel, "debug", "", "Trace API requests to stderr (basic|headers|body)")
+	rootCmd.PersistentFlags().Lookup("debug").NoOptDefVal = "basic"
+	rootCmd.PersistentFlags().StringVar(&debugFile, "debug-file", "", "Write --debug traces to a file instead of stderr")
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(initCmd)
@@ -82,7 +160,14 @@ func init() {
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(workflowCmd)
 	rootCmd.AddCommand(triggerCmd)
+	rootCmd.AddCommand(eventCmd)
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(stepCmd)
 	rootCmd.AddCommand(secretCmd)
+	rootCmd.AddCommand(tokenCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(evalCmd)
+	rootCmd.AddCommand(contextCmd)
+	rootCmd.AddCommand(configCmd)
 }
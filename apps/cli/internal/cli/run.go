@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/gentij/lunie/apps/cli/internal/api"
 	"github.com/gentij/lunie/apps/cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -13,8 +16,7 @@ var runCmd = &cobra.Command{
 	Short: "Manage workflow runs",
 }
 
-var runListPage int
-var runListPageSize int
+var runListOptions listOptions
 var runListSortBy string
 var runListSortOrder string
 
@@ -25,10 +27,10 @@ func init() {
 		Args:  cobra.ExactArgs(1),
 		RunE:  runList,
 	}
-	listCmd.Flags().IntVar(&runListPage, "page", 1, "Page number")
-	listCmd.Flags().IntVar(&runListPageSize, "page-size", 25, "Page size")
+	addListFlags(listCmd, &runListOptions)
 	listCmd.Flags().StringVar(&runListSortBy, "sort-by", "createdAt", "Sort field (createdAt|updatedAt)")
 	listCmd.Flags().StringVar(&runListSortOrder, "sort-order", "desc", "Sort order (asc|desc)")
+	addRunFilterFlags(listCmd, &runListFilter)
 
 	getCmd := &cobra.Command{
 		Use:   "get <workflow-key> <run-number>",
@@ -37,8 +39,28 @@ func init() {
 		RunE:  runGet,
 	}
 
+	watchCmd := &cobra.Command{
+		Use:   "watch <workflow-key> <run-number>",
+		Short: "Follow a workflow run until it finishes",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runWatch,
+	}
+	watchCmd.Flags().DurationVar(&runWatchInterval, "interval", defaultRunWatchInterval, "Poll interval")
+
+	rerunCmd := &cobra.Command{
+		Use:   "rerun <workflow-key> <run-number>",
+		Short: "Queue a new run with the input and overrides of a past run",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runRerun,
+	}
+	rerunCmd.Flags().StringArrayVar(&runRerunSet, "set", nil, "Patch a field before re-running (input.<path>=<value> or overrides.<path>=<value>, repeatable)")
+	rerunCmd.Flags().BoolVar(&runRerunWait, "wait", false, "Wait for the new run to finish and exit non-zero unless it succeeds")
+	rerunCmd.Flags().DurationVar(&runRerunTimeout, "timeout", 0, "Maximum time to wait with --wait (0 waits indefinitely)")
+
 	runCmd.AddCommand(listCmd)
 	runCmd.AddCommand(getCmd)
+	runCmd.AddCommand(watchCmd)
+	runCmd.AddCommand(rerunCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -48,30 +70,33 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	workflowKey := args[0]
-	result, err := ctx.Client.ListWorkflowRunsByKey(workflowKey, runListPage, runListPageSize, runListSortBy, runListSortOrder)
+	filter, err := resolveRunFilter(cmd.Context(), ctx.Client, workflowKey, runListFilter, runListSortBy, runListSortOrder, time.Now())
 	if err != nil {
 		return err
 	}
-
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	view := listView[api.WorkflowRun]{
+		Headers: []string{"RUN", "STATUS", "VERSION", "CREATED"},
+		Row: func(item api.WorkflowRun) []string {
+			return []string{fmt.Sprintf("%d", item.Number), output.ColorStatus(item.Status), item.WorkflowVersionID, item.CreatedAt}
+		},
+		WideHeaders: []string{"ID", "TRIGGER", "EVENT", "STARTED", "DURATION"},
+		WideRow: func(item api.WorkflowRun) []string {
+			return []string{item.ID, stringValue(item.TriggerID), stringValue(item.EventID), stringValue(item.StartedAt), elapsedLabel(item.StartedAt, item.FinishedAt)}
+		},
+		Ref:        func(item api.WorkflowRun) string { return fmt.Sprintf("%d", item.Number) },
+		Pagination: true,
 	}
-
-	if ctx.Quiet {
-		for _, item := range result.Items {
-			fmt.Fprintln(os.Stdout, item.Number)
-		}
-		return nil
+	if filter.active() {
+		view.Filter = filter.matches
+		view.Done = filter.done
 	}
 
-	rows := make([][]string, 0, len(result.Items))
-	for _, item := range result.Items {
-		rows = append(rows, []string{fmt.Sprintf("%d", item.Number), output.ColorStatus(item.Status), item.WorkflowVersionID, item.CreatedAt})
-	}
-	if err := output.PrintListTable([]string{"RUN", "STATUS", "VERSION", "CREATED"}, rows); err != nil {
-		return err
-	}
-	return output.PrintPagination(result.Pagination)
+	return printList(cmd, ctx, runListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.WorkflowRun], error) {
+			return ctx.Client.ListWorkflowRunsByKey(reqCtx, workflowKey, page, pageSize, runListSortBy, runListSortOrder)
+		},
+		view,
+	)
 }
 
 func runGet(cmd *cobra.Command, args []string) error {
@@ -86,13 +111,13 @@ func runGet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := ctx.Client.GetWorkflowRunByNumber(workflowKey, runNumber)
+	result, err := ctx.Client.GetWorkflowRunByNumber(cmd.Context(), workflowKey, runNumber)
 	if err != nil {
 		return err
 	}
 
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 	if ctx.Quiet {
 		fmt.Fprintln(os.Stdout, result.Number)
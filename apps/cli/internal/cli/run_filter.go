@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// runStatuses are the statuses the API reports for runs and steps.
+var runStatuses = []string{"QUEUED", "RUNNING", "SUCCEEDED", "FAILED"}
+
+type runFilterFlags struct {
+	Status  []string
+	Since   string
+	Until   string
+	Trigger string
+	Version int
+}
+
+var runListFilter runFilterFlags
+var stepListStatus []string
+
+func addStatusFlag(cmd *cobra.Command, target *[]string) {
+	cmd.Flags().StringSliceVar(target, "status", nil, "Only list items with this status ("+strings.Join(runStatuses, "|")+", comma-separated or repeated)")
+}
+
+func addRunFilterFlags(cmd *cobra.Command, flags *runFilterFlags) {
+	addStatusFlag(cmd, &flags.Status)
+	cmd.Flags().StringVar(&flags.Since, "since", "", "Only list runs created since a duration (24h) or RFC3339 timestamp")
+	cmd.Flags().StringVar(&flags.Until, "until", "", "Only list runs created before a duration ago (1h) or RFC3339 timestamp")
+	cmd.Flags().StringVar(&flags.Trigger, "trigger", "", "Only list runs started by this trigger key")
+	cmd.Flags().IntVar(&flags.Version, "version", 0, "Only list runs of this workflow version")
+}
+
+// runFilter is the resolved form of the run list filters. The API lists runs
+// without filters, so they are applied to each page client-side.
+type runFilter struct {
+	statuses  []string
+	since     time.Time
+	until     time.Time
+	triggerID string
+	versionID string
+	sortBy    string
+	sortOrder string
+}
+
+func parseStatuses(values []string) ([]string, error) {
+	statuses := []string{}
+	for _, value := range values {
+		status := strings.ToUpper(strings.TrimSpace(value))
+		if status == "" {
+			continue
+		}
+		if !slices.Contains(runStatuses, status) {
+			return nil, lerrors.Usage(fmt.Errorf("invalid --status: %s (expected %s)", value, strings.Join(runStatuses, "|")))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// resolveRunFilter validates the flags and looks up the trigger and version
+// ids that runs refer to.
+func resolveRunFilter(ctx context.Context, client *api.Client, workflowKey string, flags runFilterFlags, sortBy string, sortOrder string, now time.Time) (runFilter, error) {
+	filter := runFilter{sortBy: sortBy, sortOrder: strings.ToLower(sortOrder)}
+
+	var err error
+	if filter.statuses, err = parseStatuses(flags.Status); err != nil {
+		return filter, err
+	}
+	if strings.TrimSpace(flags.Since) != "" {
+		if filter.since, err = parseTimeFlag("--since", flags.Since, now); err != nil {
+			return filter, lerrors.Usage(err)
+		}
+	}
+	if strings.TrimSpace(flags.Until) != "" {
+		if filter.until, err = parseTimeFlag("--until", flags.Until, now); err != nil {
+			return filter, lerrors.Usage(err)
+		}
+	}
+	if !filter.since.IsZero() && !filter.until.IsZero() && !filter.since.Before(filter.until) {
+		return filter, lerrors.Usage(fmt.Errorf("--since must be before --until"))
+	}
+	if flags.Version < 0 {
+		return filter, lerrors.Usage(fmt.Errorf("--version must be a positive number"))
+	}
+
+	if key := strings.TrimSpace(flags.Trigger); key != "" {
+		trigger, err := client.GetTriggerByKey(ctx, workflowKey, key)
+		if err != nil {
+			return filter, fmt.Errorf("resolve trigger %s: %w", key, err)
+		}
+		filter.triggerID = trigger.ID
+	}
+	if flags.Version > 0 {
+		version, err := client.GetWorkflowVersionByKey(ctx, workflowKey, strconv.Itoa(flags.Version))
+		if err != nil {
+			return filter, fmt.Errorf("resolve version %d: %w", flags.Version, err)
+		}
+		filter.versionID = version.ID
+	}
+	return filter, nil
+}
+
+func (f runFilter) active() bool {
+	return len(f.statuses) > 0 || !f.since.IsZero() || !f.until.IsZero() || f.triggerID != "" || f.versionID != ""
+}
+
+func (f runFilter) matches(run api.WorkflowRun) bool {
+	if len(f.statuses) > 0 && !slices.Contains(f.statuses, strings.ToUpper(run.Status)) {
+		return false
+	}
+	if f.triggerID != "" && (run.TriggerID == nil || *run.TriggerID != f.triggerID) {
+		return false
+	}
+	if f.versionID != "" && run.WorkflowVersionID != f.versionID {
+		return false
+	}
+	if !f.since.IsZero() || !f.until.IsZero() {
+		created, err := time.Parse(time.RFC3339Nano, run.CreatedAt)
+		if err != nil {
+			return false
+		}
+		if !f.since.IsZero() && created.Before(f.since) {
+			return false
+		}
+		if !f.until.IsZero() && !created.Before(f.until) {
+			return false
+		}
+	}
+	return true
+}
+
+// done reports that pages sorted by createdAt have moved past the time window,
+// so no later run can match.
+func (f runFilter) done(run api.WorkflowRun) bool {
+	if f.sortBy != "createdAt" {
+		return false
+	}
+	created, err := time.Parse(time.RFC3339Nano, run.CreatedAt)
+	if err != nil {
+		return false
+	}
+	if f.sortOrder == "desc" {
+		return !f.since.IsZero() && created.Before(f.since)
+	}
+	return !f.until.IsZero() && !created.Before(f.until)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+)
+
+func TestRunFilterMatchesStatusTriggerVersionAndWindow(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	statuses, err := parseStatuses([]string{"failed", "RUNNING"})
+	if err != nil {
+		t.Fatalf("statuses: %v", err)
+	}
+	filter := runFilter{
+		statuses:  statuses,
+		since:     now.Add(-24 * time.Hour),
+		triggerID: "trg_1",
+		versionID: "ver_2",
+		sortBy:    "createdAt",
+		sortOrder: "desc",
+	}
+
+	trigger := "trg_1"
+	run := api.WorkflowRun{Status: "FAILED", TriggerID: &trigger, WorkflowVersionID: "ver_2", CreatedAt: "2026-03-02T08:00:00Z"}
+	if !filter.matches(run) {
+		t.Fatalf("expected run to match %+v", filter)
+	}
+
+	for name, mutate := range map[string]func(*api.WorkflowRun){
+		"status":  func(r *api.WorkflowRun) { r.Status = "SUCCEEDED" },
+		"trigger": func(r *api.WorkflowRun) { r.TriggerID = nil },
+		"version": func(r *api.WorkflowRun) { r.WorkflowVersionID = "ver_1" },
+		"since":   func(r *api.WorkflowRun) { r.CreatedAt = "2026-02-28T08:00:00Z" },
+	} {
+		other := run
+		mutate(&other)
+		if filter.matches(other) {
+			t.Fatalf("expected %s mismatch to be filtered out", name)
+		}
+	}
+
+	old := api.WorkflowRun{CreatedAt: "2026-02-28T08:00:00Z"}
+	if !filter.done(old) || filter.done(run) {
+		t.Fatal("expected descending walk to stop at the first run before --since")
+	}
+	filter.sortOrder = "asc"
+	if filter.done(old) {
+		t.Fatal("expected ascending walk to continue past old runs")
+	}
+}
+
+func TestParseStatusesRejectsUnknownStatus(t *testing.T) {
+	if _, err := parseStatuses([]string{"DONE"}); err == nil {
+		t.Fatal("expected unknown status to fail")
+	}
+}
+
+func TestListViewFilterStopsAtDone(t *testing.T) {
+	view := listView[int]{
+		Filter: func(n int) bool { return n%2 == 0 },
+		Done:   func(n int) bool { return n > 5 },
+	}
+	kept, done := view.filter([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	if !done || len(kept) != 2 || kept[0] != 2 || kept[1] != 4 {
+		t.Fatalf("expected [2 4] and done, got %v %v", kept, done)
+	}
+}
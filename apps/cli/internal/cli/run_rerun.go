@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var runRerunSet []string
+var runRerunWait bool
+var runRerunTimeout time.Duration
+
+type rerunResult struct {
+	SourceRunNumber   int    `json:"sourceRunNumber"`
+	WorkflowRunNumber int    `json:"workflowRunNumber"`
+	WorkflowRunID     string `json:"workflowRunId"`
+	Status            string `json:"status"`
+	Input             any    `json:"input"`
+	Overrides         any    `json:"overrides"`
+}
+
+func runRerun(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	workflowKey := args[0]
+	runNumber, err := parsePositiveIntArg("run number", args[1])
+	if err != nil {
+		return err
+	}
+
+	original, err := ctx.Client.GetWorkflowRunByNumber(cmd.Context(), workflowKey, runNumber)
+	if err != nil {
+		return err
+	}
+
+	input, overrides := original.Input, original.Overrides
+	for _, assignment := range runRerunSet {
+		input, overrides, err = applyRerunSet(input, overrides, assignment)
+		if err != nil {
+			return err
+		}
+	}
+
+	queued, err := ctx.Client.RunWorkflowByKey(cmd.Context(), workflowKey, input, overrides)
+	if err != nil {
+		return err
+	}
+
+	if runRerunWait {
+		if !IsStructured(ctx) && !ctx.Quiet {
+			fmt.Fprintf(os.Stdout, "Re-ran run #%d as run #%d, waiting for it to finish...\n", runNumber, queued.WorkflowRunNumber)
+		}
+		return waitForRunOutcome(cmd, ctx, workflowKey, queued.WorkflowRunNumber, runRerunTimeout, runNumber)
+	}
+
+	result := rerunResult{
+		SourceRunNumber:   runNumber,
+		WorkflowRunNumber: queued.WorkflowRunNumber,
+		WorkflowRunID:     queued.WorkflowRunID,
+		Status:            queued.Status,
+		Input:             input,
+		Overrides:         overrides,
+	}
+	if IsStructured(ctx) {
+		return output.Print(result)
+	}
+	if ctx.Quiet {
+		fmt.Fprintln(os.Stdout, result.WorkflowRunNumber)
+		return nil
+	}
+
+	return output.PrintKVTable([][2]string{
+		{"sourceRunNumber", fmt.Sprintf("%d", result.SourceRunNumber)},
+		{"workflowRunNumber", fmt.Sprintf("%d", result.WorkflowRunNumber)},
+		{"workflowRunId", result.WorkflowRunID},
+		{"status", result.Status},
+	})
+}
+
+// applyRerunSet patches input or overrides with a path=value assignment such as
+// input.user.id=42. Values that parse as JSON keep their type; anything else is
+// used as a plain string.
+func applyRerunSet(input any, overrides any, assignment string) (any, any, error) {
+	path, raw, ok := strings.Cut(assignment, "=")
+	path = strings.TrimSpace(path)
+	if !ok || path == "" {
+		return input, overrides, fmt.Errorf("invalid --set %q: expected input.<field>=<value>", assignment)
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		value = raw
+	}
+
+	segments := strings.Split(path, ".")
+	if len(segments) < 2 {
+		return input, overrides, fmt.Errorf("invalid --set %q: expected input.<field>=<value>", assignment)
+	}
+
+	var err error
+	switch segments[0] {
+	case "input":
+		input, err = setPath(input, segments[1:], value)
+	case "overrides":
+		overrides, err = setPath(overrides, segments[1:], value)
+	default:
+		return input, overrides, fmt.Errorf("invalid --set %q: path must start with input or overrides", assignment)
+	}
+	if err != nil {
+		return input, overrides, fmt.Errorf("invalid --set %q: %w", assignment, err)
+	}
+	return input, overrides, nil
+}
+
+func setPath(target any, segments []string, value any) (any, error) {
+	if target == nil {
+		target = map[string]any{}
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot set field %q on a non-object value", segments[0])
+	}
+	if segments[0] == "" {
+		return nil, fmt.Errorf("empty field name")
+	}
+	if len(segments) == 1 {
+		object[segments[0]] = value
+		return object, nil
+	}
+
+	child, err := setPath(object[segments[0]], segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	object[segments[0]] = child
+	return object, nil
+}
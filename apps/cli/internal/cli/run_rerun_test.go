@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+)
+
+func TestApplyRerunSetPatchesInput(t *testing.T) {
+	input := any(map[string]any{"user": map[string]any{"id": float64(1)}, "dryRun": true})
+	overrides := any(nil)
+
+	var err error
+	for _, assignment := range []string{"input.user.id=42", "input.dryRun=false", "input.note=retry after fix", "overrides.fetch.query.page=2"} {
+		input, overrides, err = applyRerunSet(input, overrides, assignment)
+		if err != nil {
+			t.Fatalf("%s: %v", assignment, err)
+		}
+	}
+
+	wantInput := map[string]any{"user": map[string]any{"id": float64(42)}, "dryRun": false, "note": "retry after fix"}
+	if !reflect.DeepEqual(input, wantInput) {
+		t.Fatalf("unexpected input: %#v", input)
+	}
+	wantOverrides := map[string]any{"fetch": map[string]any{"query": map[string]any{"page": float64(2)}}}
+	if !reflect.DeepEqual(overrides, wantOverrides) {
+		t.Fatalf("unexpected overrides: %#v", overrides)
+	}
+}
+
+func TestApplyRerunSetRejectsInvalidAssignments(t *testing.T) {
+	cases := map[string]string{
+		"input.foo":          "expected input.<field>=<value>",
+		"input=1":            "expected input.<field>=<value>",
+		"output.foo=1":       "must start with input or overrides",
+		"input.tags.first=x": "non-object value",
+	}
+	for assignment, want := range cases {
+		_, _, err := applyRerunSet(map[string]any{"tags": []any{"a"}}, nil, assignment)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected error containing %q, got %v", assignment, want, err)
+		}
+	}
+}
+
+func TestRunOutcomeLinksSourceRun(t *testing.T) {
+	data, err := json.Marshal(runOutcome{SourceRunNumber: 41, Run: api.WorkflowRun{Number: 42}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"sourceRunNumber":41`) {
+		t.Fatalf("expected source run number, got %s", data)
+	}
+
+	data, err = json.Marshal(runOutcome{Run: api.WorkflowRun{Number: 42}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(data), "sourceRunNumber") {
+		t.Fatalf("expected no source run for a new run, got %s", data)
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type runOutcome struct {
+	SourceRunNumber int              `json:"sourceRunNumber,omitempty"`
+	Run             api.WorkflowRun  `json:"run"`
+	FailedSteps     []stepRunSummary `json:"failedSteps"`
+}
+
+// waitForRunOutcome waits for the run and prints its outcome. sourceRunNumber
+// links a re-run to the run it was copied from; pass 0 for new runs.
+func waitForRunOutcome(cmd *cobra.Command, ctx *Context, workflowKey string, runNumber int, timeout time.Duration, sourceRunNumber int) error {
+	waitCtx := cmd.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(waitCtx, timeout)
+		defer cancel()
+	}
+
+	progress := !IsStructured(ctx) && !ctx.Quiet
+	snapshot, err := watchRun(waitCtx, ctx.Client, workflowKey, runNumber, defaultRunWatchInterval, func(_ runSnapshot, events []runWatchEvent) error {
+		if !progress {
+			return nil
+		}
+		return printRunWatchTransitions(events)
+	})
+	if err != nil {
+		// A single slow poll also ends in DeadlineExceeded; only the --timeout
+		// deadline means the run itself took too long.
+		if timeout > 0 && errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			return &exitError{
+				Code: exitCodeRunTimeout,
+				Err:  fmt.Errorf("timed out after %s waiting for run #%d", timeout, runNumber),
+			}
+		}
+		return err
+	}
+
+	if err := printRunOutcome(ctx, snapshot, sourceRunNumber); err != nil {
+		return err
+	}
+
+	if strings.ToUpper(snapshot.Run.Status) == "SUCCEEDED" {
+		return nil
+	}
+	return &exitError{
+		Code: exitCodeRunFailed,
+		Err:  fmt.Errorf("run #%d finished with status %s", snapshot.Run.Number, snapshot.Run.Status),
+	}
+}
+
+func printRunOutcome(ctx *Context, snapshot runSnapshot, sourceRunNumber int) error {
+	failed := failedStepRuns(snapshot)
+
+	if IsStructured(ctx) {
+		return output.Print(runOutcome{SourceRunNumber: sourceRunNumber, Run: snapshot.Run, FailedSteps: failed})
+	}
+	if ctx.Quiet {
+		fmt.Fprintln(os.Stdout, snapshot.Run.Status)
+		return nil
+	}
+
+	finished := ""
+	if snapshot.Run.FinishedAt != nil {
+		finished = *snapshot.Run.FinishedAt
+	}
+	rows := [][2]string{}
+	if sourceRunNumber > 0 {
+		rows = append(rows, [2]string{"sourceRunNumber", fmt.Sprintf("%d", sourceRunNumber)})
+	}
+	rows = append(rows,
+		[2]string{"workflowRunNumber", fmt.Sprintf("%d", snapshot.Run.Number)},
+		[2]string{"status", output.ColorStatus(snapshot.Run.Status)},
+		[2]string{"finishedAt", finished},
+	)
+	if err := output.PrintKVTable(rows); err != nil {
+		return err
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(os.Stdout)
+	failedRows := make([][]string, 0, len(failed))
+	for _, step := range failed {
+		failedRows = append(failedRows, []string{step.StepKey, fmt.Sprintf("%d", step.Attempt), stepErrorLabel(step.Error)})
+	}
+	return output.PrintListTable([]string{"FAILED_STEP", "ATTEMPT", "ERROR"}, failedRows)
+}
+
+func failedStepRuns(snapshot runSnapshot) []stepRunSummary {
+	failed := []stepRunSummary{}
+	for _, step := range snapshot.Steps {
+		if strings.ToUpper(step.Status) != "FAILED" {
+			continue
+		}
+		failed = append(failed, stepRunSummary{StepKey: step.StepKey, Attempt: step.Attempt, Error: step.Error})
+	}
+	return failed
+}
+
+type stepRunSummary struct {
+	StepKey string `json:"stepKey"`
+	Attempt int    `json:"attempt"`
+	Error   any    `json:"error"`
+}
+
+func stepErrorLabel(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any:
+		if message, ok := v["message"].(string); ok && strings.TrimSpace(message) != "" {
+			return message
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+func TestWaitForRunOutcomeKeepsRequestTimeoutsApart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "token")
+	client.RequestTimeout = 20 * time.Millisecond
+	client.Retry = api.RetryPolicy{MaxAttempts: 1}
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	err := waitForRunOutcome(cmd, &Context{Client: client, Quiet: true}, "sync", 7, time.Minute, 0)
+	var exit *exitError
+	if errors.As(err, &exit) {
+		t.Fatalf("expected a request timeout, got run outcome %v", err)
+	}
+	if got := exitCode(err); got != lerrors.ExitTimeout {
+		t.Fatalf("expected exit code %d, got %d (%v)", lerrors.ExitTimeout, got, err)
+	}
+}
+
+func TestWaitForRunOutcomeReportsWaitTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "token")
+	client.Retry = api.RetryPolicy{MaxAttempts: 1}
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	err := waitForRunOutcome(cmd, &Context{Client: client, Quiet: true}, "sync", 7, 20*time.Millisecond, 0)
+	if got := exitCode(err); got != exitCodeRunTimeout {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitCodeRunTimeout, got, err)
+	}
+}
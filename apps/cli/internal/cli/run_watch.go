@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const defaultRunWatchInterval = 2 * time.Second
+
+var runWatchInterval time.Duration
+
+type runSnapshot struct {
+	Run   api.WorkflowRun
+	Steps []api.StepRun
+}
+
+type runWatchEvent struct {
+	Type           string `json:"type"`
+	WorkflowKey    string `json:"workflowKey"`
+	RunNumber      int    `json:"runNumber"`
+	StepKey        string `json:"stepKey,omitempty"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	Attempt        int    `json:"attempt,omitempty"`
+	Timestamp      string `json:"timestamp"`
+}
+
+func isTerminalRunStatus(status string) bool {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "SUCCEEDED", "FAILED", "CANCELLED", "CANCELED":
+		return true
+	default:
+		return false
+	}
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	workflowKey := args[0]
+	runNumber, err := parsePositiveIntArg("run number", args[1])
+	if err != nil {
+		return err
+	}
+
+	live := !IsStructured(ctx) && !ctx.Quiet && term.IsTerminal(int(os.Stdout.Fd()))
+	_, err = watchRun(cmd.Context(), ctx.Client, workflowKey, runNumber, runWatchInterval, func(snapshot runSnapshot, events []runWatchEvent) error {
+		switch {
+		case IsStructured(ctx):
+			return printRunWatchEvents(events)
+		case ctx.Quiet:
+			for _, event := range events {
+				if event.Type == "run" && isTerminalRunStatus(event.Status) {
+					fmt.Fprintln(os.Stdout, event.Status)
+				}
+			}
+			return nil
+		case live:
+			fmt.Fprint(os.Stdout, "\x1b[H\x1b[2J")
+			return printRunWatchTable(workflowKey, snapshot)
+		default:
+			return printRunWatchTransitions(events)
+		}
+	})
+	return err
+}
+
+func watchRun(ctx context.Context, client *api.Client, workflowKey string, runNumber int, interval time.Duration, onUpdate func(runSnapshot, []runWatchEvent) error) (runSnapshot, error) {
+	if interval <= 0 {
+		interval = defaultRunWatchInterval
+	}
+
+	var previous *runSnapshot
+	for {
+		snapshot, err := fetchRunSnapshot(ctx, client, workflowKey, runNumber)
+		if err != nil {
+			return runSnapshot{}, err
+		}
+
+		events := runTransitions(workflowKey, previous, snapshot, time.Now())
+		if previous == nil || len(events) > 0 {
+			if err := onUpdate(snapshot, events); err != nil {
+				return snapshot, err
+			}
+		}
+		if isTerminalRunStatus(snapshot.Run.Status) {
+			return snapshot, nil
+		}
+		previous = &snapshot
+
+		select {
+		case <-ctx.Done():
+			return snapshot, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func fetchRunSnapshot(ctx context.Context, client *api.Client, workflowKey string, runNumber int) (runSnapshot, error) {
+	run, err := client.GetWorkflowRunByNumber(ctx, workflowKey, runNumber)
+	if err != nil {
+		return runSnapshot{}, err
+	}
+
+	steps, err := api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.StepRun], error) {
+		return client.ListStepRunsByWorkflowKeyAndRunNumber(ctx, workflowKey, runNumber, page, api.MaxPageSize, "createdAt", "asc")
+	})
+	if err != nil {
+		return runSnapshot{}, err
+	}
+
+	return runSnapshot{Run: run, Steps: steps}, nil
+}
+
+func runTransitions(workflowKey string, previous *runSnapshot, next runSnapshot, now time.Time) []runWatchEvent {
+	timestamp := now.UTC().Format(time.RFC3339)
+	events := []runWatchEvent{}
+
+	previousRunStatus := ""
+	previousSteps := map[string]api.StepRun{}
+	if previous != nil {
+		previousRunStatus = previous.Run.Status
+		for _, step := range previous.Steps {
+			previousSteps[step.StepKey] = step
+		}
+	}
+
+	for _, step := range next.Steps {
+		before, seen := previousSteps[step.StepKey]
+		if seen && before.Status == step.Status && before.Attempt == step.Attempt {
+			continue
+		}
+		events = append(events, runWatchEvent{
+			Type:           "step",
+			WorkflowKey:    workflowKey,
+			RunNumber:      next.Run.Number,
+			StepKey:        step.StepKey,
+			Status:         step.Status,
+			PreviousStatus: before.Status,
+			Attempt:        step.Attempt,
+			Timestamp:      timestamp,
+		})
+	}
+
+	if previousRunStatus != next.Run.Status {
+		events = append(events, runWatchEvent{
+			Type:           "run",
+			WorkflowKey:    workflowKey,
+			RunNumber:      next.Run.Number,
+			Status:         next.Run.Status,
+			PreviousStatus: previousRunStatus,
+			Timestamp:      timestamp,
+		})
+	}
+
+	return events
+}
+
+func printRunWatchEvents(events []runWatchEvent) error {
+	for _, event := range events {
+		if err := output.PrintRecord(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printRunWatchTransitions(events []runWatchEvent) error {
+	for _, event := range events {
+		subject := fmt.Sprintf("run #%d", event.RunNumber)
+		if event.Type == "step" {
+			subject = "step " + event.StepKey
+		}
+		status := output.ColorStatus(event.Status)
+		if event.PreviousStatus != "" {
+			status = event.PreviousStatus + " -> " + status
+		}
+		if _, err := fmt.Fprintf(os.Stdout, "%s  %s  %s\n", event.Timestamp, subject, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printRunWatchTable(workflowKey string, snapshot runSnapshot) error {
+	fmt.Fprintf(os.Stdout, "%s · run #%d · %s\n\n", workflowKey, snapshot.Run.Number, output.ColorStatus(snapshot.Run.Status))
+
+	rows := make([][]string, 0, len(snapshot.Steps))
+	for _, step := range snapshot.Steps {
+		rows = append(rows, []string{
+			step.StepKey,
+			output.ColorStatus(step.Status),
+			fmt.Sprintf("%d", step.Attempt),
+			stepDurationLabel(step),
+		})
+	}
+	return output.PrintListTable([]string{"STEP_KEY", "STATUS", "ATTEMPT", "DURATION"}, rows)
+}
+
+func stepDurationLabel(step api.StepRun) string {
+	if step.DurationMs != nil {
+		return (time.Duration(*step.DurationMs) * time.Millisecond).String()
+	}
+	return elapsedLabel(step.StartedAt, step.FinishedAt)
+}
+
+// elapsedLabel is the time from started to finished, or to now while still
+// running. It is empty before the start.
+func elapsedLabel(startedAt *string, finishedAt *string) string {
+	if startedAt == nil {
+		return ""
+	}
+	started, err := time.Parse(time.RFC3339Nano, *startedAt)
+	if err != nil {
+		return ""
+	}
+	end := time.Now()
+	if finishedAt != nil {
+		if finished, err := time.Parse(time.RFC3339Nano, *finishedAt); err == nil {
+			end = finished
+		}
+	}
+	return end.Sub(started).Round(time.Second).String()
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+)
+
+func TestRunTransitionsReportsEverythingOnFirstPoll(t *testing.T) {
+	snapshot := runSnapshot{
+		Run:   api.WorkflowRun{Number: 7, Status: "RUNNING"},
+		Steps: []api.StepRun{{StepKey: "fetch", Status: "SUCCEEDED", Attempt: 1}},
+	}
+
+	events := runTransitions("sync-crm", nil, snapshot, time.Now())
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != "step" || events[0].StepKey != "fetch" || events[0].PreviousStatus != "" {
+		t.Fatalf("unexpected step event: %#v", events[0])
+	}
+	if events[1].Type != "run" || events[1].Status != "RUNNING" || events[1].RunNumber != 7 {
+		t.Fatalf("unexpected run event: %#v", events[1])
+	}
+}
+
+func TestRunTransitionsOnlyReportsChanges(t *testing.T) {
+	previous := runSnapshot{
+		Run: api.WorkflowRun{Number: 7, Status: "RUNNING"},
+		Steps: []api.StepRun{
+			{StepKey: "fetch", Status: "SUCCEEDED", Attempt: 1},
+			{StepKey: "load", Status: "RUNNING", Attempt: 1},
+		},
+	}
+	next := runSnapshot{
+		Run: api.WorkflowRun{Number: 7, Status: "RUNNING"},
+		Steps: []api.StepRun{
+			{StepKey: "fetch", Status: "SUCCEEDED", Attempt: 1},
+			{StepKey: "load", Status: "FAILED", Attempt: 1},
+		},
+	}
+
+	events := runTransitions("sync-crm", &previous, next, time.Now())
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %#v", events)
+	}
+	if events[0].StepKey != "load" || events[0].PreviousStatus != "RUNNING" || events[0].Status != "FAILED" {
+		t.Fatalf("unexpected event: %#v", events[0])
+	}
+}
+
+func TestIsTerminalRunStatus(t *testing.T) {
+	for _, status := range []string{"SUCCEEDED", "FAILED", "CANCELLED", "failed"} {
+		if !isTerminalRunStatus(status) {
+			t.Fatalf("expected %q to be terminal", status)
+		}
+	}
+	for _, status := range []string{"QUEUED", "RUNNING", ""} {
+		if isTerminalRunStatus(status) {
+			t.Fatalf("expected %q to be non-terminal", status)
+		}
+	}
+}
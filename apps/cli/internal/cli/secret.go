@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -15,8 +16,7 @@ var secretCmd = &cobra.Command{
 	Short: "Manage secrets",
 }
 
-var secretListPage int
-var secretListPageSize int
+var secretListOptions listOptions
 var secretListSortBy string
 var secretListSortOrder string
 var secretCreateName string
@@ -32,8 +32,7 @@ func init() {
 		Short: "List secrets",
 		RunE:  secretList,
 	}
-	listCmd.Flags().IntVar(&secretListPage, "page", 1, "Page number")
-	listCmd.Flags().IntVar(&secretListPageSize, "page-size", 25, "Page size")
+	addListFlags(listCmd, &secretListOptions)
 	listCmd.Flags().StringVar(&secretListSortBy, "sort-by", "createdAt", "Sort field (createdAt|updatedAt)")
 	listCmd.Flags().StringVar(&secretListSortOrder, "sort-order", "desc", "Sort order (asc|desc)")
 
@@ -85,30 +84,19 @@ func secretList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("missing context")
 	}
 
-	result, err := ctx.Client.ListSecrets(secretListPage, secretListPageSize, secretListSortBy, secretListSortOrder)
-	if err != nil {
-		return err
-	}
-
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
-	}
-
-	if ctx.Quiet {
-		for _, item := range result.Items {
-			fmt.Fprintln(os.Stdout, item.Name)
-		}
-		return nil
-	}
-
-	rows := make([][]string, 0, len(result.Items))
-	for _, item := range result.Items {
-		rows = append(rows, []string{item.Name, item.ID, item.CreatedAt, item.UpdatedAt})
-	}
-	if err := output.PrintListTable([]string{"NAME", "ID", "CREATED", "UPDATED"}, rows); err != nil {
-		return err
-	}
-	return output.PrintPagination(result.Pagination)
+	return printList(cmd, ctx, secretListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.Secret], error) {
+			return ctx.Client.ListSecrets(reqCtx, page, pageSize, secretListSortBy, secretListSortOrder)
+		},
+		listView[api.Secret]{
+			Headers: []string{"NAME", "ID", "CREATED", "UPDATED"},
+			Row: func(item api.Secret) []string {
+				return []string{item.Name, item.ID, item.CreatedAt, item.UpdatedAt}
+			},
+			Ref:        func(item api.Secret) string { return item.Name },
+			Pagination: true,
+		},
+	)
 }
 
 func secretGet(cmd *cobra.Command, args []string) error {
@@ -117,12 +105,12 @@ func secretGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("missing context")
 	}
 
-	secretID, err := resolveSecretIdentifier(ctx, args[0])
+	secretID, err := resolveSecretIdentifier(cmd, ctx, args[0])
 	if err != nil {
 		return err
 	}
 
-	result, err := ctx.Client.GetSecret(secretID)
+	result, err := ctx.Client.GetSecret(cmd.Context(), secretID)
 	if err != nil {
 		return err
 	}
@@ -138,7 +126,7 @@ func secretCreate(cmd *cobra.Command, args []string) error {
 
 	payload := secretCreatePayload(cmd)
 
-	result, err := ctx.Client.CreateSecret(payload)
+	result, err := ctx.Client.CreateSecret(cmd.Context(), payload)
 	if err != nil {
 		return err
 	}
@@ -179,12 +167,12 @@ func secretUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no fields to update")
 	}
 
-	secretID, err := resolveSecretIdentifier(ctx, args[0])
+	secretID, err := resolveSecretIdentifier(cmd, ctx, args[0])
 	if err != nil {
 		return err
 	}
 
-	result, err := ctx.Client.UpdateSecret(secretID, patch)
+	result, err := ctx.Client.UpdateSecret(cmd.Context(), secretID, patch)
 	if err != nil {
 		return err
 	}
@@ -198,12 +186,12 @@ func secretDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("missing context")
 	}
 
-	secretID, err := resolveSecretIdentifier(ctx, args[0])
+	secretID, err := resolveSecretIdentifier(cmd, ctx, args[0])
 	if err != nil {
 		return err
 	}
 
-	result, err := ctx.Client.DeleteSecret(secretID)
+	result, err := ctx.Client.DeleteSecret(cmd.Context(), secretID)
 	if err != nil {
 		return err
 	}
@@ -212,8 +200,8 @@ func secretDelete(cmd *cobra.Command, args []string) error {
 }
 
 func printSecret(ctx *Context, result api.Secret) error {
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 	if ctx.Quiet {
 		fmt.Fprintln(os.Stdout, result.Name)
@@ -234,7 +222,7 @@ func printSecret(ctx *Context, result api.Secret) error {
 	})
 }
 
-func resolveSecretIdentifier(ctx *Context, ref string) (string, error) {
+func resolveSecretIdentifier(cmd *cobra.Command, ctx *Context, ref string) (string, error) {
 	if ctx == nil || ctx.Client == nil {
 		return "", fmt.Errorf("missing context")
 	}
@@ -244,23 +232,16 @@ func resolveSecretIdentifier(ctx *Context, ref string) (string, error) {
 		return "", fmt.Errorf("missing secret name")
 	}
 
-	page := 1
-	for {
-		result, err := ctx.Client.ListSecrets(page, 100, "createdAt", "desc")
+	secrets := api.Paginate(cmd.Context(), func(reqCtx context.Context, page int) (api.Paginated[api.Secret], error) {
+		return ctx.Client.ListSecrets(reqCtx, page, api.MaxPageSize, "createdAt", "desc")
+	})
+	for item, err := range secrets {
 		if err != nil {
 			return "", err
 		}
-
-		for _, item := range result.Items {
-			if item.Name == ref || item.ID == ref {
-				return item.ID, nil
-			}
-		}
-
-		if !result.Pagination.HasNext {
-			break
+		if item.Name == ref || item.ID == ref {
+			return item.ID, nil
 		}
-		page++
 	}
 
 	return "", fmt.Errorf("secret not found: %s", ref)
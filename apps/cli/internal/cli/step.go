@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/gentij/lunie/apps/cli/internal/api"
 	"github.com/gentij/lunie/apps/cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -13,8 +17,7 @@ var stepCmd = &cobra.Command{
 	Short: "Manage step runs",
 }
 
-var stepListPage int
-var stepListPageSize int
+var stepListOptions listOptions
 var stepListSortBy string
 var stepListSortOrder string
 
@@ -25,10 +28,10 @@ func init() {
 		Args:  cobra.ExactArgs(2),
 		RunE:  stepList,
 	}
-	listCmd.Flags().IntVar(&stepListPage, "page", 1, "Page number")
-	listCmd.Flags().IntVar(&stepListPageSize, "page-size", 25, "Page size")
+	addListFlags(listCmd, &stepListOptions)
 	listCmd.Flags().StringVar(&stepListSortBy, "sort-by", "createdAt", "Sort field (createdAt|updatedAt)")
 	listCmd.Flags().StringVar(&stepListSortOrder, "sort-order", "asc", "Sort order (asc|desc)")
+	addStatusFlag(listCmd, &stepListStatus)
 
 	getCmd := &cobra.Command{
 		Use:   "get <workflow-key> <run-number> <step-key>",
@@ -37,8 +40,20 @@ func init() {
 		RunE:  stepGet,
 	}
 
+	logsCmd := &cobra.Command{
+		Use:   "logs <workflow-key> <run-number> [step-key]",
+		Short: "Show step run logs",
+		Long:  "Show step run logs, prefixed with the step key. Log lines carry no timestamps, so logs are grouped per step in the order the steps were created; with --follow, new lines are printed as they arrive, interleaving running steps.",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE:  stepLogs,
+	}
+	logsCmd.Flags().BoolVar(&stepLogsFollow, "follow", false, "Keep printing new log lines until the run finishes")
+	logsCmd.Flags().StringVar(&stepLogsGrep, "grep", "", "Only show lines matching a regular expression")
+	logsCmd.Flags().StringVar(&stepLogsSince, "since", "", "Only show logs of steps updated since a duration (15m) or RFC3339 timestamp; whole steps are filtered, not lines")
+
 	stepCmd.AddCommand(listCmd)
 	stepCmd.AddCommand(getCmd)
+	stepCmd.AddCommand(logsCmd)
 }
 
 func stepList(cmd *cobra.Command, args []string) error {
@@ -53,34 +68,39 @@ func stepList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := ctx.Client.ListStepRunsByWorkflowKeyAndRunNumber(workflowKey, runNumber, stepListPage, stepListPageSize, stepListSortBy, stepListSortOrder)
+	statuses, err := parseStatuses(stepListStatus)
 	if err != nil {
 		return err
 	}
 
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
-	}
-
-	if ctx.Quiet {
-		for _, item := range result.Items {
-			fmt.Fprintln(os.Stdout, item.StepKey)
+	view := listView[api.StepRun]{
+		Headers: []string{"STEP_KEY", "STATUS", "STARTED"},
+		Row: func(item api.StepRun) []string {
+			started := ""
+			if item.StartedAt != nil {
+				started = *item.StartedAt
+			}
+			return []string{item.StepKey, item.Status, started}
+		},
+		WideHeaders: []string{"ID", "ATTEMPT", "FINISHED", "DURATION"},
+		WideRow: func(item api.StepRun) []string {
+			return []string{item.ID, fmt.Sprintf("%d", item.Attempt), stringValue(item.FinishedAt), stepDurationLabel(item)}
+		},
+		Ref:        func(item api.StepRun) string { return item.StepKey },
+		Pagination: true,
+	}
+	if len(statuses) > 0 {
+		view.Filter = func(item api.StepRun) bool {
+			return slices.Contains(statuses, strings.ToUpper(item.Status))
 		}
-		return nil
 	}
 
-	rows := make([][]string, 0, len(result.Items))
-	for _, item := range result.Items {
-		started := ""
-		if item.StartedAt != nil {
-			started = *item.StartedAt
-		}
-		rows = append(rows, []string{item.StepKey, item.Status, started})
-	}
-	if err := output.PrintListTable([]string{"STEP_KEY", "STATUS", "STARTED"}, rows); err != nil {
-		return err
-	}
-	return output.PrintPagination(result.Pagination)
+	return printList(cmd, ctx, stepListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.StepRun], error) {
+			return ctx.Client.ListStepRunsByWorkflowKeyAndRunNumber(reqCtx, workflowKey, runNumber, page, pageSize, stepListSortBy, stepListSortOrder)
+		},
+		view,
+	)
 }
 
 func stepGet(cmd *cobra.Command, args []string) error {
@@ -96,13 +116,13 @@ func stepGet(cmd *cobra.Command, args []string) error {
 	}
 
 	stepKey := args[2]
-	result, err := ctx.Client.GetStepRunByStepKey(workflowKey, runNumber, stepKey)
+	result, err := ctx.Client.GetStepRunByStepKey(cmd.Context(), workflowKey, runNumber, stepKey)
 	if err != nil {
 		return err
 	}
 
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 	if ctx.Quiet {
 		fmt.Fprintln(os.Stdout, result.StepKey)
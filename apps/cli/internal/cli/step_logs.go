@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var stepLogsFollow bool
+var stepLogsGrep string
+var stepLogsSince string
+
+type stepLogLine struct {
+	StepKey string `json:"stepKey"`
+	Attempt int    `json:"attempt"`
+	Line    string `json:"line"`
+}
+
+type stepLogFilter struct {
+	StepKey string
+	Pattern *regexp.Regexp
+	Since   time.Time
+}
+
+func stepLogs(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	workflowKey := args[0]
+	runNumber, err := parsePositiveIntArg("run number", args[1])
+	if err != nil {
+		return err
+	}
+
+	filter := stepLogFilter{}
+	if len(args) > 2 {
+		filter.StepKey = strings.TrimSpace(args[2])
+	}
+	if strings.TrimSpace(stepLogsGrep) != "" {
+		pattern, err := regexp.Compile(stepLogsGrep)
+		if err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		filter.Pattern = pattern
+	}
+	if strings.TrimSpace(stepLogsSince) != "" {
+		since, err := parseSince(stepLogsSince, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = since
+	}
+
+	if !stepLogsFollow {
+		snapshot, err := fetchRunSnapshot(cmd.Context(), ctx.Client, workflowKey, runNumber)
+		if err != nil {
+			return err
+		}
+		steps := filter.steps(snapshot.Steps)
+		if filter.StepKey != "" && len(steps) == 0 && filter.Since.IsZero() {
+			return fmt.Errorf("step not found: %s", filter.StepKey)
+		}
+		return printStepLogLines(ctx, filter.lines(steps, map[string]string{}), stepKeyWidth(steps))
+	}
+
+	printed := map[string]string{}
+	_, err = watchRun(cmd.Context(), ctx.Client, workflowKey, runNumber, defaultRunWatchInterval, func(snapshot runSnapshot, _ []runWatchEvent) error {
+		steps := filter.steps(snapshot.Steps)
+		return printStepLogLines(ctx, filter.lines(steps, printed), stepKeyWidth(steps))
+	})
+	return err
+}
+
+func (f stepLogFilter) steps(items []api.StepRun) []api.StepRun {
+	steps := make([]api.StepRun, 0, len(items))
+	for _, step := range items {
+		if f.StepKey != "" && step.StepKey != f.StepKey {
+			continue
+		}
+		if !f.Since.IsZero() {
+			updated, err := time.Parse(time.RFC3339Nano, step.UpdatedAt)
+			if err == nil && updated.Before(f.Since) {
+				continue
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func (f stepLogFilter) lines(steps []api.StepRun, printed map[string]string) []stepLogLine {
+	lines := []stepLogLine{}
+	for _, step := range steps {
+		text := step.LogText()
+		previous := printed[step.StepKey]
+		if text == previous {
+			continue
+		}
+		printed[step.StepKey] = text
+
+		fresh := text
+		if previous != "" && strings.HasPrefix(text, previous) {
+			fresh = strings.TrimPrefix(strings.TrimPrefix(text, previous), "\n")
+		}
+		for _, line := range strings.Split(strings.TrimRight(fresh, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			if f.Pattern != nil && !f.Pattern.MatchString(line) {
+				continue
+			}
+			lines = append(lines, stepLogLine{StepKey: step.StepKey, Attempt: step.Attempt, Line: line})
+		}
+	}
+	return lines
+}
+
+func printStepLogLines(ctx *Context, lines []stepLogLine, width int) error {
+	for _, line := range lines {
+		if IsStructured(ctx) {
+			if err := output.PrintRecord(line); err != nil {
+				return err
+			}
+			continue
+		}
+		if ctx.Quiet {
+			fmt.Fprintln(os.Stdout, line.Line)
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "%-*s | %s\n", width, line.StepKey, line.Line)
+	}
+	return nil
+}
+
+func stepKeyWidth(steps []api.StepRun) int {
+	width := 0
+	for _, step := range steps {
+		if len(step.StepKey) > width {
+			width = len(step.StepKey)
+		}
+	}
+	return width
+}
+
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	return parseTimeFlag("--since", raw, now)
+}
+
+// parseTimeFlag accepts a duration before now (15m) or an RFC3339 timestamp.
+func parseTimeFlag(name string, raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if duration, err := time.ParseDuration(raw); err == nil {
+		if duration < 0 {
+			return time.Time{}, fmt.Errorf("invalid %s: %s", name, raw)
+		}
+		return now.Add(-duration), nil
+	}
+	if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+		return ts, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s: %s (use a duration like 15m or an RFC3339 timestamp)", name, raw)
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+)
+
+func TestStepLogFilterLinesOnlyReturnsNewOutput(t *testing.T) {
+	filter := stepLogFilter{}
+	printed := map[string]string{}
+
+	first := filter.lines([]api.StepRun{{StepKey: "fetch", Logs: "starting\nrequesting"}}, printed)
+	if len(first) != 2 {
+		t.Fatalf("expected 2 lines, got %#v", first)
+	}
+
+	second := filter.lines([]api.StepRun{{StepKey: "fetch", Logs: "starting\nrequesting\ndone"}}, printed)
+	if len(second) != 1 || second[0].Line != "done" {
+		t.Fatalf("expected only the appended line, got %#v", second)
+	}
+
+	third := filter.lines([]api.StepRun{{StepKey: "fetch", Logs: "starting\nrequesting\ndone"}}, printed)
+	if len(third) != 0 {
+		t.Fatalf("expected no lines for unchanged logs, got %#v", third)
+	}
+}
+
+func TestStepLogFilterAppliesGrepAndStepKey(t *testing.T) {
+	filter := stepLogFilter{StepKey: "load", Pattern: regexp.MustCompile("error")}
+	steps := filter.steps([]api.StepRun{
+		{StepKey: "fetch", Logs: "error in fetch"},
+		{StepKey: "load", Logs: "ok\nerror in load"},
+	})
+
+	lines := filter.lines(steps, map[string]string{})
+	if len(lines) != 1 || lines[0].StepKey != "load" || lines[0].Line != "error in load" {
+		t.Fatalf("unexpected lines: %#v", lines)
+	}
+}
+
+func TestParseSinceAcceptsDurationAndTimestamp(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseSince("15m", now)
+	if err != nil || !got.Equal(now.Add(-15*time.Minute)) {
+		t.Fatalf("unexpected duration result: %v, %v", got, err)
+	}
+
+	got, err = parseSince("2026-01-02T10:00:00Z", now)
+	if err != nil || got.Hour() != 10 {
+		t.Fatalf("unexpected timestamp result: %v, %v", got, err)
+	}
+
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Fatal("expected invalid --since to fail")
+	}
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage API tokens",
+}
+
+var tokenListOptions listOptions
+var tokenListSortOrder string
+var tokenListActive bool
+var tokenCreateName string
+var tokenCreateScopes []string
+
+func init() {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List API tokens",
+		RunE:  tokenList,
+	}
+	addListFlags(listCmd, &tokenListOptions)
+	listCmd.Flags().StringVar(&tokenListSortOrder, "sort-order", "desc", "Sort order by creation time (asc|desc)")
+	listCmd.Flags().BoolVar(&tokenListActive, "active", false, "Only list tokens that are not revoked")
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API token",
+		Long:  "Create an API token. The token is printed once and cannot be retrieved again. A token without scopes has full access; a scoped token needs <resource>:read for reads and <resource>:write for changes. A scoped token can only create tokens with a subset of its own scopes.",
+		RunE:  tokenCreate,
+	}
+	createCmd.Flags().StringVar(&tokenCreateName, "name", "", "Token name")
+	createCmd.Flags().StringSliceVar(&tokenCreateScopes, "scope", nil, "Scope granted to the token, comma-separated or repeated ("+strings.Join(api.APITokenScopes, "|")+")")
+	_ = createCmd.MarkFlagRequired("name")
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke <token-name-or-id>",
+		Short: "Revoke an API token",
+		Args:  cobra.ExactArgs(1),
+		RunE:  tokenRevoke,
+	}
+
+	tokenCmd.AddCommand(listCmd)
+	tokenCmd.AddCommand(createCmd)
+	tokenCmd.AddCommand(revokeCmd)
+}
+
+func tokenList(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	view := listView[api.APIToken]{
+		Headers: []string{"NAME", "ID", "SCOPES", "STATUS", "CREATED", "LAST USED"},
+		Row: func(item api.APIToken) []string {
+			return []string{item.Name, item.ID, tokenScopesLabel(item.Scopes), apiTokenStatus(item), item.CreatedAt, stringValue(item.LastUsedAt)}
+		},
+		Ref:        func(item api.APIToken) string { return item.Name },
+		Pagination: true,
+	}
+	if tokenListActive {
+		view.Filter = func(item api.APIToken) bool { return item.RevokedAt == nil }
+	}
+
+	return printList(cmd, ctx, tokenListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.APIToken], error) {
+			return ctx.Client.ListAPITokens(reqCtx, page, pageSize, tokenListSortOrder)
+		},
+		view,
+	)
+}
+
+func tokenCreate(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	name := strings.TrimSpace(tokenCreateName)
+	if name == "" {
+		return fmt.Errorf("missing token name")
+	}
+
+	scopes := normalizeScopes(tokenCreateScopes)
+	for _, scope := range scopes {
+		if !slices.Contains(api.APITokenScopes, scope) {
+			return lerrors.Usage(fmt.Errorf("unknown scope %q (%s)", scope, strings.Join(api.APITokenScopes, "|")))
+		}
+	}
+
+	result, err := ctx.Client.CreateAPIToken(cmd.Context(), name, scopes)
+	if err != nil {
+		return err
+	}
+
+	if IsStructured(ctx) {
+		return output.Print(result)
+	}
+	if ctx.Quiet {
+		fmt.Fprintln(os.Stdout, result.Token)
+		return nil
+	}
+
+	if err := output.PrintKVTable([][2]string{
+		{"id", result.ID},
+		{"name", result.Name},
+		{"scopes", tokenScopesLabel(result.Scopes)},
+		{"token", result.Token},
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Store this token now; it will not be shown again.")
+	return nil
+}
+
+func tokenRevoke(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	tokenID, err := resolveTokenIdentifier(cmd, ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	result, err := ctx.Client.RevokeAPIToken(cmd.Context(), tokenID)
+	if err != nil {
+		return err
+	}
+
+	if IsStructured(ctx) {
+		return output.Print(result)
+	}
+	if ctx.Quiet {
+		fmt.Fprintln(os.Stdout, result.ID)
+		return nil
+	}
+
+	return output.PrintKVTable([][2]string{
+		{"id", result.ID},
+		{"name", result.Name},
+		{"status", apiTokenStatus(result)},
+		{"revokedAt", stringValue(result.RevokedAt)},
+	})
+}
+
+// resolveTokenIdentifier accepts a token ID or name. Names are not unique, so
+// a name shared by several active tokens is rejected.
+func resolveTokenIdentifier(cmd *cobra.Command, ctx *Context, ref string) (string, error) {
+	if ctx == nil || ctx.Client == nil {
+		return "", fmt.Errorf("missing context")
+	}
+
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", fmt.Errorf("missing token name or id")
+	}
+
+	tokens := api.Paginate(cmd.Context(), func(reqCtx context.Context, page int) (api.Paginated[api.APIToken], error) {
+		return ctx.Client.ListAPITokens(reqCtx, page, api.MaxPageSize, "desc")
+	})
+	matches := []api.APIToken{}
+	for item, err := range tokens {
+		if err != nil {
+			return "", err
+		}
+		if item.ID == ref {
+			return item.ID, nil
+		}
+		if item.Name == ref && item.RevokedAt == nil {
+			matches = append(matches, item)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("token not found: %s", ref)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return "", fmt.Errorf("token name %q matches %d active tokens; use the token id", ref, len(matches))
+	}
+}
+
+// normalizeScopes trims scopes and drops blanks and duplicates, keeping the
+// order they were given in.
+func normalizeScopes(scopes []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	return result
+}
+
+func tokenScopesLabel(scopes []string) string {
+	if len(scopes) == 0 {
+		return "all"
+	}
+	return strings.Join(scopes, ",")
+}
+
+func apiTokenStatus(token api.APIToken) string {
+	if token.RevokedAt != nil {
+		return "revoked"
+	}
+	return "active"
+}
@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeScopesDropsBlanksAndDuplicates(t *testing.T) {
+	got := normalizeScopes([]string{" runs:read", "", "workflows:write", "runs:read "})
+	want := []string{"runs:read", "workflows:write"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := normalizeScopes(nil); len(got) != 0 {
+		t.Fatalf("expected no scopes, got %v", got)
+	}
+}
@@ -1,13 +1,16 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
 	"github.com/gentij/lunie/apps/cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -17,8 +20,7 @@ var triggerCmd = &cobra.Command{
 	Short: "Manage triggers",
 }
 
-var triggerListPage int
-var triggerListPageSize int
+var triggerListOptions listOptions
 var triggerListSortBy string
 var triggerListSortOrder string
 var triggerCreateType string
@@ -29,6 +31,10 @@ var triggerUpdateName string
 var triggerUpdateIsActive bool
 var triggerUpdateConfig string
 var triggerWebhookPublicBase string
+var triggerFireInput string
+var triggerFireOverrides string
+var triggerFireWait bool
+var triggerFireTimeout time.Duration
 
 func init() {
 	listCmd := &cobra.Command{
@@ -37,8 +43,7 @@ func init() {
 		Args:  cobra.ExactArgs(1),
 		RunE:  triggerList,
 	}
-	listCmd.Flags().IntVar(&triggerListPage, "page", 1, "Page number")
-	listCmd.Flags().IntVar(&triggerListPageSize, "page-size", 25, "Page size")
+	addListFlags(listCmd, &triggerListOptions)
 	listCmd.Flags().StringVar(&triggerListSortBy, "sort-by", "createdAt", "Sort field (createdAt|updatedAt)")
 	listCmd.Flags().StringVar(&triggerListSortOrder, "sort-order", "desc", "Sort order (asc|desc)")
 
@@ -92,12 +97,25 @@ func init() {
 	rotateKeyCmd.Flags().StringVar(&triggerWebhookPublicBase, "public-base", "", "Public API base URL override")
 	webhookCmd.AddCommand(rotateKeyCmd)
 
+	fireCmd := &cobra.Command{
+		Use:   "fire <workflow-key> <trigger-key>",
+		Short: "Start a run through a trigger",
+		Long:  "Start a run through the trigger's authenticated webhook endpoint. Unlike workflow run, the run and its event are attributed to the trigger.",
+		Args:  cobra.ExactArgs(2),
+		RunE:  triggerFire,
+	}
+	fireCmd.Flags().StringVar(&triggerFireInput, "input", "", "Path to input JSON, recorded as the event payload")
+	fireCmd.Flags().StringVar(&triggerFireOverrides, "overrides", "", "Path to HTTP step request overrides JSON")
+	fireCmd.Flags().BoolVar(&triggerFireWait, "wait", false, "Wait for the run to finish and exit non-zero unless it succeeds")
+	fireCmd.Flags().DurationVar(&triggerFireTimeout, "timeout", 0, "Maximum time to wait with --wait (0 waits indefinitely)")
+
 	triggerCmd.AddCommand(listCmd)
 	triggerCmd.AddCommand(getCmd)
 	triggerCmd.AddCommand(createCmd)
 	triggerCmd.AddCommand(updateCmd)
 	triggerCmd.AddCommand(deleteCmd)
 	triggerCmd.AddCommand(webhookCmd)
+	triggerCmd.AddCommand(fireCmd)
 }
 
 func triggerList(cmd *cobra.Command, args []string) error {
@@ -107,30 +125,23 @@ func triggerList(cmd *cobra.Command, args []string) error {
 	}
 
 	workflowKey := args[0]
-	result, err := ctx.Client.ListTriggersByWorkflowKey(workflowKey, triggerListPage, triggerListPageSize, triggerListSortBy, triggerListSortOrder)
-	if err != nil {
-		return err
-	}
-
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
-	}
-
-	if ctx.Quiet {
-		for _, item := range result.Items {
-			fmt.Fprintln(os.Stdout, item.Key)
-		}
-		return nil
-	}
-
-	rows := make([][]string, 0, len(result.Items))
-	for _, item := range result.Items {
-		rows = append(rows, []string{item.Key, item.Type, triggerNameValue(item.Name), output.BoolLabel(item.IsActive)})
-	}
-	if err := output.PrintListTable([]string{"KEY", "TYPE", "NAME", "ACTIVE"}, rows); err != nil {
-		return err
-	}
-	return output.PrintPagination(result.Pagination)
+	return printList(cmd, ctx, triggerListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.Trigger], error) {
+			return ctx.Client.ListTriggersByWorkflowKey(reqCtx, workflowKey, page, pageSize, triggerListSortBy, triggerListSortOrder)
+		},
+		listView[api.Trigger]{
+			Headers: []string{"KEY", "TYPE", "NAME", "ACTIVE"},
+			Row: func(item api.Trigger) []string {
+				return []string{item.Key, item.Type, triggerNameValue(item.Name), output.BoolLabel(item.IsActive)}
+			},
+			WideHeaders: []string{"ID", "CREATED", "UPDATED"},
+			WideRow: func(item api.Trigger) []string {
+				return []string{item.ID, item.CreatedAt, item.UpdatedAt}
+			},
+			Ref:        func(item api.Trigger) string { return item.Key },
+			Pagination: true,
+		},
+	)
 }
 
 func triggerGet(cmd *cobra.Command, args []string) error {
@@ -141,7 +152,7 @@ func triggerGet(cmd *cobra.Command, args []string) error {
 
 	workflowKey := args[0]
 	triggerKey := args[1]
-	result, err := ctx.Client.GetTriggerByKey(workflowKey, triggerKey)
+	result, err := ctx.Client.GetTriggerByKey(cmd.Context(), workflowKey, triggerKey)
 	if err != nil {
 		return err
 	}
@@ -168,7 +179,7 @@ func triggerCreate(cmd *cobra.Command, args []string) error {
 		"config":   configValue,
 	}
 
-	result, err := ctx.Client.CreateTriggerByWorkflowKey(workflowKey, payload)
+	result, err := ctx.Client.CreateTriggerByWorkflowKey(cmd.Context(), workflowKey, payload)
 	if err != nil {
 		return err
 	}
@@ -203,7 +214,7 @@ func triggerUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no fields to update")
 	}
 
-	result, err := ctx.Client.UpdateTriggerByKey(workflowKey, triggerKey, patch)
+	result, err := ctx.Client.UpdateTriggerByKey(cmd.Context(), workflowKey, triggerKey, patch)
 	if err != nil {
 		return err
 	}
@@ -219,7 +230,7 @@ func triggerDelete(cmd *cobra.Command, args []string) error {
 
 	workflowKey := args[0]
 	triggerKey := args[1]
-	result, err := ctx.Client.DeleteTriggerByKey(workflowKey, triggerKey)
+	result, err := ctx.Client.DeleteTriggerByKey(cmd.Context(), workflowKey, triggerKey)
 	if err != nil {
 		return err
 	}
@@ -236,7 +247,7 @@ func triggerWebhookRotateKey(cmd *cobra.Command, args []string) error {
 	workflowKey := args[0]
 	triggerKey := args[1]
 
-	result, err := ctx.Client.RotateTriggerWebhookKeyByKey(workflowKey, triggerKey)
+	result, err := ctx.Client.RotateTriggerWebhookKeyByKey(cmd.Context(), workflowKey, triggerKey)
 	if err != nil {
 		return err
 	}
@@ -252,8 +263,8 @@ func triggerWebhookRotateKey(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if IsJSON(ctx) {
-		return output.PrintJSON(map[string]string{
+	if IsStructured(ctx) {
+		return output.Print(map[string]string{
 			"workflowKey": workflowKey,
 			"triggerKey":  triggerKey,
 			"webhookKey":  result.WebhookKey,
@@ -274,9 +285,71 @@ func triggerWebhookRotateKey(cmd *cobra.Command, args []string) error {
 	})
 }
 
+func triggerFire(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	workflowKey := args[0]
+	triggerKey := args[1]
+
+	input, err := readOptionalJSONFile(triggerFireInput)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := readOptionalJSONFile(triggerFireOverrides)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctx.Client.TriggerWebhookByKey(cmd.Context(), workflowKey, triggerKey, input, overrides)
+	if err != nil {
+		return err
+	}
+	if err := webhookResponseError(workflowKey, triggerKey, result); err != nil {
+		return err
+	}
+
+	if triggerFireWait {
+		if !IsStructured(ctx) && !ctx.Quiet {
+			fmt.Fprintf(os.Stdout, "Fired %s/%s as run #%d, waiting for it to finish...\n", workflowKey, triggerKey, result.WorkflowRunNumber)
+		}
+		return waitForRunOutcome(cmd, ctx, workflowKey, result.WorkflowRunNumber, triggerFireTimeout, 0)
+	}
+
+	if IsStructured(ctx) {
+		return output.Print(result)
+	}
+	if ctx.Quiet {
+		fmt.Fprintln(os.Stdout, result.WorkflowRunNumber)
+		return nil
+	}
+
+	return output.PrintKVTable([][2]string{
+		{"workflowRunNumber", fmt.Sprintf("%d", result.WorkflowRunNumber)},
+		{"workflowRunId", result.WorkflowRunID},
+		{"status", result.Status},
+	})
+}
+
+// webhookResponseError turns a webhook ingress status other than accepted into
+// an error. The server answers an inactive trigger with 200 and no run.
+func webhookResponseError(workflowKey string, triggerKey string, response api.TriggerWebhookResponse) error {
+	switch response.Status {
+	case "accepted":
+		return nil
+	case "trigger_inactive":
+		return lerrors.Conflict(fmt.Errorf("trigger %s/%s is inactive; activate it with `lunie trigger update %s %s --is-active`", workflowKey, triggerKey, workflowKey, triggerKey))
+	default:
+		return fmt.Errorf("unexpected webhook status %q", response.Status)
+	}
+}
+
 func printTrigger(ctx *Context, result api.Trigger) error {
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 	if ctx.Quiet {
 		fmt.Fprintln(os.Stdout, result.Key)
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+)
+
+func TestWebhookResponseError(t *testing.T) {
+	if err := webhookResponseError("deploy", "github", api.TriggerWebhookResponse{Status: "accepted"}); err != nil {
+		t.Fatalf("expected accepted to succeed, got %v", err)
+	}
+
+	err := webhookResponseError("deploy", "github", api.TriggerWebhookResponse{Status: "trigger_inactive"})
+	if !errors.Is(err, lerrors.ErrConflict) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if lerrors.ExitCode(err) != lerrors.ExitConflict {
+		t.Fatalf("expected exit code %d, got %d", lerrors.ExitConflict, lerrors.ExitCode(err))
+	}
+
+	if err := webhookResponseError("deploy", "github", api.TriggerWebhookResponse{Status: "queued"}); err == nil {
+		t.Fatal("expected unknown status to fail")
+	}
+}
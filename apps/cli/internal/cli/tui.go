@@ -16,8 +16,12 @@ var tuiCmd = &cobra.Command{
 		if ctx == nil {
 			return fmt.Errorf("missing context")
 		}
+		token, err := ctx.Client.AuthToken()
+		if err != nil {
+			return err
+		}
 		configPath := config.ResolvePath(configPath)
-		app := tui.NewApp(ctx.Client, ctx.Config.ServerURL, ctx.Config.Token != "", ctx.Config, configPath)
-		return app.Start()
+		app := tui.NewApp(ctx.Client, ctx.Config.ServerURL, token != "", ctx.Config, configPath)
+		return app.Start(cmd.Context())
 	},
 }
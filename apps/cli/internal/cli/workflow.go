@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/gentij/lunie/apps/cli/internal/api"
 	"github.com/gentij/lunie/apps/cli/internal/output"
@@ -14,8 +16,7 @@ var workflowCmd = &cobra.Command{
 	Short: "Manage workflows",
 }
 
-var workflowListPage int
-var workflowListPageSize int
+var workflowListOptions listOptions
 var workflowListSortBy string
 var workflowListSortOrder string
 var workflowCreateName string
@@ -24,6 +25,8 @@ var workflowUpdateName string
 var workflowUpdateIsActive bool
 var workflowRunInput string
 var workflowRunOverrides string
+var workflowRunWait bool
+var workflowRunTimeout time.Duration
 var workflowValidateDefinition string
 
 func init() {
@@ -32,8 +35,7 @@ func init() {
 		Short: "List workflows",
 		RunE:  workflowList,
 	}
-	listCmd.Flags().IntVar(&workflowListPage, "page", 1, "Page number")
-	listCmd.Flags().IntVar(&workflowListPageSize, "page-size", 25, "Page size")
+	addListFlags(listCmd, &workflowListOptions)
 	listCmd.Flags().StringVar(&workflowListSortBy, "sort-by", "createdAt", "Sort field (createdAt|updatedAt)")
 	listCmd.Flags().StringVar(&workflowListSortOrder, "sort-order", "desc", "Sort order (asc|desc)")
 
@@ -80,6 +82,8 @@ func init() {
 	}
 	runCmd.Flags().StringVar(&workflowRunInput, "input", "", "Path to input JSON (overrides workflow definition input defaults)")
 	runCmd.Flags().StringVar(&workflowRunOverrides, "overrides", "", "Path to HTTP step request overrides JSON")
+	runCmd.Flags().BoolVar(&workflowRunWait, "wait", false, "Wait for the run to finish and exit non-zero unless it succeeds")
+	runCmd.Flags().DurationVar(&workflowRunTimeout, "timeout", 0, "Maximum time to wait with --wait (0 waits indefinitely)")
 
 	workflowCmd.AddCommand(createCmd)
 	workflowCmd.AddCommand(updateCmd)
@@ -96,40 +100,64 @@ func init() {
 	validateCmd.Flags().StringVar(&workflowValidateDefinition, "definition", "", "Path to definition JSON")
 	_ = validateCmd.MarkFlagRequired("definition")
 	workflowCmd.AddCommand(validateCmd)
-}
-
-func workflowList(cmd *cobra.Command, args []string) error {
-	ctx := GetContext(cmd.Context())
-	if ctx == nil {
-		return fmt.Errorf("missing context")
-	}
 
-	result, err := ctx.Client.ListWorkflows(workflowListPage, workflowListPageSize, workflowListSortBy, workflowListSortOrder)
-	if err != nil {
-		return err
+	lintCmd := &cobra.Command{
+		Use:   "lint <file>...",
+		Short: "Check workflow definitions offline",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  workflowLint,
 	}
+	lintCmd.Flags().BoolVar(&workflowLintStrict, "strict", false, "Treat warnings as errors")
+	workflowCmd.AddCommand(lintCmd)
 
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	graphCmd := &cobra.Command{
+		Use:   "graph <workflow-key|file>",
+		Short: "Show the step dependency graph and execution batches",
+		Args:  cobra.ExactArgs(1),
+		RunE:  workflowGraph,
 	}
+	graphCmd.Flags().StringVar(&workflowGraphFormat, "format", "ascii", "Graph format (ascii|dot|mermaid)")
+	graphCmd.Flags().IntVar(&workflowGraphVersion, "version", 0, "Workflow version to graph (defaults to latest)")
+	workflowCmd.AddCommand(graphCmd)
 
-	if ctx.Quiet {
-		for _, item := range result.Items {
-			fmt.Fprintln(os.Stdout, item.Key)
-		}
-		return nil
+	execCmd := &cobra.Command{
+		Use:   "exec <file>",
+		Short: "Execute a workflow definition locally",
+		Args:  cobra.ExactArgs(1),
+		RunE:  workflowExec,
 	}
+	execCmd.Flags().BoolVar(&workflowExecLocal, "local", false, "Run steps in this process instead of on the server")
+	execCmd.Flags().StringVar(&workflowExecInput, "input", "", "Path to input JSON (overrides workflow definition input defaults)")
+	execCmd.Flags().StringVar(&workflowExecSecretsFile, "secrets-file", "", "Secrets as JSON/YAML map or NAME=value lines (LUNIE_SECRET_<NAME> env vars take precedence)")
+	workflowCmd.AddCommand(execCmd)
+}
 
-	rows := make([][]string, 0, len(result.Items))
-	for _, item := range result.Items {
-		latest := ""
-		if item.LatestVersionID != nil {
-			latest = *item.LatestVersionID
-		}
-		rows = append(rows, []string{item.Key, item.Name, fmt.Sprintf("%t", item.IsActive), latest})
+func workflowList(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
 	}
 
-	return output.PrintListTable([]string{"KEY", "NAME", "ACTIVE", "LATEST_VERSION"}, rows)
+	return printList(cmd, ctx, workflowListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.Workflow], error) {
+			return ctx.Client.ListWorkflows(reqCtx, page, pageSize, workflowListSortBy, workflowListSortOrder)
+		},
+		listView[api.Workflow]{
+			Headers: []string{"KEY", "NAME", "ACTIVE", "LATEST_VERSION"},
+			Row: func(item api.Workflow) []string {
+				latest := ""
+				if item.LatestVersionID != nil {
+					latest = *item.LatestVersionID
+				}
+				return []string{item.Key, item.Name, fmt.Sprintf("%t", item.IsActive), latest}
+			},
+			WideHeaders: []string{"ID", "CREATED", "UPDATED"},
+			WideRow: func(item api.Workflow) []string {
+				return []string{item.ID, item.CreatedAt, item.UpdatedAt}
+			},
+			Ref: func(item api.Workflow) string { return item.Key },
+		},
+	)
 }
 
 func workflowGet(cmd *cobra.Command, args []string) error {
@@ -138,7 +166,7 @@ func workflowGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("missing context")
 	}
 
-	result, err := ctx.Client.GetWorkflowByKey(args[0])
+	result, err := ctx.Client.GetWorkflowByKey(cmd.Context(), args[0])
 	if err != nil {
 		return err
 	}
@@ -157,7 +185,7 @@ func workflowCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := ctx.Client.CreateWorkflow(workflowCreateName, definition)
+	result, err := ctx.Client.CreateWorkflow(cmd.Context(), workflowCreateName, definition)
 	if err != nil {
 		return err
 	}
@@ -182,7 +210,7 @@ func workflowUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no fields to update")
 	}
 
-	result, err := ctx.Client.UpdateWorkflowByKey(args[0], patch)
+	result, err := ctx.Client.UpdateWorkflowByKey(cmd.Context(), args[0], patch)
 	if err != nil {
 		return err
 	}
@@ -196,7 +224,7 @@ func workflowDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("missing context")
 	}
 
-	result, err := ctx.Client.DeleteWorkflowByKey(args[0])
+	result, err := ctx.Client.DeleteWorkflowByKey(cmd.Context(), args[0])
 	if err != nil {
 		return err
 	}
@@ -220,13 +248,20 @@ func workflowRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := ctx.Client.RunWorkflowByKey(args[0], input, overrides)
+	result, err := ctx.Client.RunWorkflowByKey(cmd.Context(), args[0], input, overrides)
 	if err != nil {
 		return err
 	}
 
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if workflowRunWait {
+		if !IsStructured(ctx) && !ctx.Quiet {
+			fmt.Fprintf(os.Stdout, "Queued run #%d, waiting for it to finish...\n", result.WorkflowRunNumber)
+		}
+		return waitForRunOutcome(cmd, ctx, args[0], result.WorkflowRunNumber, workflowRunTimeout, 0)
+	}
+
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 	if ctx.Quiet {
 		fmt.Fprintln(os.Stdout, result.WorkflowRunNumber)
@@ -247,13 +282,13 @@ func workflowValidate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := ctx.Client.ValidateWorkflowByKey(args[0], definition)
+	result, err := ctx.Client.ValidateWorkflowByKey(cmd.Context(), args[0], definition)
 	if err != nil {
 		return err
 	}
 
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 	if ctx.Quiet {
 		fmt.Fprintln(os.Stdout, result.Valid)
@@ -269,8 +304,8 @@ func workflowValidate(cmd *cobra.Command, args []string) error {
 }
 
 func printWorkflow(ctx *Context, result api.Workflow) error {
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 
 	if ctx.Quiet {
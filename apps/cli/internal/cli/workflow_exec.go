@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/definition"
+	"github.com/gentij/lunie/apps/cli/internal/engine"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const localSecretEnvPrefix = "LUNIE_SECRET_"
+
+var workflowExecLocal bool
+var workflowExecInput string
+var workflowExecSecretsFile string
+
+func workflowExec(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+	if !workflowExecLocal {
+		return fmt.Errorf("only local execution is supported; pass --local")
+	}
+
+	source, err := definition.LoadFile(args[0])
+	if err != nil {
+		return err
+	}
+	if issues := source.Lint(); definition.HasErrors(issues) {
+		for _, issue := range issues {
+			if issue.Severity == definition.SeverityError {
+				fmt.Fprintln(os.Stderr, formatLintIssue(args[0], issue))
+			}
+		}
+		return lerrors.Validation(fmt.Errorf("%s is not a valid workflow definition", args[0]))
+	}
+	def, err := definition.Decode(source.Value)
+	if err != nil {
+		return err
+	}
+
+	input := map[string]any{}
+	if strings.TrimSpace(workflowExecInput) != "" {
+		raw, err := readJSONFile(workflowExecInput)
+		if err != nil {
+			return err
+		}
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("--input must contain a JSON object")
+		}
+		input = object
+	}
+
+	secrets, err := loadLocalSecrets(workflowExecSecretsFile, os.Environ())
+	if err != nil {
+		return err
+	}
+
+	runner := &engine.Engine{Secrets: secrets}
+	if !IsStructured(ctx) {
+		runner.OnStep = func(step engine.StepResult) {
+			printLocalStep(ctx, step)
+		}
+	}
+
+	result, err := runner.Run(cmd.Context(), def, input)
+	if err != nil {
+		return err
+	}
+
+	if IsStructured(ctx) {
+		if err := output.Print(result); err != nil {
+			return err
+		}
+	} else if !ctx.Quiet {
+		fmt.Fprintf(os.Stdout, "Local run %s in %s (%d steps)\n", output.ColorStatus(result.Status), formatMillis(result.DurationMs), len(result.Steps))
+	}
+
+	if result.Status == engine.StatusSucceeded {
+		return nil
+	}
+	return &exitError{
+		Code: exitCodeRunFailed,
+		Err:  fmt.Errorf("local run finished with status %s", result.Status),
+	}
+}
+
+func printLocalStep(ctx *Context, step engine.StepResult) {
+	if ctx.Quiet {
+		fmt.Fprintf(os.Stdout, "%s %s\n", step.Key, step.Status)
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, "[%d] %s (%s) %s %s\n", step.Batch, step.Key, step.Type, output.ColorStatus(step.Status), formatMillis(step.DurationMs))
+	if step.Status != engine.StatusSkipped {
+		printLocalStepSection("input", step.Input)
+		printLocalStepSection("request", step.Request)
+		printLocalStepSection("output", step.Output)
+	}
+	if step.Error != "" {
+		fmt.Fprintf(os.Stdout, "  error: %s\n", step.Error)
+	}
+	fmt.Fprintln(os.Stdout)
+}
+
+func printLocalStepSection(label string, value any) {
+	if value == nil {
+		return
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("    ", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return
+	}
+	fmt.Fprintf(os.Stdout, "  %s:\n    %s", label, buf.String())
+}
+
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+func loadLocalSecrets(path string, environ []string) (map[string]string, error) {
+	secrets := map[string]string{}
+
+	if strings.TrimSpace(path) != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json", ".yaml", ".yml":
+			var values map[string]any
+			if err := yaml.Unmarshal(data, &values); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			for name, value := range values {
+				secrets[name] = fmt.Sprint(value)
+			}
+		default:
+			if err := parseDotenv(data, secrets); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(name, localSecretEnvPrefix) {
+			continue
+		}
+		if name = strings.TrimPrefix(name, localSecretEnvPrefix); name != "" {
+			secrets[name] = value
+		}
+	}
+
+	return secrets, nil
+}
+
+func parseDotenv(data []byte, values map[string]string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("line %d: expected NAME=value", line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return scanner.Err()
+}
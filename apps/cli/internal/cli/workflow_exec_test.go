@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/config"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+)
+
+func TestWorkflowExecExitsWithValidationCodeOnLintErrors(t *testing.T) {
+	useTestConfig(t, config.Config{})
+	path := filepath.Join(t.TempDir(), "def.json")
+	if err := os.WriteFile(path, []byte(`{"steps": [{"key": "fetch", "type": "bogus"}]}`), 0o600); err != nil {
+		t.Fatalf("write definition: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"workflow", "exec", path, "--local"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.ExecuteContext(context.Background())
+	if code := exitCode(err); code != lerrors.ExitValidation {
+		t.Fatalf("expected exit code %d, got %d (%v)", lerrors.ExitValidation, code, err)
+	}
+}
+
+func TestLoadLocalSecretsMergesFileAndEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	content := "# local secrets\nexport API_TOKEN=\"from-file\"\nSLACK_URL=https://hooks.test/x\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	secrets, err := loadLocalSecrets(path, []string{"LUNIE_SECRET_API_TOKEN=from-env", "HOME=/root"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if secrets["API_TOKEN"] != "from-env" || secrets["SLACK_URL"] != "https://hooks.test/x" || len(secrets) != 2 {
+		t.Fatalf("unexpected secrets: %v", secrets)
+	}
+}
+
+func TestLoadLocalSecretsReadsYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	if err := os.WriteFile(path, []byte("API_TOKEN: abc\nRETRIES: 3\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	secrets, err := loadLocalSecrets(path, nil)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if secrets["API_TOKEN"] != "abc" || secrets["RETRIES"] != "3" {
+		t.Fatalf("unexpected secrets: %v", secrets)
+	}
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/definition"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var workflowGraphFormat string
+var workflowGraphVersion int
+
+type graphView struct {
+	Source  string            `json:"source"`
+	Steps   []graphStep       `json:"steps"`
+	Edges   []definition.Edge `json:"edges"`
+	Batches [][]string        `json:"batches"`
+	Cycle   []string          `json:"cycle,omitempty"`
+}
+
+type graphStep struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+func workflowGraph(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	format := strings.ToLower(strings.TrimSpace(workflowGraphFormat))
+	switch format {
+	case "ascii", "dot", "mermaid":
+	default:
+		return fmt.Errorf("invalid --format: %s (ascii|dot|mermaid)", workflowGraphFormat)
+	}
+
+	def, label, err := loadGraphDefinition(cmd, ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	graph, buildErr := definition.BuildGraph(def)
+	var cycle *definition.CycleError
+	if buildErr != nil && !errors.As(buildErr, &cycle) {
+		return buildErr
+	}
+
+	view := graphView{Source: label, Steps: make([]graphStep, 0, len(graph.Steps)), Edges: graph.Edges, Batches: graph.Batches}
+	for _, step := range graph.Steps {
+		view.Steps = append(view.Steps, graphStep{Key: step.Key, Type: step.Type})
+	}
+	if cycle != nil {
+		view.Cycle = cycle.Path
+	}
+
+	switch {
+	case IsStructured(ctx):
+		if err := output.Print(view); err != nil {
+			return err
+		}
+	case format == "dot":
+		renderGraphDOT(os.Stdout, view)
+	case format == "mermaid":
+		renderGraphMermaid(os.Stdout, view)
+	default:
+		renderGraphASCII(os.Stdout, view, ctx.Quiet)
+	}
+	if buildErr != nil {
+		return lerrors.Validation(buildErr)
+	}
+	return nil
+}
+
+func loadGraphDefinition(cmd *cobra.Command, ctx *Context, ref string) (definition.Definition, string, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		source, err := definition.LoadFile(ref)
+		if err != nil {
+			return definition.Definition{}, "", err
+		}
+		def, err := definition.Decode(source.Value)
+		return def, ref, err
+	}
+
+	version := strconv.Itoa(workflowGraphVersion)
+	if workflowGraphVersion <= 0 {
+		versions, err := ctx.Client.ListWorkflowVersionsByKey(cmd.Context(), ref, 1, 1, "version", "desc")
+		if err != nil {
+			return definition.Definition{}, "", err
+		}
+		if len(versions.Items) == 0 {
+			return definition.Definition{}, "", fmt.Errorf("workflow %s has no versions", ref)
+		}
+		version = strconv.Itoa(versions.Items[0].Version)
+	}
+
+	result, err := ctx.Client.GetWorkflowVersionByKey(cmd.Context(), ref, version)
+	if err != nil {
+		return definition.Definition{}, "", err
+	}
+	def, err := definition.Decode(result.Definition)
+	return def, fmt.Sprintf("%s v%d", ref, result.Version), err
+}
+
+func renderGraphASCII(w io.Writer, view graphView, quiet bool) {
+	types := map[string]string{}
+	width := 0
+	for _, step := range view.Steps {
+		types[step.Key] = step.Type
+		if len(step.Key) > width {
+			width = len(step.Key)
+		}
+	}
+	needs := map[string][]string{}
+	inferred := false
+	for _, edge := range view.Edges {
+		label := edge.From
+		if edge.Inferred {
+			label += "*"
+			inferred = true
+		}
+		needs[edge.To] = append(needs[edge.To], label)
+	}
+
+	if len(view.Cycle) > 0 {
+		if !quiet {
+			fmt.Fprintln(w, view.Source)
+		}
+		fmt.Fprintf(w, "cycle: %s\n", strings.Join(view.Cycle, " -> "))
+		return
+	}
+	if quiet {
+		for _, batch := range view.Batches {
+			fmt.Fprintln(w, strings.Join(batch, " "))
+		}
+		return
+	}
+
+	fmt.Fprintln(w, view.Source)
+	for i, batch := range view.Batches {
+		if i > 0 {
+			fmt.Fprintln(w, "   |")
+			fmt.Fprintln(w, "   v")
+		}
+		fmt.Fprintf(w, "Batch %d\n", i+1)
+		for _, key := range batch {
+			line := fmt.Sprintf("  %-*s  %-9s", width, key, types[key])
+			if deps := needs[key]; len(deps) > 0 {
+				line += "  <- " + strings.Join(deps, ", ")
+			}
+			fmt.Fprintln(w, strings.TrimRight(line, " "))
+		}
+	}
+	if inferred {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "* inferred from {{steps.*}} references")
+	}
+}
+
+func renderGraphDOT(w io.Writer, view graphView) {
+	fmt.Fprintln(w, "digraph workflow {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+	if len(view.Batches) > 0 {
+		for i, batch := range view.Batches {
+			fmt.Fprintf(w, "  subgraph cluster_batch_%d {\n", i+1)
+			fmt.Fprintf(w, "    label=%s;\n", strconv.Quote(fmt.Sprintf("Batch %d", i+1)))
+			fmt.Fprintln(w, "    style=dashed;")
+			for _, key := range batch {
+				fmt.Fprintf(w, "    %s [label=%s];\n", strconv.Quote(key), strconv.Quote(graphNodeLabel(view, key)))
+			}
+			fmt.Fprintln(w, "  }")
+		}
+	} else {
+		for _, step := range view.Steps {
+			fmt.Fprintf(w, "  %s [label=%s];\n", strconv.Quote(step.Key), strconv.Quote(graphNodeLabel(view, step.Key)))
+		}
+	}
+	for _, edge := range view.Edges {
+		style := ""
+		if edge.Inferred {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(w, "  %s -> %s%s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To), style)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func renderGraphMermaid(w io.Writer, view graphView) {
+	ids := map[string]string{}
+	for i, step := range view.Steps {
+		if _, ok := ids[step.Key]; !ok {
+			ids[step.Key] = fmt.Sprintf("s%d", i+1)
+		}
+	}
+	node := func(key string) string {
+		return fmt.Sprintf("%s[%q]", ids[key], graphNodeLabel(view, key))
+	}
+
+	fmt.Fprintln(w, "flowchart LR")
+	if len(view.Batches) > 0 {
+		for i, batch := range view.Batches {
+			fmt.Fprintf(w, "  subgraph batch%d [\"Batch %d\"]\n", i+1, i+1)
+			for _, key := range batch {
+				fmt.Fprintf(w, "    %s\n", node(key))
+			}
+			fmt.Fprintln(w, "  end")
+		}
+	} else {
+		for _, step := range view.Steps {
+			fmt.Fprintf(w, "  %s\n", node(step.Key))
+		}
+	}
+	for _, edge := range view.Edges {
+		arrow := "-->"
+		if edge.Inferred {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(w, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+}
+
+func graphNodeLabel(view graphView, key string) string {
+	for _, step := range view.Steps {
+		if step.Key == key && step.Type != "" {
+			return key + " (" + step.Type + ")"
+		}
+	}
+	return key
+}
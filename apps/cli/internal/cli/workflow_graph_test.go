@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/definition"
+)
+
+func testGraphView() graphView {
+	return graphView{
+		Source: "sync v2",
+		Steps:  []graphStep{{Key: "users", Type: "http"}, {Key: "orders", Type: "http"}, {Key: "join", Type: "transform"}},
+		Edges: []definition.Edge{
+			{From: "users", To: "join"},
+			{From: "orders", To: "join", Inferred: true},
+		},
+		Batches: [][]string{{"users", "orders"}, {"join"}},
+	}
+}
+
+func TestRenderGraphASCIIShowsBatchesAndInferredEdges(t *testing.T) {
+	var buf bytes.Buffer
+	renderGraphASCII(&buf, testGraphView(), false)
+
+	out := buf.String()
+	for _, want := range []string{"Batch 1", "Batch 2", "join    transform  <- users, orders*", "* inferred"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderGraphASCIIPrintsCycle(t *testing.T) {
+	view := graphView{Source: "loop.json", Cycle: []string{"a", "b", "a"}}
+
+	var buf bytes.Buffer
+	renderGraphASCII(&buf, view, false)
+	if got, want := buf.String(), "loop.json\ncycle: a -> b -> a\n"; got != want {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
+
+func TestRenderGraphMermaidUsesDottedInferredEdges(t *testing.T) {
+	var buf bytes.Buffer
+	renderGraphMermaid(&buf, testGraphView())
+
+	out := buf.String()
+	for _, want := range []string{"flowchart LR", `s3["join (transform)"]`, "s1 --> s3", "s2 -.-> s3"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderGraphDOTQuotesKeys(t *testing.T) {
+	var buf bytes.Buffer
+	renderGraphDOT(&buf, testGraphView())
+
+	out := buf.String()
+	for _, want := range []string{"subgraph cluster_batch_1", `"orders" -> "join" [style=dashed];`, `"users" -> "join";`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gentij/lunie/apps/cli/internal/definition"
+	lerrors "github.com/gentij/lunie/apps/cli/internal/errors"
+	"github.com/gentij/lunie/apps/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var workflowLintStrict bool
+
+type lintResult struct {
+	File   string             `json:"file"`
+	Valid  bool               `json:"valid"`
+	Issues []definition.Issue `json:"issues"`
+}
+
+func workflowLint(cmd *cobra.Command, args []string) error {
+	ctx := GetContext(cmd.Context())
+	if ctx == nil {
+		return fmt.Errorf("missing context")
+	}
+
+	results := make([]lintResult, 0, len(args))
+	failed := 0
+	for _, path := range args {
+		source, err := definition.LoadFile(path)
+		if err != nil {
+			return err
+		}
+		issues := source.Lint()
+		if issues == nil {
+			issues = []definition.Issue{}
+		}
+		result := lintResult{File: path, Valid: lintPassed(issues), Issues: issues}
+		if !result.Valid {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if err := printLintResults(ctx, results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return lerrors.Validation(fmt.Errorf("%d of %d file(s) failed lint", failed, len(results)))
+	}
+	return nil
+}
+
+func lintPassed(issues []definition.Issue) bool {
+	if workflowLintStrict {
+		return len(issues) == 0
+	}
+	return !definition.HasErrors(issues)
+}
+
+func printLintResults(ctx *Context, results []lintResult) error {
+	if IsStructured(ctx) {
+		if len(results) == 1 {
+			return output.Print(results[0])
+		}
+		return output.Print(results)
+	}
+
+	for _, result := range results {
+		if ctx.Quiet {
+			fmt.Fprintf(os.Stdout, "%s %t\n", result.File, result.Valid)
+			continue
+		}
+		for _, issue := range result.Issues {
+			fmt.Fprintln(os.Stdout, formatLintIssue(result.File, issue))
+		}
+		if len(result.Issues) == 0 {
+			fmt.Fprintf(os.Stdout, "%s: ok\n", result.File)
+		}
+	}
+	return nil
+}
+
+func formatLintIssue(file string, issue definition.Issue) string {
+	location := file
+	if issue.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", file, issue.Line, issue.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, issue.Severity, issue.Error())
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -14,8 +15,7 @@ var workflowVersionCmd = &cobra.Command{
 	Short: "Manage workflow versions",
 }
 
-var workflowVersionListPage int
-var workflowVersionListPageSize int
+var workflowVersionListOptions listOptions
 var workflowVersionListSortBy string
 var workflowVersionListSortOrder string
 var workflowVersionCreateDefinition string
@@ -27,8 +27,7 @@ func init() {
 		Args:  cobra.ExactArgs(1),
 		RunE:  workflowVersionList,
 	}
-	listCmd.Flags().IntVar(&workflowVersionListPage, "page", 1, "Page number")
-	listCmd.Flags().IntVar(&workflowVersionListPageSize, "page-size", 25, "Page size")
+	addListFlags(listCmd, &workflowVersionListOptions)
 	listCmd.Flags().StringVar(&workflowVersionListSortBy, "sort-by", "version", "Sort field (version|createdAt)")
 	listCmd.Flags().StringVar(&workflowVersionListSortOrder, "sort-order", "desc", "Sort order (asc|desc)")
 
@@ -60,29 +59,23 @@ func workflowVersionList(cmd *cobra.Command, args []string) error {
 	}
 
 	workflowKey := args[0]
-	result, err := ctx.Client.ListWorkflowVersionsByKey(workflowKey, workflowVersionListPage, workflowVersionListPageSize, workflowVersionListSortBy, workflowVersionListSortOrder)
-	if err != nil {
-		return err
-	}
-
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
-	}
-	if ctx.Quiet {
-		for _, item := range result.Items {
-			fmt.Fprintln(os.Stdout, item.Version)
-		}
-		return nil
-	}
-
-	rows := make([][]string, 0, len(result.Items))
-	for _, item := range result.Items {
-		rows = append(rows, []string{fmt.Sprintf("%d", item.Version), item.ID, item.CreatedAt})
-	}
-	if err := output.PrintListTable([]string{"VERSION", "ID", "CREATED"}, rows); err != nil {
-		return err
-	}
-	return output.PrintPagination(result.Pagination)
+	return printList(cmd, ctx, workflowVersionListOptions,
+		func(reqCtx context.Context, page int, pageSize int) (api.Paginated[api.WorkflowVersion], error) {
+			return ctx.Client.ListWorkflowVersionsByKey(reqCtx, workflowKey, page, pageSize, workflowVersionListSortBy, workflowVersionListSortOrder)
+		},
+		listView[api.WorkflowVersion]{
+			Headers: []string{"VERSION", "ID", "CREATED"},
+			Row: func(item api.WorkflowVersion) []string {
+				return []string{fmt.Sprintf("%d", item.Version), item.ID, item.CreatedAt}
+			},
+			WideHeaders: []string{"WORKFLOW_ID"},
+			WideRow: func(item api.WorkflowVersion) []string {
+				return []string{item.WorkflowID}
+			},
+			Ref:        func(item api.WorkflowVersion) string { return fmt.Sprintf("%d", item.Version) },
+			Pagination: true,
+		},
+	)
 }
 
 func workflowVersionGet(cmd *cobra.Command, args []string) error {
@@ -93,7 +86,7 @@ func workflowVersionGet(cmd *cobra.Command, args []string) error {
 
 	workflowKey := args[0]
 	version := args[1]
-	result, err := ctx.Client.GetWorkflowVersionByKey(workflowKey, version)
+	result, err := ctx.Client.GetWorkflowVersionByKey(cmd.Context(), workflowKey, version)
 	if err != nil {
 		return err
 	}
@@ -113,7 +106,7 @@ func workflowVersionCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := ctx.Client.CreateWorkflowVersionByKey(workflowKey, definition)
+	result, err := ctx.Client.CreateWorkflowVersionByKey(cmd.Context(), workflowKey, definition)
 	if err != nil {
 		return err
 	}
@@ -122,8 +115,8 @@ func workflowVersionCreate(cmd *cobra.Command, args []string) error {
 }
 
 func printWorkflowVersion(ctx *Context, result api.WorkflowVersion) error {
-	if IsJSON(ctx) {
-		return output.PrintJSON(result)
+	if IsStructured(ctx) {
+		return output.Print(result)
 	}
 	if ctx.Quiet {
 		fmt.Fprintln(os.Stdout, result.Version)
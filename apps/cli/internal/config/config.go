@@ -3,15 +3,40 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 )
 
 type Config struct {
-	ServerURL string `json:"serverUrl"`
-	Token     string `json:"token"`
-	Profile   string `json:"profile,omitempty"`
-	Theme     string `json:"theme,omitempty"`
+	ServerURL      string                   `json:"serverUrl"`
+	Token          string                   `json:"token,omitempty"`
+	TokenStore     string                   `json:"tokenStore,omitempty"`
+	Output         string                   `json:"output,omitempty"`
+	Theme          string                   `json:"theme,omitempty"`
+	Retry          *RetryConfig             `json:"retry,omitempty"`
+	Credentials    *CredentialsConfig       `json:"credentials,omitempty"`
+	CurrentContext string                   `json:"currentContext,omitempty"`
+	Contexts       map[string]ContextConfig `json:"contexts,omitempty"`
+}
+
+// ContextConfig is a named server profile. Empty fields fall back to the
+// top-level values of Config.
+type ContextConfig struct {
+	ServerURL  string `json:"serverUrl"`
+	Token      string `json:"token,omitempty"`
+	TokenStore string `json:"tokenStore,omitempty"`
+	Output     string `json:"output,omitempty"`
+	Theme      string `json:"theme,omitempty"`
+}
+
+type RetryConfig struct {
+	MaxAttempts int    `json:"maxAttempts,omitempty"`
+	BaseDelay   string `json:"baseDelay,omitempty"`
+	MaxDelay    string `json:"maxDelay,omitempty"`
 }
 
 func DefaultConfigPath() string {
@@ -48,6 +73,18 @@ func Load(path string) (Config, error) {
 	return cfg, nil
 }
 
+// Update loads the config file at path, applies fn and saves the result.
+func Update(path string, fn func(*Config) error) error {
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(&cfg); err != nil {
+		return err
+	}
+	return Save(path, cfg)
+}
+
 func Save(path string, cfg Config) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
@@ -60,3 +97,171 @@ func Save(path string, cfg Config) error {
 
 	return os.WriteFile(path, data, 0o600)
 }
+
+var contextNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func ValidateContextName(name string) error {
+	if !contextNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid context name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+func (c Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WithContext returns the effective config for the named context: its values
+// override the top-level ones and CurrentContext is set to name. An empty name
+// selects the top-level values only. The top-level token is only inherited by
+// contexts that point at the top-level server, so it is never sent elsewhere.
+func (c Config) WithContext(name string) (Config, error) {
+	effective := c
+	effective.CurrentContext = name
+	if name == "" {
+		return effective, nil
+	}
+
+	context, ok := c.Contexts[name]
+	if !ok {
+		return c, fmt.Errorf("context %q not found", name)
+	}
+	if context.ServerURL != "" {
+		effective.ServerURL = context.ServerURL
+	}
+	if context.Token != "" || context.TokenStore != "" || !c.inheritsToken(context) {
+		effective.Token = context.Token
+		effective.TokenStore = context.TokenStore
+	}
+	if context.Output != "" {
+		effective.Output = context.Output
+	}
+	if context.Theme != "" {
+		effective.Theme = context.Theme
+	}
+	return effective, nil
+}
+
+func (c Config) inheritsToken(context ContextConfig) bool {
+	server := strings.TrimRight(strings.TrimSpace(context.ServerURL), "/")
+	return server == "" || server == strings.TrimRight(strings.TrimSpace(c.ServerURL), "/")
+}
+
+// SetCredentials records the server URL and the store holding the token on
+// the named context, or on the top-level config when name is empty. The token
+// itself is only written to the config file for the plaintext store; pass an
+// empty store to clear the credentials.
+func (c *Config) SetCredentials(name string, serverURL string, token string, store string) {
+	if store != StorePlaintext {
+		token = ""
+	}
+	if name == "" {
+		c.ServerURL = serverURL
+		c.Token = token
+		c.TokenStore = store
+		return
+	}
+	context := c.Contexts[name]
+	context.ServerURL = serverURL
+	context.Token = token
+	context.TokenStore = store
+	c.setContext(name, context)
+}
+
+// TokenStoreFor returns the store holding the token of the named context, or
+// of the top-level config when name is empty or the context has no token and
+// shares the top-level server. Tokens written before stores existed are
+// reported as plaintext.
+func (c Config) TokenStoreFor(name string) (store string, contextName string) {
+	if context, ok := c.Contexts[name]; ok && name != "" {
+		if context.TokenStore != "" {
+			return context.TokenStore, name
+		}
+		if context.Token != "" {
+			return StorePlaintext, name
+		}
+		if !c.inheritsToken(context) {
+			return "", name
+		}
+	}
+	if c.TokenStore != "" {
+		return c.TokenStore, ""
+	}
+	if c.Token != "" {
+		return StorePlaintext, ""
+	}
+	return "", ""
+}
+
+// SetTheme stores the theme on the named context, or on the top-level config
+// when name is empty.
+func (c *Config) SetTheme(name string, theme string) {
+	if _, ok := c.Contexts[name]; name == "" || !ok {
+		c.Theme = theme
+		return
+	}
+	context := c.Contexts[name]
+	context.Theme = theme
+	c.setContext(name, context)
+}
+
+func (c *Config) setContext(name string, context ContextConfig) {
+	if c.Contexts == nil {
+		c.Contexts = map[string]ContextConfig{}
+	}
+	c.Contexts[name] = context
+}
+
+func (c *Config) AddContext(name string, context ContextConfig) error {
+	if err := ValidateContextName(name); err != nil {
+		return err
+	}
+	if _, ok := c.Contexts[name]; ok {
+		return fmt.Errorf("context %q already exists", name)
+	}
+	c.setContext(name, context)
+	return nil
+}
+
+func (c *Config) UseContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found", name)
+	}
+	c.CurrentContext = name
+	return nil
+}
+
+// RemoveContext deletes a context and clears CurrentContext if it pointed at it.
+func (c *Config) RemoveContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found", name)
+	}
+	delete(c.Contexts, name)
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+	return nil
+}
+
+func (c *Config) RenameContext(oldName string, newName string) error {
+	context, ok := c.Contexts[oldName]
+	if !ok {
+		return fmt.Errorf("context %q not found", oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	if err := c.AddContext(newName, context); err != nil {
+		return err
+	}
+	delete(c.Contexts, oldName)
+	if c.CurrentContext == oldName {
+		c.CurrentContext = newName
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWithContextOverlaysTopLevelValues(t *testing.T) {
+	cfg := Config{
+		ServerURL: "http://localhost:3000/v1/api",
+		Token:     "local-token",
+		Theme:     "lunie",
+		Contexts: map[string]ContextConfig{
+			"staging": {ServerURL: "http://localhost:3000/v1/api/", Output: "json"},
+		},
+	}
+
+	effective, err := cfg.WithContext("staging")
+	if err != nil {
+		t.Fatalf("with context: %v", err)
+	}
+	if effective.ServerURL != "http://localhost:3000/v1/api/" || effective.Output != "json" || effective.CurrentContext != "staging" {
+		t.Fatalf("expected staging values, got %+v", effective)
+	}
+	if effective.Token != "local-token" || effective.Theme != "lunie" {
+		t.Fatalf("expected empty context fields to fall back, got %+v", effective)
+	}
+	if _, err := cfg.WithContext("prod"); err == nil {
+		t.Fatal("expected unknown context to fail")
+	}
+}
+
+func TestWithContextKeepsTopLevelTokenOffOtherServers(t *testing.T) {
+	cfg := Config{
+		ServerURL: "http://localhost:3000/v1/api",
+		Token:     "local-secret-token",
+		Contexts: map[string]ContextConfig{
+			"prod": {ServerURL: "https://lunie.example/v1/api"},
+		},
+	}
+
+	effective, err := cfg.WithContext("prod")
+	if err != nil {
+		t.Fatalf("with context: %v", err)
+	}
+	if effective.Token != "" || effective.TokenStore != "" {
+		t.Fatalf("expected no token for another server, got %+v", effective)
+	}
+	if store, owner := cfg.TokenStoreFor("prod"); store != "" || owner != "prod" {
+		t.Fatalf("expected no token store for prod, got %q owned by %q", store, owner)
+	}
+}
+
+func TestContextLifecycleKeepsCurrentContextInSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := Update(path, func(cfg *Config) error {
+		if err := cfg.AddContext("staging", ContextConfig{ServerURL: "https://staging.example/v1/api"}); err != nil {
+			return err
+		}
+		if err := cfg.UseContext("staging"); err != nil {
+			return err
+		}
+		return cfg.RenameContext("staging", "stage")
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.CurrentContext != "stage" || len(cfg.Contexts) != 1 {
+		t.Fatalf("expected renamed current context, got %+v", cfg)
+	}
+	if err := cfg.AddContext("stage", ContextConfig{}); err == nil {
+		t.Fatal("expected duplicate context to fail")
+	}
+	if err := cfg.AddContext("bad name", ContextConfig{}); err == nil {
+		t.Fatal("expected invalid name to fail")
+	}
+
+	if err := cfg.RemoveContext("stage"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if cfg.CurrentContext != "" {
+		t.Fatalf("expected removing the current context to clear it, got %q", cfg.CurrentContext)
+	}
+}
+
+func TestSetCredentialsTargetsNamedContext(t *testing.T) {
+	cfg := Config{ServerURL: "http://localhost:3000/v1/api", Token: "top"}
+	cfg.SetCredentials("prod", "https://prod.example/v1/api", "prod-token", StorePlaintext)
+
+	if cfg.Token != "top" || cfg.Contexts["prod"].Token != "prod-token" {
+		t.Fatalf("expected only the prod context to change, got %+v", cfg)
+	}
+}
+
+func TestSetCredentialsKeepsTokenOutOfConfigForStores(t *testing.T) {
+	cfg := Config{ServerURL: "http://localhost:3000/v1/api", Token: "legacy"}
+	if store, owner := cfg.TokenStoreFor("prod"); store != StorePlaintext || owner != "" {
+		t.Fatalf("expected legacy top-level token to be plaintext, got %q %q", store, owner)
+	}
+
+	cfg.SetCredentials("", cfg.ServerURL, "secret", StoreSecretService)
+	if cfg.Token != "" || cfg.TokenStore != StoreSecretService {
+		t.Fatalf("expected token to move to the store, got %+v", cfg)
+	}
+
+	cfg.SetCredentials("prod", "https://prod.example/v1/api", "secret", StoreFile)
+	effective, err := cfg.WithContext("prod")
+	if err != nil {
+		t.Fatalf("with context: %v", err)
+	}
+	if effective.TokenStore != StoreFile {
+		t.Fatalf("expected context store to override, got %+v", effective)
+	}
+	if store, owner := cfg.TokenStoreFor("prod"); store != StoreFile || owner != "prod" {
+		t.Fatalf("expected prod to own its token, got %q %q", store, owner)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Credential store backends. The config file records which one holds each
+// token; plaintext keeps the token in the config file itself and is opt-in.
+const (
+	StoreSecretService = "secret-service"
+	StoreFile          = "file"
+	StoreHelper        = "helper"
+	StorePlaintext     = "plaintext"
+)
+
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialKey identifies a token. Context is empty for the top-level
+// credentials.
+type CredentialKey struct {
+	Context string
+	Server  string
+}
+
+// Account is the name the token is stored under. Context names cannot contain
+// parentheses, so the top-level account never collides with a context.
+func (k CredentialKey) Account() string {
+	if k.Context == "" {
+		return "(default)"
+	}
+	return k.Context
+}
+
+// CredentialStore keeps API tokens outside the config file.
+type CredentialStore interface {
+	Name() string
+	Get(key CredentialKey) (string, error)
+	Store(key CredentialKey, token string) error
+	Erase(key CredentialKey) error
+}
+
+type CredentialOptions struct {
+	// Dir holds the encrypted credentials file, normally the config directory.
+	Dir string
+	// Helper is the credential helper command for the helper backend.
+	Helper string
+	// Passphrase returns the passphrase for the encrypted file backend. It is
+	// only called when the file is read or written.
+	Passphrase func() (string, error)
+}
+
+// CredentialsConfig selects the backend used by auth login and the helper
+// command for the helper backend.
+type CredentialsConfig struct {
+	Store  string `json:"store,omitempty"`
+	Helper string `json:"helper,omitempty"`
+}
+
+func CredentialStoreNames() []string {
+	return []string{StoreSecretService, StoreFile, StoreHelper, StorePlaintext}
+}
+
+// OpenCredentialStore returns the named backend. Plaintext tokens live in the
+// config file and have no store.
+func OpenCredentialStore(name string, opts CredentialOptions) (CredentialStore, error) {
+	switch name {
+	case StoreSecretService:
+		return newSecretServiceStore(), nil
+	case StoreFile:
+		if opts.Passphrase == nil {
+			return nil, fmt.Errorf("encrypted credentials file needs a passphrase")
+		}
+		return newFileStore(opts.Dir, opts.Passphrase), nil
+	case StoreHelper:
+		if strings.TrimSpace(opts.Helper) == "" {
+			return nil, fmt.Errorf("credential helper not configured: set credentials.helper in the config file")
+		}
+		return newHelperStore(opts.Helper), nil
+	case StorePlaintext:
+		return nil, fmt.Errorf("plaintext tokens are kept in the config file")
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (expected %s)", name, strings.Join(CredentialStoreNames(), "|"))
+	}
+}
+
+// DefaultCredentialStore returns the backend to use when none is configured,
+// or an empty string when no secure backend is available without setup.
+func DefaultCredentialStore() string {
+	if secretServiceAvailable() {
+		return StoreSecretService
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	credentialsFileName    = "credentials.enc"
+	credentialsFileVersion = 1
+	defaultKDFIterations   = 600_000
+)
+
+// encryptedCredentials is the on-disk format of the encrypted file backend:
+// a JSON map of account to token, sealed with AES-256-GCM under a key derived
+// from the passphrase with PBKDF2-SHA256.
+type encryptedCredentials struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type fileStore struct {
+	path       string
+	passphrase func() (string, error)
+	iterations int
+	cached     *string
+}
+
+func newFileStore(dir string, passphrase func() (string, error)) *fileStore {
+	return &fileStore{
+		path:       filepath.Join(dir, credentialsFileName),
+		passphrase: passphrase,
+		iterations: defaultKDFIterations,
+	}
+}
+
+func (s *fileStore) Name() string {
+	return StoreFile
+}
+
+func (s *fileStore) Get(key CredentialKey) (string, error) {
+	tokens, _, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	token, ok := tokens[key.Account()]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return token, nil
+}
+
+func (s *fileStore) Store(key CredentialKey, token string) error {
+	tokens, sealed, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[key.Account()] = token
+	return s.write(tokens, sealed)
+}
+
+func (s *fileStore) Erase(key CredentialKey) error {
+	tokens, sealed, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key.Account()]; !ok {
+		return ErrCredentialNotFound
+	}
+	delete(tokens, key.Account())
+	return s.write(tokens, sealed)
+}
+
+func (s *fileStore) getPassphrase() (string, error) {
+	if s.cached != nil {
+		return *s.cached, nil
+	}
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("credentials passphrase must not be empty")
+	}
+	s.cached = &passphrase
+	return passphrase, nil
+}
+
+// read returns the decrypted tokens and the header of the existing file, or
+// an empty map and nil when there is no file yet.
+func (s *fileStore) read() (map[string]string, *encryptedCredentials, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	var sealed encryptedCredentials
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", s.path, err)
+	}
+	if sealed.Version != credentialsFileVersion {
+		return nil, nil, fmt.Errorf("unsupported credentials file version %d", sealed.Version)
+	}
+
+	aead, err := s.cipher(sealed.Salt, sealed.Iterations)
+	if err != nil {
+		return nil, nil, err
+	}
+	plain, err := aead.Open(nil, sealed.Nonce, sealed.Ciphertext, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decrypt %s: wrong passphrase or corrupted file", s.path)
+	}
+
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", s.path, err)
+	}
+	return tokens, &sealed, nil
+}
+
+func (s *fileStore) write(tokens map[string]string, previous *encryptedCredentials) error {
+	sealed := encryptedCredentials{Version: credentialsFileVersion, KDF: "pbkdf2-sha256", Iterations: s.iterations}
+	if previous != nil {
+		sealed.Salt = previous.Salt
+		sealed.Iterations = previous.Iterations
+	} else {
+		sealed.Salt = make([]byte, 16)
+		if _, err := rand.Read(sealed.Salt); err != nil {
+			return err
+		}
+	}
+
+	aead, err := s.cipher(sealed.Salt, sealed.Iterations)
+	if err != nil {
+		return err
+	}
+	sealed.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(sealed.Nonce); err != nil {
+		return err
+	}
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	sealed.Ciphertext = aead.Seal(nil, sealed.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(sealed, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *fileStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	passphrase, err := s.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const helperPrefix = "lunie-credential-"
+
+// helperStore runs an external credential helper, similar to git's. The
+// helper is called with get, store or erase as its last argument and reads
+// key=value lines from stdin, terminated by a blank line:
+//
+//	context=<account>
+//	server=<server URL>
+//	token=<token>        (store only)
+//
+// For get, it prints token=<token>, or nothing when it has no token.
+type helperStore struct {
+	command []string
+	run     commandRunner
+}
+
+// newHelperStore accepts a helper name, which runs lunie-credential-<name>
+// from PATH, or a path to an executable, optionally followed by arguments.
+func newHelperStore(helper string) *helperStore {
+	command := strings.Fields(helper)
+	if filepath.Base(command[0]) == command[0] {
+		command[0] = helperPrefix + command[0]
+	}
+	return &helperStore{command: command, run: runCommand}
+}
+
+func (s *helperStore) Name() string {
+	return StoreHelper
+}
+
+func (s *helperStore) call(operation string, key CredentialKey, token string) (string, error) {
+	var input strings.Builder
+	fmt.Fprintf(&input, "context=%s\n", key.Account())
+	fmt.Fprintf(&input, "server=%s\n", key.Server)
+	if token != "" {
+		fmt.Fprintf(&input, "token=%s\n", token)
+	}
+	input.WriteString("\n")
+
+	args := append(append([]string{}, s.command[1:]...), operation)
+	out, err := s.run(input.String(), s.command[0], args...)
+	if err != nil {
+		return "", fmt.Errorf("credential helper %s: %w", operation, err)
+	}
+	return out, nil
+}
+
+func (s *helperStore) Get(key CredentialKey) (string, error) {
+	out, err := s.call("get", key, "")
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok && name == "token" && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", ErrCredentialNotFound
+}
+
+func (s *helperStore) Store(key CredentialKey, token string) error {
+	if strings.ContainsAny(token, "\r\n") {
+		return fmt.Errorf("token must be a single line")
+	}
+	_, err := s.call("store", key, token)
+	return err
+}
+
+func (s *helperStore) Erase(key CredentialKey) error {
+	_, err := s.call("erase", key, "")
+	return err
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const secretServiceName = "lunie"
+
+// commandRunner runs an external program with stdin and returns its stdout.
+type commandRunner func(stdin string, name string, args ...string) (string, error)
+
+// commandError is a program that exited unsuccessfully.
+type commandError struct {
+	name     string
+	exitCode int
+	stderr   string
+}
+
+func (e *commandError) Error() string {
+	if e.stderr != "" {
+		return fmt.Sprintf("%s exited with code %d: %s", e.name, e.exitCode, e.stderr)
+	}
+	return fmt.Sprintf("%s exited with code %d", e.name, e.exitCode)
+}
+
+func runCommand(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return stdout.String(), &commandError{name: name, exitCode: exitErr.ExitCode(), stderr: strings.TrimSpace(stderr.String())}
+		}
+		return stdout.String(), err
+	}
+	return stdout.String(), nil
+}
+
+// secretServiceStore talks to the Secret Service D-Bus API (GNOME Keyring,
+// KWallet) through libsecret's secret-tool.
+type secretServiceStore struct {
+	run commandRunner
+}
+
+func newSecretServiceStore() *secretServiceStore {
+	return &secretServiceStore{run: runCommand}
+}
+
+func secretServiceAvailable() bool {
+	if runtime.GOOS != "linux" && runtime.GOOS != "freebsd" {
+		return false
+	}
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func (s *secretServiceStore) Name() string {
+	return StoreSecretService
+}
+
+// attributes identify an entry by context and server, like the helper
+// protocol, so a context pointed at another server does not reuse the token
+// saved for the old one.
+func (s *secretServiceStore) attributes(key CredentialKey) []string {
+	attributes := []string{"service", secretServiceName, "account", key.Account()}
+	if server := strings.TrimSpace(key.Server); server != "" {
+		attributes = append(attributes, "server", server)
+	}
+	return attributes
+}
+
+func (s *secretServiceStore) Get(key CredentialKey) (string, error) {
+	out, err := s.run("", "secret-tool", append([]string{"lookup"}, s.attributes(key)...)...)
+	if err != nil {
+		// secret-tool exits 1 without output when nothing matches.
+		var cmdErr *commandError
+		if errors.As(err, &cmdErr) && cmdErr.exitCode == 1 && cmdErr.stderr == "" {
+			return "", ErrCredentialNotFound
+		}
+		return "", fmt.Errorf("secret service lookup: %w", err)
+	}
+	token := strings.TrimSpace(out)
+	if token == "" {
+		return "", ErrCredentialNotFound
+	}
+	return token, nil
+}
+
+func (s *secretServiceStore) Store(key CredentialKey, token string) error {
+	label := fmt.Sprintf("--label=Lunie API token (%s)", key.Account())
+	args := append([]string{"store", label}, s.attributes(key)...)
+	if _, err := s.run(token, "secret-tool", args...); err != nil {
+		return fmt.Errorf("secret service store: %w", err)
+	}
+	return nil
+}
+
+func (s *secretServiceStore) Erase(key CredentialKey) error {
+	if _, err := s.run("", "secret-tool", append([]string{"clear"}, s.attributes(key)...)...); err != nil {
+		return fmt.Errorf("secret service clear: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testFileStore(dir string, passphrase string) *fileStore {
+	store := newFileStore(dir, func() (string, error) { return passphrase, nil })
+	store.iterations = 1000
+	return store
+}
+
+func TestFileStoreEncryptsTokens(t *testing.T) {
+	dir := t.TempDir()
+	store := testFileStore(dir, "correct horse")
+	key := CredentialKey{Context: "prod", Server: "https://prod.example/v1/api"}
+
+	if _, err := store.Get(key); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected not found before store, got %v", err)
+	}
+	if err := store.Store(key, "prod-token"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	if err := store.Store(CredentialKey{}, "top-token"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFileName))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(data), "prod-token") {
+		t.Fatal("expected token to be encrypted on disk")
+	}
+
+	reopened := testFileStore(dir, "correct horse")
+	if token, err := reopened.Get(key); err != nil || token != "prod-token" {
+		t.Fatalf("expected prod token, got %q (%v)", token, err)
+	}
+	if err := reopened.Erase(key); err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+	if token, err := reopened.Get(CredentialKey{}); err != nil || token != "top-token" {
+		t.Fatalf("expected top-level token to survive erase, got %q (%v)", token, err)
+	}
+
+	if _, err := testFileStore(dir, "wrong").Get(key); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Fatalf("expected wrong passphrase error, got %v", err)
+	}
+}
+
+func TestHelperStoreSpeaksKeyValueProtocol(t *testing.T) {
+	var calls []string
+	var inputs []string
+	store := newHelperStore("pass --quiet")
+	store.run = func(stdin string, name string, args ...string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		inputs = append(inputs, stdin)
+		if args[len(args)-1] == "get" {
+			return "token=helper-token\n", nil
+		}
+		return "", nil
+	}
+
+	key := CredentialKey{Context: "prod", Server: "https://prod.example/v1/api"}
+	if err := store.Store(key, "helper-token"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	token, err := store.Get(key)
+	if err != nil || token != "helper-token" {
+		t.Fatalf("expected helper token, got %q (%v)", token, err)
+	}
+
+	if calls[0] != "lunie-credential-pass --quiet store" {
+		t.Fatalf("unexpected helper command: %q", calls[0])
+	}
+	want := "context=prod\nserver=https://prod.example/v1/api\ntoken=helper-token\n\n"
+	if inputs[0] != want {
+		t.Fatalf("unexpected helper input: %q", inputs[0])
+	}
+	if strings.Contains(inputs[1], "token=") {
+		t.Fatalf("get must not send a token: %q", inputs[1])
+	}
+}
+
+func TestHelperStoreTreatsEmptyOutputAsNotFound(t *testing.T) {
+	store := newHelperStore("/usr/local/bin/my-helper")
+	store.run = func(stdin string, name string, args ...string) (string, error) {
+		if name != "/usr/local/bin/my-helper" {
+			t.Fatalf("expected helper path to be used as-is, got %q", name)
+		}
+		return "", nil
+	}
+	if _, err := store.Get(CredentialKey{}); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestSecretServiceStoreUsesSecretTool(t *testing.T) {
+	var calls [][]string
+	var stored string
+	store := newSecretServiceStore()
+	store.run = func(stdin string, name string, args ...string) (string, error) {
+		calls = append(calls, append([]string{name}, args...))
+		switch args[0] {
+		case "store":
+			stored = stdin
+		case "lookup":
+			if stored == "" {
+				return "", &commandError{name: name, exitCode: 1}
+			}
+			return stored, nil
+		}
+		return "", nil
+	}
+
+	key := CredentialKey{Context: "prod"}
+	if _, err := store.Get(key); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := store.Store(key, "keyring-token"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	if token, err := store.Get(key); err != nil || token != "keyring-token" {
+		t.Fatalf("expected keyring token, got %q (%v)", token, err)
+	}
+	if !slices.Equal(calls[1][len(calls[1])-4:], []string{"service", "lunie", "account", "prod"}) {
+		t.Fatalf("unexpected attributes: %v", calls[1])
+	}
+}
+
+func TestSecretServiceStoreKeysEntriesByServer(t *testing.T) {
+	tokens := map[string]string{}
+	store := newSecretServiceStore()
+	store.run = func(stdin string, name string, args ...string) (string, error) {
+		attributes := strings.Join(args[len(args)-6:], " ")
+		switch args[0] {
+		case "store":
+			tokens[attributes] = stdin
+		case "lookup":
+			token, ok := tokens[attributes]
+			if !ok {
+				return "", &commandError{name: name, exitCode: 1}
+			}
+			return token, nil
+		}
+		return "", nil
+	}
+
+	old := CredentialKey{Context: "prod", Server: "https://old.example/v1/api"}
+	if err := store.Store(old, "old-token"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	moved := CredentialKey{Context: "prod", Server: "https://new.example/v1/api"}
+	if _, err := store.Get(moved); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected no token for the new server, got %v", err)
+	}
+	if token, err := store.Get(old); err != nil || token != "old-token" {
+		t.Fatalf("expected old server token, got %q (%v)", token, err)
+	}
+}
+
+func TestOpenCredentialStoreRejectsUnknownBackends(t *testing.T) {
+	if _, err := OpenCredentialStore("keychain", CredentialOptions{}); err == nil {
+		t.Fatal("expected unknown store to fail")
+	}
+	if _, err := OpenCredentialStore(StoreHelper, CredentialOptions{}); err == nil {
+		t.Fatal("expected helper without command to fail")
+	}
+}
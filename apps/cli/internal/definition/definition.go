@@ -0,0 +1,126 @@
+package definition
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	StepHTTP      = "http"
+	StepTransform = "transform"
+	StepCondition = "condition"
+)
+
+type Definition struct {
+	Input         map[string]any `json:"input,omitempty"`
+	Notifications []Notification `json:"notifications,omitempty"`
+	Steps         []Step         `json:"steps"`
+}
+
+type Notification struct {
+	Provider string   `json:"provider"`
+	Webhook  string   `json:"webhook"`
+	On       []string `json:"on"`
+}
+
+type Step struct {
+	Key          string         `json:"key"`
+	Type         string         `json:"type"`
+	DependsOn    []string       `json:"dependsOn,omitempty"`
+	Input        map[string]any `json:"input,omitempty"`
+	OutputPolicy *OutputPolicy  `json:"outputPolicy,omitempty"`
+	RateLimit    *RateLimit     `json:"rateLimit,omitempty"`
+	Request      map[string]any `json:"request"`
+}
+
+type OutputPolicy struct {
+	Truncate *bool `json:"truncate,omitempty"`
+	MaxBytes *int  `json:"maxBytes,omitempty"`
+}
+
+type RateLimit struct {
+	Key        string `json:"key"`
+	Max        int    `json:"max"`
+	PerSeconds int    `json:"perSeconds"`
+}
+
+type HTTPRequest struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Query     map[string]any    `json:"query,omitempty"`
+	Body      any               `json:"body,omitempty"`
+	TimeoutMs *int              `json:"timeoutMs,omitempty"`
+}
+
+type TransformRequest struct {
+	Source map[string]any `json:"source,omitempty"`
+	Output any            `json:"output"`
+}
+
+type ConditionRequest struct {
+	Expr    string         `json:"expr"`
+	Assert  *bool          `json:"assert,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Source  map[string]any `json:"source,omitempty"`
+}
+
+func Decode(value any) (Definition, error) {
+	var def Definition
+	if err := convert(value, &def); err != nil {
+		return def, fmt.Errorf("invalid definition: %w", err)
+	}
+	if def.Steps == nil {
+		def.Steps = []Step{}
+	}
+	return def, nil
+}
+
+func (d Definition) Step(key string) (Step, bool) {
+	for _, step := range d.Steps {
+		if step.Key == key {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
+
+func (s Step) HTTP() (HTTPRequest, error) {
+	var request HTTPRequest
+	err := decodeRequest(s, StepHTTP, &request)
+	return request, err
+}
+
+func (s Step) Transform() (TransformRequest, error) {
+	var request TransformRequest
+	err := decodeRequest(s, StepTransform, &request)
+	return request, err
+}
+
+func (s Step) Condition() (ConditionRequest, error) {
+	var request ConditionRequest
+	err := decodeRequest(s, StepCondition, &request)
+	return request, err
+}
+
+func (r ConditionRequest) ShouldAssert() bool {
+	return r.Assert == nil || *r.Assert
+}
+
+func decodeRequest(step Step, stepType string, target any) error {
+	if step.Type != stepType {
+		return fmt.Errorf("step %q is %s, not %s", step.Key, step.Type, stepType)
+	}
+	if err := convert(step.Request, target); err != nil {
+		return fmt.Errorf("step %q: invalid request: %w", step.Key, err)
+	}
+	return nil
+}
+
+func convert(value any, target any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,210 @@
+package definition
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var stepRefPattern = regexp.MustCompile(`\{\{\s*steps\.([a-zA-Z0-9_-]+)(?:\.[^}]*)?\s*\}\}`)
+
+// CycleError lists the steps left unscheduled by a dependency cycle. Path is
+// one concrete cycle among them, in edge order and closed on its first step.
+type CycleError struct {
+	Steps []string
+	Path  []string
+}
+
+func (e *CycleError) Error() string {
+	if len(e.Path) > 0 {
+		return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Path, " -> "))
+	}
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Steps, ", "))
+}
+
+func InferredDependencies(def Definition) map[string][]string {
+	known := stepKeySet(def.Steps)
+	inferred := map[string][]string{}
+	for _, step := range def.Steps {
+		data, err := json.Marshal(step.Request)
+		if err != nil {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, match := range stepRefPattern.FindAllStringSubmatch(string(data), -1) {
+			ref := match[1]
+			if ref == step.Key || !known[ref] || seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			inferred[step.Key] = append(inferred[step.Key], ref)
+		}
+	}
+	return inferred
+}
+
+func Dependencies(def Definition) map[string][]string {
+	known := stepKeySet(def.Steps)
+	inferred := InferredDependencies(def)
+	deps := make(map[string][]string, len(def.Steps))
+	for _, step := range def.Steps {
+		seen := map[string]bool{}
+		list := []string{}
+		for _, dep := range append(append([]string{}, step.DependsOn...), inferred[step.Key]...) {
+			if dep == step.Key || !known[dep] || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			list = append(list, dep)
+		}
+		deps[step.Key] = list
+	}
+	return deps
+}
+
+func ExecutionBatches(def Definition) ([][]string, error) {
+	deps := Dependencies(def)
+	order := make([]string, 0, len(def.Steps))
+	seen := map[string]bool{}
+	for _, step := range def.Steps {
+		if !seen[step.Key] {
+			seen[step.Key] = true
+			order = append(order, step.Key)
+		}
+	}
+
+	inDegree := make(map[string]int, len(order))
+	children := make(map[string][]string, len(order))
+	for _, key := range order {
+		for _, dep := range deps[key] {
+			children[dep] = append(children[dep], key)
+			inDegree[key]++
+		}
+	}
+
+	batches := [][]string{}
+	queue := []string{}
+	for _, key := range order {
+		if inDegree[key] == 0 {
+			queue = append(queue, key)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		batch := queue
+		batches = append(batches, batch)
+		queue = []string{}
+		for _, key := range batch {
+			visited++
+			for _, child := range children[key] {
+				inDegree[child]--
+				if inDegree[child] == 0 {
+					queue = append(queue, child)
+				}
+			}
+		}
+	}
+
+	if visited != len(order) {
+		remaining := []string{}
+		for _, key := range order {
+			if inDegree[key] > 0 {
+				remaining = append(remaining, key)
+			}
+		}
+		return nil, &CycleError{Steps: remaining, Path: cyclePath(remaining, deps)}
+	}
+	return batches, nil
+}
+
+// cyclePath walks dependencies from the first remaining step. Every remaining
+// step still waits on another remaining step, so the walk must revisit one.
+func cyclePath(remaining []string, deps map[string][]string) []string {
+	if len(remaining) == 0 {
+		return nil
+	}
+	pending := make(map[string]bool, len(remaining))
+	for _, key := range remaining {
+		pending[key] = true
+	}
+
+	walk := []string{}
+	index := map[string]int{}
+	key := remaining[0]
+	for {
+		if start, ok := index[key]; ok {
+			loop := walk[start:]
+			path := make([]string, 0, len(loop)+1)
+			path = append(path, loop[0])
+			for i := len(loop) - 1; i >= 0; i-- {
+				path = append(path, loop[i])
+			}
+			return path
+		}
+		index[key] = len(walk)
+		walk = append(walk, key)
+		next := ""
+		for _, dep := range deps[key] {
+			if pending[dep] {
+				next = dep
+				break
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		key = next
+	}
+}
+
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Inferred bool   `json:"inferred"`
+}
+
+type Graph struct {
+	Steps   []Step     `json:"-"`
+	Edges   []Edge     `json:"edges"`
+	Batches [][]string `json:"batches"`
+}
+
+func BuildGraph(def Definition) (Graph, error) {
+	graph := Graph{Steps: def.Steps, Edges: []Edge{}, Batches: [][]string{}}
+	deps := Dependencies(def)
+	for _, step := range def.Steps {
+		explicit := map[string]bool{}
+		for _, dep := range step.DependsOn {
+			explicit[dep] = true
+		}
+		for _, dep := range deps[step.Key] {
+			graph.Edges = append(graph.Edges, Edge{From: dep, To: step.Key, Inferred: !explicit[dep]})
+		}
+	}
+
+	batches, err := ExecutionBatches(def)
+	if err != nil {
+		return graph, err
+	}
+	graph.Batches = batches
+	return graph, nil
+}
+
+func (g Graph) Needs(stepKey string) []Edge {
+	edges := []Edge{}
+	for _, edge := range g.Edges {
+		if edge.To == stepKey {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+func stepKeySet(steps []Step) map[string]bool {
+	keys := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		keys[step.Key] = true
+	}
+	return keys
+}
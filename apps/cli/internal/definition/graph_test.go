@@ -0,0 +1,91 @@
+package definition
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExecutionBatchesUsesExplicitAndTemplateDependencies(t *testing.T) {
+	def := Definition{Steps: []Step{
+		{Key: "users", Type: StepHTTP, Request: map[string]any{"url": "https://x/users"}},
+		{Key: "orders", Type: StepHTTP, Request: map[string]any{"url": "https://x/orders"}},
+		{Key: "join", Type: StepTransform, DependsOn: []string{"users"}, Request: map[string]any{"source": map[string]any{"orders": "{{steps.orders.body}}"}}},
+		{Key: "notify", Type: StepHTTP, DependsOn: []string{"join"}, Request: map[string]any{"url": "https://x/{{ steps.join.id }}"}},
+	}}
+
+	batches, err := ExecutionBatches(def)
+	if err != nil {
+		t.Fatalf("expected batches, got %v", err)
+	}
+	want := [][]string{{"users", "orders"}, {"join"}, {"notify"}}
+	if !reflect.DeepEqual(batches, want) {
+		t.Fatalf("unexpected batches: %v", batches)
+	}
+
+	deps := Dependencies(def)
+	if !reflect.DeepEqual(deps["join"], []string{"users", "orders"}) {
+		t.Fatalf("unexpected join dependencies: %v", deps["join"])
+	}
+}
+
+func TestExecutionBatchesReportsCycle(t *testing.T) {
+	def := Definition{Steps: []Step{
+		{Key: "a", DependsOn: []string{"b"}},
+		{Key: "b", Request: map[string]any{"url": "{{steps.a.id}}"}},
+		{Key: "c"},
+	}}
+
+	_, err := ExecutionBatches(def)
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if !reflect.DeepEqual(cycle.Steps, []string{"a", "b"}) {
+		t.Fatalf("unexpected cycle steps: %v", cycle.Steps)
+	}
+	if !reflect.DeepEqual(cycle.Path, []string{"a", "b", "a"}) {
+		t.Fatalf("unexpected cycle path: %v", cycle.Path)
+	}
+}
+
+func TestExecutionBatchesCyclePathSkipsDownstreamSteps(t *testing.T) {
+	def := Definition{Steps: []Step{
+		{Key: "report", DependsOn: []string{"fetch"}},
+		{Key: "fetch", DependsOn: []string{"parse"}},
+		{Key: "parse", DependsOn: []string{"store"}},
+		{Key: "store", DependsOn: []string{"fetch"}},
+	}}
+
+	_, err := ExecutionBatches(def)
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if !reflect.DeepEqual(cycle.Path, []string{"fetch", "store", "parse", "fetch"}) {
+		t.Fatalf("unexpected cycle path: %v", cycle.Path)
+	}
+	if err.Error() != "dependency cycle detected: fetch -> store -> parse -> fetch" {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
+
+func TestBuildGraphMarksInferredEdges(t *testing.T) {
+	def := Definition{Steps: []Step{
+		{Key: "a"},
+		{Key: "b", DependsOn: []string{"a"}, Request: map[string]any{"body": "{{steps.a.id}}"}},
+		{Key: "c", Request: map[string]any{"body": "{{steps.b.id}}"}},
+	}}
+
+	graph, err := BuildGraph(def)
+	if err != nil {
+		t.Fatalf("expected graph, got %v", err)
+	}
+	want := []Edge{{From: "a", To: "b"}, {From: "b", To: "c", Inferred: true}}
+	if !reflect.DeepEqual(graph.Edges, want) {
+		t.Fatalf("unexpected edges: %+v", graph.Edges)
+	}
+	if len(graph.Batches) != 3 {
+		t.Fatalf("unexpected batches: %v", graph.Batches)
+	}
+}
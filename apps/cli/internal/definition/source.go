@@ -0,0 +1,131 @@
+package definition
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gentij/lunie/apps/cli/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+type Position struct {
+	Line   int
+	Column int
+}
+
+type Source struct {
+	Path      string
+	Value     any
+	Prefix    string
+	positions map[string]Position
+}
+
+func LoadFile(path string) (Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Source{}, err
+	}
+	return Parse(path, data)
+}
+
+func Parse(path string, data []byte) (Source, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return Source{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(node.Content) == 0 {
+		return Source{}, fmt.Errorf("%s: file is empty", path)
+	}
+
+	var raw any
+	if err := node.Content[0].Decode(&raw); err != nil {
+		return Source{}, fmt.Errorf("%s: %w", path, err)
+	}
+	value, err := manifest.Normalize(raw)
+	if err != nil {
+		return Source{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	source := Source{Path: path, Value: value, positions: map[string]Position{}}
+	collectPositions(node.Content[0], "", source.positions)
+
+	if document, ok := value.(map[string]any); ok {
+		if inner, ok := document["definition"]; ok {
+			if _, hasSteps := document["steps"]; !hasSteps {
+				source.Value = inner
+				source.Prefix = "definition"
+			}
+		}
+	}
+	return source, nil
+}
+
+func (s Source) Lint() []Issue {
+	issues := Validate(s.Value)
+	for i := range issues {
+		position := s.Position(issues[i].Field)
+		issues[i].Line = position.Line
+		issues[i].Column = position.Column
+	}
+	sort.SliceStable(issues, func(a, b int) bool {
+		return issues[a].Line < issues[b].Line
+	})
+	return issues
+}
+
+func (s Source) Position(field string) Position {
+	path := field
+	if s.Prefix != "" {
+		path = joinField(s.Prefix, field)
+	}
+	for {
+		if position, ok := s.positions[path]; ok {
+			return position
+		}
+		if path == "" {
+			return Position{}
+		}
+		path = parentField(path)
+	}
+}
+
+func collectPositions(node *yaml.Node, path string, positions map[string]Position) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	positions[path] = Position{Line: node.Line, Column: node.Column}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			collectPositions(node.Content[i+1], joinField(path, node.Content[i].Value), positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			collectPositions(item, fmt.Sprintf("%s[%d]", path, i), positions)
+		}
+	}
+}
+
+func joinField(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	if key == "" {
+		return path
+	}
+	return path + "." + key
+}
+
+func parentField(path string) string {
+	if strings.HasSuffix(path, "]") {
+		if index := strings.LastIndex(path, "["); index >= 0 {
+			return path[:index]
+		}
+	}
+	if index := strings.LastIndex(path, "."); index >= 0 {
+		return path[:index]
+	}
+	return ""
+}
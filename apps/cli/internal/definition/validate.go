@@ -0,0 +1,484 @@
+package definition
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+var (
+	stepKeyPattern      = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	rateLimitKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	secretRefPattern    = regexp.MustCompile(`^\{\{\s*secret\.[A-Za-z0-9_-]+\s*\}\}$`)
+	inputRefPattern     = regexp.MustCompile(`\{\{\s*input\.([a-zA-Z0-9_-]+)(?:\.[^}]*)?\s*\}\}`)
+	templateRootPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_-]+)(?:\.|\}\})`)
+)
+
+var httpMethods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+
+type Issue struct {
+	Field    string `json:"field,omitempty"`
+	StepKey  string `json:"stepKey,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+func (i Issue) Error() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+type validator struct {
+	issues []Issue
+}
+
+func Validate(value any) []Issue {
+	v := &validator{}
+	root, ok := value.(map[string]any)
+	if !ok {
+		v.errorf("", "", "definition must be an object")
+		return v.issues
+	}
+
+	v.unknownFields("", "", root, false, "input", "notifications", "steps")
+	if input, ok := root["input"]; ok {
+		v.record("input", "", input)
+	}
+	if raw, ok := root["notifications"]; ok {
+		v.notifications(raw)
+	}
+
+	steps := []map[string]any{}
+	if raw, ok := root["steps"]; ok {
+		items, ok := raw.([]any)
+		if !ok {
+			v.errorf("steps", "", "must be an array")
+		}
+		for i, item := range items {
+			field := fmt.Sprintf("steps[%d]", i)
+			step, ok := item.(map[string]any)
+			if !ok {
+				v.errorf(field, "", "step must be an object")
+				step = map[string]any{}
+			}
+			v.step(field, step)
+			steps = append(steps, step)
+		}
+	}
+
+	v.references(root, steps)
+	return v.issues
+}
+
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) step(field string, step map[string]any) {
+	raw, hasKey := step["key"]
+	key, isString := raw.(string)
+	switch {
+	case !hasKey:
+		v.errorf(field+".key", "", "key is required")
+	case !isString:
+		v.errorf(field+".key", "", "key must be a string")
+	case !stepKeyPattern.MatchString(key):
+		v.errorf(field+".key", key, "key must contain only letters, numbers, underscore, or hyphen")
+	}
+
+	stepType, _ := step["type"].(string)
+	switch stepType {
+	case StepHTTP, StepTransform, StepCondition:
+	default:
+		v.errorf(field+".type", key, "invalid step type %q (expected http, transform or condition)", step["type"])
+	}
+	v.unknownFields(field, key, step, false, "key", "type", "dependsOn", "input", "outputPolicy", "rateLimit", "request")
+
+	if raw, ok := step["dependsOn"]; ok {
+		items, isArray := raw.([]any)
+		if !isArray {
+			v.errorf(field+".dependsOn", key, "dependsOn must be an array of step keys")
+		}
+		for j, item := range items {
+			if _, isString := item.(string); !isString {
+				v.errorf(fmt.Sprintf("%s.dependsOn[%d]", field, j), key, "dependsOn entries must be strings")
+			}
+		}
+	}
+	if input, ok := step["input"]; ok {
+		v.record(field+".input", key, input)
+	}
+	if raw, ok := step["outputPolicy"]; ok {
+		policy, isObject := raw.(map[string]any)
+		if !isObject {
+			v.errorf(field+".outputPolicy", key, "outputPolicy must be an object")
+		}
+		v.unknownFields(field+".outputPolicy", key, policy, true, "truncate", "maxBytes")
+		if truncate, ok := policy["truncate"]; ok {
+			if _, isBool := truncate.(bool); !isBool {
+				v.errorf(field+".outputPolicy.truncate", key, "truncate must be a boolean")
+			}
+		}
+		if maxBytes, ok := policy["maxBytes"]; ok && !isPositiveInt(maxBytes) {
+			v.errorf(field+".outputPolicy.maxBytes", key, "maxBytes must be a positive integer")
+		}
+	}
+	if raw, ok := step["rateLimit"]; ok {
+		v.rateLimit(field+".rateLimit", key, raw)
+	}
+
+	request, ok := step["request"].(map[string]any)
+	if !ok {
+		v.errorf(field+".request", key, "request must be an object")
+		return
+	}
+	switch stepType {
+	case StepHTTP:
+		v.httpRequest(field+".request", key, request)
+	case StepTransform:
+		v.unknownFields(field+".request", key, request, true, "source", "output")
+		if source, ok := request["source"]; ok {
+			v.record(field+".request.source", key, source)
+		}
+	case StepCondition:
+		v.unknownFields(field+".request", key, request, true, "expr", "assert", "message", "source")
+		if expr, isString := request["expr"].(string); !isString || expr == "" {
+			v.errorf(field+".request.expr", key, "expr must be a non-empty string")
+		}
+		if assert, ok := request["assert"]; ok {
+			if _, isBool := assert.(bool); !isBool {
+				v.errorf(field+".request.assert", key, "assert must be a boolean")
+			}
+		}
+		if message, ok := request["message"]; ok {
+			if text, isString := message.(string); !isString || text == "" {
+				v.errorf(field+".request.message", key, "message must be a non-empty string")
+			}
+		}
+		if source, ok := request["source"]; ok {
+			v.record(field+".request.source", key, source)
+		}
+	}
+}
+
+func (v *validator) httpRequest(field string, key string, request map[string]any) {
+	v.unknownFields(field, key, request, false, "method", "url", "headers", "query", "body", "timeoutMs")
+
+	if method, _ := request["method"].(string); !httpMethods[method] {
+		v.errorf(field+".method", key, "method must be one of GET, POST, PUT, PATCH, DELETE")
+	}
+	rawURL, isString := request["url"].(string)
+	if !isString {
+		v.errorf(field+".url", key, "url is required")
+	} else if !isTemplate(rawURL) && !isHTTPURL(rawURL) {
+		v.errorf(field+".url", key, "url must be an absolute http(s) URL or a template pattern")
+	}
+	if raw, ok := request["headers"]; ok {
+		headers, isObject := raw.(map[string]any)
+		if !isObject {
+			v.errorf(field+".headers", key, "headers must be an object")
+		}
+		for _, name := range sortedKeys(headers) {
+			if _, isString := headers[name].(string); !isString {
+				v.errorf(field+".headers."+name, key, "header values must be strings")
+			}
+		}
+	}
+	if raw, ok := request["query"]; ok {
+		query, isObject := raw.(map[string]any)
+		if !isObject {
+			v.errorf(field+".query", key, "query must be an object")
+		}
+		for _, name := range sortedKeys(query) {
+			switch query[name].(type) {
+			case string, float64, int, bool:
+			default:
+				v.errorf(field+".query."+name, key, "query values must be strings, numbers or booleans")
+			}
+		}
+	}
+	if timeout, ok := request["timeoutMs"]; ok && !isPositiveInt(timeout) {
+		v.errorf(field+".timeoutMs", key, "timeoutMs must be a positive integer")
+	}
+}
+
+func (v *validator) rateLimit(field string, key string, raw any) {
+	limit, ok := raw.(map[string]any)
+	if !ok {
+		v.errorf(field, key, "rateLimit must be an object")
+		return
+	}
+	v.unknownFields(field, key, limit, true, "key", "max", "perSeconds")
+	if name, _ := limit["key"].(string); !rateLimitKeyPattern.MatchString(name) {
+		v.errorf(field+".key", key, "rateLimit.key must contain only letters, numbers, underscore")
+	}
+	for _, name := range []string{"max", "perSeconds"} {
+		if !isPositiveInt(limit[name]) {
+			v.errorf(field+"."+name, key, "%s must be a positive integer", name)
+		}
+	}
+}
+
+func (v *validator) notifications(raw any) {
+	items, ok := raw.([]any)
+	if !ok {
+		v.errorf("notifications", "", "notifications must be an array")
+		return
+	}
+	for i, item := range items {
+		field := fmt.Sprintf("notifications[%d]", i)
+		notification, ok := item.(map[string]any)
+		if !ok {
+			v.errorf(field, "", "notification must be an object")
+			continue
+		}
+		v.unknownFields(field, "", notification, true, "provider", "webhook", "on")
+		switch notification["provider"] {
+		case "slack", "discord":
+		default:
+			v.errorf(field+".provider", "", "provider must be slack or discord")
+		}
+		webhook, _ := notification["webhook"].(string)
+		webhook = strings.TrimSpace(webhook)
+		if !secretRefPattern.MatchString(webhook) && !isHTTPURL(webhook) {
+			v.errorf(field+".webhook", "", "webhook must be an absolute http(s) URL or {{secret.NAME}} reference")
+		}
+		events, _ := notification["on"].([]any)
+		if len(events) == 0 {
+			v.errorf(field+".on", "", "on must list at least one of SUCCEEDED, FAILED")
+		}
+		for j, event := range events {
+			if event != "SUCCEEDED" && event != "FAILED" {
+				v.errorf(fmt.Sprintf("%s.on[%d]", field, j), "", "event must be SUCCEEDED or FAILED")
+			}
+		}
+	}
+}
+
+func (v *validator) references(root map[string]any, steps []map[string]any) {
+	def, err := Decode(root)
+	if err != nil {
+		if !HasErrors(v.issues) {
+			v.errorf("", "", "%s", err.Error())
+		}
+		return
+	}
+
+	known := map[string]bool{}
+	for i, step := range def.Steps {
+		if known[step.Key] {
+			v.errorf(fmt.Sprintf("steps[%d].key", i), step.Key, "duplicate step key %q", step.Key)
+		}
+		known[step.Key] = true
+	}
+
+	for i, step := range def.Steps {
+		for j, dep := range step.DependsOn {
+			field := fmt.Sprintf("steps[%d].dependsOn[%d]", i, j)
+			switch {
+			case dep == step.Key:
+				v.errorf(field, step.Key, "dependsOn cannot reference itself")
+			case !known[dep]:
+				v.errorf(field, step.Key, "dependsOn references unknown step %q", dep)
+			}
+		}
+	}
+
+	workflowInput := def.Input
+	walkStrings(root["input"], "input", func(value string, path string) {
+		v.templateRoots(value, path, "")
+	})
+	for i, notification := range def.Notifications {
+		v.templateRoots(strings.TrimSpace(notification.Webhook), fmt.Sprintf("notifications[%d].webhook", i), "")
+	}
+
+	for i, step := range def.Steps {
+		field := fmt.Sprintf("steps[%d]", i)
+		walkStrings(steps[i]["request"], field+".request", func(value string, path string) {
+			v.templateRoots(value, path, step.Key)
+			for _, match := range stepRefPattern.FindAllStringSubmatch(value, -1) {
+				if !known[match[1]] {
+					v.errorf(path, step.Key, "references unknown step %q", match[1])
+				}
+			}
+			for _, match := range inputRefPattern.FindAllStringSubmatch(value, -1) {
+				_, inWorkflow := workflowInput[match[1]]
+				_, inStep := step.Input[match[1]]
+				if !inWorkflow && !inStep {
+					v.errorf(path, step.Key, "input field %q must be declared in workflow definition.input or step.input", match[1])
+				}
+			}
+		})
+		walkStrings(steps[i]["input"], field+".input", func(value string, path string) {
+			v.templateRoots(value, path, step.Key)
+		})
+
+		switch step.Type {
+		case StepTransform:
+			v.jmesNodes(step.Request["output"], field+".request.output", step.Key)
+		case StepCondition:
+			if expr, _ := step.Request["expr"].(string); strings.TrimSpace(expr) != "" {
+				v.jmesExpression(expr, field+".request.expr", step.Key)
+			}
+		}
+	}
+
+	if _, err := ExecutionBatches(def); err != nil {
+		var cycle *CycleError
+		if errors.As(err, &cycle) {
+			field := "steps"
+			for i, step := range def.Steps {
+				if step.Key == cycle.Steps[0] {
+					field = fmt.Sprintf("steps[%d]", i)
+					break
+				}
+			}
+			v.errorf(field, strings.Join(cycle.Steps, ","), "dependency cycle detected (explicit or template-based); steps involved: %s", strings.Join(cycle.Steps, ", "))
+		}
+	}
+}
+
+func (v *validator) templateRoots(value string, field string, stepKey string) {
+	for _, match := range templateRootPattern.FindAllStringSubmatch(value, -1) {
+		switch match[1] {
+		case "input", "steps", "secret":
+		default:
+			v.errorf(field, stepKey, "invalid template root %q (allowed: input, steps, secret)", match[1])
+		}
+	}
+}
+
+func (v *validator) jmesNodes(node any, field string, stepKey string) {
+	switch value := node.(type) {
+	case []any:
+		for i, item := range value {
+			v.jmesNodes(item, fmt.Sprintf("%s[%d]", field, i), stepKey)
+		}
+	case map[string]any:
+		if raw, ok := value["$jmes"]; ok {
+			if len(value) != 1 {
+				v.errorf(field, stepKey, `$jmes node must be exactly { "$jmes": "..." }`)
+			}
+			expr, isString := raw.(string)
+			if !isString || strings.TrimSpace(expr) == "" {
+				v.errorf(field+".$jmes", stepKey, "$jmes expression must be a non-empty string")
+				return
+			}
+			v.jmesExpression(expr, field+".$jmes", stepKey)
+			return
+		}
+		for _, key := range sortedKeys(value) {
+			v.jmesNodes(value[key], field+"."+key, stepKey)
+		}
+	}
+}
+
+func (v *validator) jmesExpression(expr string, field string, stepKey string) {
+	if _, err := jmespath.Compile(expr); err != nil {
+		v.errorf(field, stepKey, "invalid JMESPath expression: %s", err.Error())
+	}
+}
+
+func (v *validator) record(field string, stepKey string, value any) {
+	if _, ok := value.(map[string]any); !ok {
+		v.errorf(field, stepKey, "must be an object")
+	}
+}
+
+func (v *validator) unknownFields(field string, stepKey string, object map[string]any, strict bool, allowed ...string) {
+	known := map[string]bool{}
+	for _, name := range allowed {
+		known[name] = true
+	}
+	for _, name := range sortedKeys(object) {
+		if known[name] {
+			continue
+		}
+		path := name
+		if field != "" {
+			path = field + "." + name
+		}
+		if strict {
+			v.errorf(path, stepKey, "unrecognized field %q", name)
+		} else {
+			v.add(Issue{Field: path, StepKey: stepKey, Severity: SeverityWarning, Message: fmt.Sprintf("unknown field %q is ignored by the server", name)})
+		}
+	}
+}
+
+func (v *validator) errorf(field string, stepKey string, format string, args ...any) {
+	v.add(Issue{Field: field, StepKey: stepKey, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) add(issue Issue) {
+	v.issues = append(v.issues, issue)
+}
+
+func walkStrings(node any, field string, visit func(value string, field string)) {
+	switch value := node.(type) {
+	case string:
+		visit(value, field)
+	case []any:
+		for i, item := range value {
+			walkStrings(item, fmt.Sprintf("%s[%d]", field, i), visit)
+		}
+	case map[string]any:
+		for _, key := range sortedKeys(value) {
+			walkStrings(value[key], field+"."+key, visit)
+		}
+	}
+}
+
+func isTemplate(value string) bool {
+	return strings.Contains(value, "{{") && strings.Contains(value, "}}")
+}
+
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	return parsed.Scheme == "http" || parsed.Scheme == "https"
+}
+
+func isPositiveInt(value any) bool {
+	switch number := value.(type) {
+	case int:
+		return number > 0
+	case int64:
+		return number > 0
+	case float64:
+		return number > 0 && number == math.Trunc(number)
+	default:
+		return false
+	}
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
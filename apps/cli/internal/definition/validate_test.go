@@ -0,0 +1,163 @@
+package definition
+
+import (
+	"strings"
+	"testing"
+)
+
+func issueMessages(issues []Issue) []string {
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.Severity+" "+issue.Error())
+	}
+	return messages
+}
+
+func expectIssue(t *testing.T, issues []Issue, field string, fragment string) {
+	t.Helper()
+	for _, issue := range issues {
+		if issue.Field == field && strings.Contains(issue.Message, fragment) {
+			return
+		}
+	}
+	t.Fatalf("expected issue at %s containing %q, got %v", field, fragment, issueMessages(issues))
+}
+
+func TestValidateAcceptsValidDefinition(t *testing.T) {
+	def := map[string]any{
+		"input": map[string]any{"apiBase": "https://api.example.com"},
+		"notifications": []any{
+			map[string]any{"provider": "slack", "webhook": "{{secret.SLACK_URL}}", "on": []any{"FAILED"}},
+		},
+		"steps": []any{
+			map[string]any{
+				"key":       "fetch",
+				"type":      "http",
+				"rateLimit": map[string]any{"key": "crm_api", "max": float64(5), "perSeconds": float64(1)},
+				"request": map[string]any{
+					"method":  "GET",
+					"url":     "{{input.apiBase}}/users",
+					"headers": map[string]any{"Authorization": "Bearer {{secret.TOKEN}}"},
+				},
+			},
+			map[string]any{
+				"key":  "count",
+				"type": "transform",
+				"request": map[string]any{
+					"output": map[string]any{"total": map[string]any{"$jmes": "length(steps.fetch)"}},
+				},
+			},
+			map[string]any{
+				"key":          "check",
+				"type":         "condition",
+				"outputPolicy": map[string]any{"maxBytes": float64(1024)},
+				"request":      map[string]any{"expr": "steps.count.total > `0`", "source": map[string]any{"n": "{{steps.count.total}}"}},
+			},
+		},
+	}
+
+	if issues := Validate(def); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issueMessages(issues))
+	}
+}
+
+func TestValidateReportsSchemaErrors(t *testing.T) {
+	def := map[string]any{
+		"notifications": []any{map[string]any{"provider": "teams", "webhook": "ftp://x", "on": []any{}}},
+		"steps": []any{
+			map[string]any{"key": "bad key", "type": "http", "request": map[string]any{"method": "FETCH", "url": "/relative"}},
+			map[string]any{"key": "rl", "type": "http", "rateLimit": map[string]any{"key": "a-b", "max": float64(0), "perSeconds": 1.5}, "request": map[string]any{"method": "GET", "url": "https://x"}},
+			map[string]any{"key": "cond", "type": "condition", "request": map[string]any{"expr": "", "extra": true}},
+			map[string]any{"key": "what", "type": "shell", "request": map[string]any{}},
+		},
+	}
+
+	issues := Validate(def)
+	expectIssue(t, issues, "notifications[0].provider", "slack or discord")
+	expectIssue(t, issues, "notifications[0].webhook", "http(s) URL")
+	expectIssue(t, issues, "notifications[0].on", "at least one")
+	expectIssue(t, issues, "steps[0].key", "letters, numbers")
+	expectIssue(t, issues, "steps[0].request.method", "GET, POST")
+	expectIssue(t, issues, "steps[0].request.url", "absolute http(s) URL")
+	expectIssue(t, issues, "steps[1].rateLimit.key", "letters, numbers, underscore")
+	expectIssue(t, issues, "steps[1].rateLimit.max", "positive integer")
+	expectIssue(t, issues, "steps[1].rateLimit.perSeconds", "positive integer")
+	expectIssue(t, issues, "steps[2].request.expr", "non-empty")
+	expectIssue(t, issues, "steps[2].request.extra", "unrecognized")
+	expectIssue(t, issues, "steps[3].type", "invalid step type")
+}
+
+func TestValidateReportsReferenceErrors(t *testing.T) {
+	def := map[string]any{
+		"input": map[string]any{"known": "x", "bad": "{{env.HOME}}"},
+		"steps": []any{
+			map[string]any{"key": "a", "type": "http", "dependsOn": []any{"a", "ghost"}, "request": map[string]any{"method": "GET", "url": "https://x/{{input.unknown}}"}},
+			map[string]any{"key": "a", "type": "condition", "request": map[string]any{"expr": "`true`"}},
+			map[string]any{"key": "b", "type": "http", "request": map[string]any{"method": "POST", "url": "https://x", "body": "{{steps.c.id}} {{steps.nope.id}}"}},
+			map[string]any{"key": "c", "type": "http", "dependsOn": []any{"b"}, "request": map[string]any{"method": "GET", "url": "https://x"}},
+			map[string]any{"key": "d", "type": "transform", "request": map[string]any{"output": map[string]any{"$jmes": "foo[", "other": 1}}},
+		},
+	}
+
+	issues := Validate(def)
+	expectIssue(t, issues, "input.bad", `invalid template root "env"`)
+	expectIssue(t, issues, "steps[0].dependsOn[0]", "cannot reference itself")
+	expectIssue(t, issues, "steps[0].dependsOn[1]", `unknown step "ghost"`)
+	expectIssue(t, issues, "steps[0].request.url", `input field "unknown"`)
+	expectIssue(t, issues, "steps[1].key", `duplicate step key "a"`)
+	expectIssue(t, issues, "steps[2].request.body", `unknown step "nope"`)
+	expectIssue(t, issues, "steps[2]", "dependency cycle")
+	expectIssue(t, issues, "steps[4].request.output", "exactly")
+	expectIssue(t, issues, "steps[4].request.output.$jmes", "invalid JMESPath")
+}
+
+func TestValidateWarnsAboutIgnoredFields(t *testing.T) {
+	def := map[string]any{
+		"version": "1",
+		"steps":   []any{map[string]any{"key": "a", "type": "http", "request": map[string]any{"method": "GET", "url": "https://x"}}},
+	}
+
+	issues := Validate(def)
+	if HasErrors(issues) {
+		t.Fatalf("expected only warnings, got %v", issueMessages(issues))
+	}
+	expectIssue(t, issues, "version", "ignored")
+}
+
+func TestSourceLintReportsLines(t *testing.T) {
+	data := []byte(`key: sync
+definition:
+  steps:
+    - key: fetch
+      type: http
+      request:
+        method: GET
+        url: not-a-url
+`)
+	source, err := Parse("sync.yaml", data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if source.Prefix != "definition" {
+		t.Fatalf("expected manifest definition to be linted, got prefix %q", source.Prefix)
+	}
+
+	issues := source.Lint()
+	if len(issues) != 1 {
+		t.Fatalf("expected one issue, got %v", issueMessages(issues))
+	}
+	if issues[0].Field != "steps[0].request.url" || issues[0].Line != 8 || issues[0].Column != 14 {
+		t.Fatalf("unexpected issue: %+v", issues[0])
+	}
+}
+
+func TestSourcePositionFallsBackToParent(t *testing.T) {
+	source, err := Parse("def.json", []byte("{\n  \"steps\": [\n    {\"key\": \"a\", \"type\": \"http\"}\n  ]\n}\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	position := source.Position("steps[0].request")
+	if position.Line != 3 {
+		t.Fatalf("expected step line, got %+v", position)
+	}
+}
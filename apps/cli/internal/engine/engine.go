@@ -0,0 +1,233 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/definition"
+)
+
+const (
+	StatusSucceeded = "SUCCEEDED"
+	StatusFailed    = "FAILED"
+	StatusSkipped   = "SKIPPED"
+)
+
+const redacted = "[REDACTED]"
+
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(webhook|token|secret|password|api[-_]?key|authorization)`)
+
+type StepResult struct {
+	Key        string    `json:"key"`
+	Type       string    `json:"type"`
+	Batch      int       `json:"batch"`
+	Status     string    `json:"status"`
+	Input      any       `json:"input,omitempty"`
+	Request    any       `json:"request,omitempty"`
+	Output     any       `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+type Result struct {
+	Status     string       `json:"status"`
+	Steps      []StepResult `json:"steps"`
+	DurationMs int64        `json:"durationMs"`
+}
+
+type Engine struct {
+	HTTPClient *http.Client
+	Secrets    map[string]string
+	OnStep     func(StepResult)
+}
+
+func (e *Engine) Run(ctx context.Context, def definition.Definition, input map[string]any) (Result, error) {
+	batches, err := definition.ExecutionBatches(def)
+	if err != nil {
+		return Result{}, err
+	}
+	deps := definition.Dependencies(def)
+
+	runInput := map[string]any{}
+	for key, value := range def.Input {
+		runInput[key] = value
+	}
+	for key, value := range input {
+		runInput[key] = value
+	}
+
+	started := time.Now()
+	result := Result{Status: StatusSucceeded, Steps: []StepResult{}}
+	outputs := map[string]any{}
+	statuses := map[string]string{}
+
+	for index, batch := range batches {
+		results := make([]StepResult, len(batch))
+		var wg sync.WaitGroup
+		for i, key := range batch {
+			step, _ := def.Step(key)
+			scope := Scope{Input: stepInput(runInput, step), Steps: map[string]any{}, Secret: e.Secrets}
+			blocked := ""
+			for _, dep := range deps[key] {
+				if statuses[dep] != StatusSucceeded {
+					blocked = dep
+					break
+				}
+				scope.Steps[dep] = outputs[dep]
+			}
+			if blocked != "" || ctx.Err() != nil {
+				reason := fmt.Sprintf("dependency %q did not succeed", blocked)
+				if blocked == "" {
+					reason = ctx.Err().Error()
+				}
+				results[i] = StepResult{Key: key, Type: step.Type, Batch: index + 1, Status: StatusSkipped, Error: reason, StartedAt: time.Now()}
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int, step definition.Step, scope Scope) {
+				defer wg.Done()
+				results[i] = e.runStep(ctx, step, scope, index+1)
+			}(i, step, scope)
+		}
+		wg.Wait()
+
+		for _, step := range results {
+			statuses[step.Key] = step.Status
+			if step.Status == StatusSucceeded {
+				outputs[step.Key] = step.Output
+			} else {
+				result.Status = StatusFailed
+			}
+			step = e.redact(step)
+			result.Steps = append(result.Steps, step)
+			if e.OnStep != nil {
+				e.OnStep(step)
+			}
+		}
+	}
+
+	result.DurationMs = time.Since(started).Milliseconds()
+	return result, nil
+}
+
+func (e *Engine) runStep(ctx context.Context, step definition.Step, scope Scope, batch int) StepResult {
+	result := StepResult{Key: step.Key, Type: step.Type, Batch: batch, Input: scope.Input, StartedAt: time.Now()}
+	fail := func(err error) StepResult {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		result.DurationMs = time.Since(result.StartedAt).Milliseconds()
+		return result
+	}
+
+	for _, name := range SecretNames(step.Request) {
+		if _, ok := e.Secrets[name]; !ok {
+			return fail(fmt.Errorf("secret %q not found", name))
+		}
+	}
+
+	resolved, err := Resolve(step.Request, scope)
+	if err != nil {
+		return fail(err)
+	}
+	request, _ := resolved.(map[string]any)
+	if request == nil {
+		request = map[string]any{}
+	}
+	result.Request = request
+
+	var output map[string]any
+	switch step.Type {
+	case definition.StepHTTP:
+		output, err = e.executeHTTP(ctx, request)
+	case definition.StepTransform:
+		output, err = executeTransform(request, scope)
+	case definition.StepCondition:
+		output, err = executeCondition(request, scope)
+	default:
+		err = fmt.Errorf("no executor for step type %q", step.Type)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	result.Status = StatusSucceeded
+	result.Output = output
+	result.DurationMs = time.Since(result.StartedAt).Milliseconds()
+	return result
+}
+
+func (e *Engine) httpClient() *http.Client {
+	if e.HTTPClient != nil {
+		return e.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (e *Engine) redact(step StepResult) StepResult {
+	values := make([]string, 0, len(e.Secrets))
+	for _, value := range e.Secrets {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	step.Input = redactValue(step.Input, values)
+	step.Request = redactValue(step.Request, values)
+	step.Output = redactValue(step.Output, values)
+	if step.Error != "" {
+		step.Error = redactString(step.Error, values)
+	}
+	return step
+}
+
+func redactValue(value any, secrets []string) any {
+	switch v := value.(type) {
+	case string:
+		return redactString(v, secrets)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item, secrets)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if sensitiveKeyPattern.MatchString(key) {
+				out[key] = redacted
+				continue
+			}
+			out[key] = redactValue(item, secrets)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func redactString(text string, secrets []string) string {
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	return text
+}
+
+func stepInput(runInput map[string]any, step definition.Step) map[string]any {
+	input := make(map[string]any, len(runInput)+len(step.Input))
+	for key, value := range runInput {
+		input[key] = value
+	}
+	for key, value := range step.Input {
+		input[key] = value
+	}
+	return input
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gentij/lunie/apps/cli/internal/definition"
+)
+
+func TestRunExecutesStepsInDependencyOrder(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &received)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"users":[{"email":"a@test"},{"email":"b@test"}]}`))
+	}))
+	defer server.Close()
+
+	def, err := definition.Decode(map[string]any{
+		"input": map[string]any{"apiBase": server.URL},
+		"steps": []any{
+			map[string]any{"key": "fetch", "type": "http", "request": map[string]any{
+				"method": "GET", "url": "{{input.apiBase}}/users",
+				"headers": map[string]any{"Authorization": "Bearer {{secret.TOKEN}}"},
+			}},
+			map[string]any{"key": "shape", "type": "transform", "request": map[string]any{
+				"source": map[string]any{"users": "{{steps.fetch.users}}"},
+				"output": map[string]any{"count": map[string]any{"$jmes": "length(source.users)"}},
+			}},
+			map[string]any{"key": "check", "type": "condition", "dependsOn": []any{"shape"}, "request": map[string]any{"expr": "steps.shape.count > `1`"}},
+			map[string]any{"key": "post", "type": "http", "dependsOn": []any{"check"}, "request": map[string]any{
+				"method": "POST", "url": "{{input.apiBase}}/report", "body": map[string]any{"total": "{{steps.shape.body.count}}"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	seen := []string{}
+	runner := &Engine{Secrets: map[string]string{"TOKEN": "s3cret"}, OnStep: func(step StepResult) { seen = append(seen, step.Key) }}
+	result, err := runner.Run(context.Background(), def, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.Status != StatusSucceeded {
+		t.Fatalf("expected success, got %+v", result.Steps)
+	}
+	if len(seen) != 4 || seen[0] != "fetch" || seen[3] != "post" {
+		t.Fatalf("unexpected order: %v", seen)
+	}
+	if received["total"] != "2" {
+		t.Fatalf("unexpected posted body: %v", received)
+	}
+	request := result.Steps[0].Request.(map[string]any)
+	if request["headers"].(map[string]any)["Authorization"] != redacted {
+		t.Fatalf("expected secret to be redacted, got %v", request["headers"])
+	}
+}
+
+func TestRunSkipsDependentsOfFailedSteps(t *testing.T) {
+	def := definition.Definition{Steps: []definition.Step{
+		{Key: "gate", Type: definition.StepCondition, Request: map[string]any{"expr": "input.enabled", "message": "disabled"}},
+		{Key: "soft", Type: definition.StepCondition, Request: map[string]any{"expr": "input.enabled", "assert": false}},
+		{Key: "after", Type: definition.StepTransform, DependsOn: []string{"gate"}, Request: map[string]any{"output": "x"}},
+	}}
+
+	result, err := (&Engine{}).Run(context.Background(), def, map[string]any{"enabled": false})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Fatalf("expected failure, got %s", result.Status)
+	}
+	statuses := map[string]string{}
+	for _, step := range result.Steps {
+		statuses[step.Key] = step.Status
+	}
+	if statuses["gate"] != StatusFailed || statuses["soft"] != StatusSucceeded || statuses["after"] != StatusSkipped {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+	if result.Steps[0].Error != "condition failed: disabled" {
+		t.Fatalf("unexpected error: %q", result.Steps[0].Error)
+	}
+}
@@ -0,0 +1,256 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/definition"
+	"github.com/jmespath/go-jmespath"
+)
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	httpSoftMaxBytes   = 256 * 1024
+	httpHardMaxBytes   = 10 * 1024 * 1024
+)
+
+func (e *Engine) executeHTTP(ctx context.Context, request map[string]any) (map[string]any, error) {
+	var spec definition.HTTPRequest
+	if err := convert(request, &spec); err != nil {
+		return nil, fmt.Errorf("invalid http request: %w", err)
+	}
+
+	target, err := url.Parse(spec.URL)
+	if err != nil || target.Host == "" || (target.Scheme != "http" && target.Scheme != "https") {
+		return nil, fmt.Errorf("invalid URL after template resolution: %q", spec.URL)
+	}
+	if len(spec.Query) > 0 {
+		query := target.Query()
+		for key, value := range spec.Query {
+			query.Add(key, stringify(value))
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	timeout := defaultHTTPTimeout
+	if spec.TimeoutMs != nil {
+		timeout = time.Duration(*spec.TimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	headers := map[string]string{}
+	for key, value := range spec.Headers {
+		headers[key] = value
+	}
+	if truthy(spec.Body) && (spec.Method == http.MethodPost || spec.Method == http.MethodPut || spec.Method == http.MethodPatch) {
+		data, err := json.Marshal(spec.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		if _, ok := headers["Content-Type"]; !ok {
+			headers["Content-Type"] = "application/json"
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, spec.Method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	text, err := io.ReadAll(io.LimitReader(resp.Body, httpSoftMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(text) > httpSoftMaxBytes {
+		return nil, fmt.Errorf("HTTP response truncated (softMaxBytes=%d bytesRead=%d)", httpSoftMaxBytes, len(text))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	var data any = string(text)
+	if strings.Contains(contentType, "application/json") {
+		var parsed any
+		if err := json.Unmarshal(text, &parsed); err == nil {
+			data = parsed
+		}
+	}
+
+	responseHeaders := map[string]any{}
+	for key, values := range resp.Header {
+		responseHeaders[strings.ToLower(key)] = strings.Join(values, ", ")
+	}
+
+	return map[string]any{
+		"statusCode": float64(resp.StatusCode),
+		"headers":    responseHeaders,
+		"body": map[string]any{
+			"_lunieHttp": map[string]any{
+				"contentType":  contentType,
+				"truncated":    false,
+				"bytesRead":    float64(len(text)),
+				"softMaxBytes": float64(httpSoftMaxBytes),
+				"hardMaxBytes": float64(httpHardMaxBytes),
+			},
+			"data": data,
+		},
+	}, nil
+}
+
+func executeTransform(request map[string]any, scope Scope) (map[string]any, error) {
+	root := jmesRoot(request, scope, false)
+	body, err := evaluateJMESNodes(request["output"], root)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"statusCode": float64(200), "body": body}, nil
+}
+
+func executeCondition(request map[string]any, scope Scope) (map[string]any, error) {
+	var spec definition.ConditionRequest
+	if err := convert(request, &spec); err != nil {
+		return nil, fmt.Errorf("invalid condition request: %w", err)
+	}
+
+	value, err := jmespath.Search(spec.Expr, jmesRoot(request, scope, true))
+	if err != nil {
+		value = nil
+	}
+	passed := jmesTruthy(value)
+	if spec.ShouldAssert() && !passed {
+		if spec.Message != "" {
+			return nil, fmt.Errorf("condition failed: %s", spec.Message)
+		}
+		return nil, fmt.Errorf("condition failed")
+	}
+	return map[string]any{"statusCode": float64(200), "body": map[string]any{"passed": passed, "value": value}}, nil
+}
+
+func jmesRoot(request map[string]any, scope Scope, unwrapHTTP bool) map[string]any {
+	source, ok := request["source"].(map[string]any)
+	if !ok {
+		source = map[string]any{}
+	}
+	responses := map[string]any{}
+	steps := map[string]any{}
+	for key, output := range scope.Steps {
+		responses[key] = output
+		steps[key] = output
+		object, ok := output.(map[string]any)
+		if !ok {
+			continue
+		}
+		body, ok := object["body"]
+		if !ok {
+			continue
+		}
+		steps[key] = body
+		if data, ok := unwrapHTTPBody(body); ok && unwrapHTTP {
+			steps[key] = data
+		}
+	}
+	input := map[string]any{}
+	for key, value := range scope.Input {
+		input[key] = value
+	}
+	return map[string]any{"input": input, "source": source, "steps": steps, "stepResponses": responses}
+}
+
+func evaluateJMESNodes(node any, root map[string]any) (any, error) {
+	switch v := node.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			value, err := evaluateJMESNodes(item, root)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = value
+		}
+		return out, nil
+	case map[string]any:
+		if raw, ok := v["$jmes"]; ok && len(v) == 1 {
+			expr, isString := raw.(string)
+			if !isString || strings.TrimSpace(expr) == "" {
+				return nil, fmt.Errorf("$jmes expression must be a non-empty string")
+			}
+			value, err := jmespath.Search(expr, root)
+			if err != nil {
+				return nil, fmt.Errorf("JMESPath evaluation failed: %w", err)
+			}
+			return value, nil
+		}
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			value, err := evaluateJMESNodes(item, root)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = value
+		}
+		return out, nil
+	default:
+		return node, nil
+	}
+}
+
+func jmesTruthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	default:
+		return true
+	}
+}
+
+func convert(value any, target any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func Search(expr string, scope Scope, unwrapHTTP bool) (any, error) {
+	return jmespath.Search(expr, jmesRoot(nil, scope, unwrapHTTP))
+}
@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	fullStepPattern    = regexp.MustCompile(`^\{\{\s*steps\.([a-zA-Z0-9_-]+)(\.[^}]*)?\s*\}\}$`)
+	fullInputPattern   = regexp.MustCompile(`^\{\{\s*input\.([a-zA-Z0-9_-]+)(\.[^}]*)?\s*\}\}$`)
+	fullSecretPattern  = regexp.MustCompile(`^\{\{\s*secret\.([a-zA-Z0-9_-]+)\s*\}\}$`)
+	stepPattern        = regexp.MustCompile(`\{\{steps\.([a-zA-Z0-9_-]+)(\.[^}]*)?\}\}`)
+	inputPattern       = regexp.MustCompile(`\{\{input\.([a-zA-Z0-9_-]+)(\.[^}]*)?\}\}`)
+	secretPattern      = regexp.MustCompile(`\{\{secret\.([a-zA-Z0-9_-]+)\}\}`)
+	secretNamesPattern = regexp.MustCompile(`\{\{\s*secret\.([a-zA-Z0-9_-]+)\s*\}\}`)
+)
+
+type Scope struct {
+	Input  map[string]any
+	Steps  map[string]any
+	Secret map[string]string
+}
+
+func Resolve(value any, scope Scope) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return resolveString(v, scope)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := Resolve(item, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			resolved, err := Resolve(item, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = resolved
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+func SecretNames(value any) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	var walk func(node any)
+	walk = func(node any) {
+		switch v := node.(type) {
+		case string:
+			for _, match := range secretNamesPattern.FindAllStringSubmatch(v, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					names = append(names, match[1])
+				}
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	return names
+}
+
+func resolveString(template string, scope Scope) (any, error) {
+	if match := fullStepPattern.FindStringSubmatch(template); match != nil {
+		value, err := stepReference(match[1], match[2], scope)
+		if err != nil {
+			return nil, err
+		}
+		return coerceSingle(value), nil
+	}
+	if match := fullInputPattern.FindStringSubmatch(template); match != nil {
+		value, err := inputReference(match[1], match[2], scope)
+		if err != nil {
+			return nil, err
+		}
+		return coerceSingle(value), nil
+	}
+	if match := fullSecretPattern.FindStringSubmatch(template); match != nil {
+		value, err := secretReference(match[1], scope)
+		if err != nil {
+			return nil, err
+		}
+		return coerceSingle(value), nil
+	}
+
+	result, err := replaceAll(stepPattern, template, func(groups []string) (string, error) {
+		value, err := stepReference(groups[1], groups[2], scope)
+		return coerceInterpolated(value), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err = replaceAll(inputPattern, result, func(groups []string) (string, error) {
+		value, err := inputReference(groups[1], groups[2], scope)
+		return coerceInterpolated(value), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replaceAll(secretPattern, result, func(groups []string) (string, error) {
+		value, err := secretReference(groups[1], scope)
+		return coerceInterpolated(value), err
+	})
+}
+
+func stepReference(stepKey string, path string, scope Scope) (any, error) {
+	output, ok := scope.Steps[stepKey]
+	if !ok || output == nil {
+		return nil, fmt.Errorf("referenced step %q does not exist or has not completed", stepKey)
+	}
+
+	data := output
+	if object, ok := output.(map[string]any); ok {
+		if body, ok := unwrapHTTPBody(object["body"]); ok {
+			data = body
+		}
+	}
+
+	clean := cleanReferencePath(path, "output")
+	if clean == "" {
+		return data, nil
+	}
+	value, ok := lookupPath(data, clean)
+	if !ok {
+		return nil, fmt.Errorf("path %q not found in step %q output", path, stepKey)
+	}
+	return value, nil
+}
+
+func inputReference(key string, path string, scope Scope) (any, error) {
+	data, ok := scope.Input[key]
+	if !ok {
+		return nil, fmt.Errorf("input field %q not found in workflow input", key)
+	}
+
+	clean := cleanReferencePath(path, "")
+	if clean == "" {
+		return data, nil
+	}
+	value, ok := lookupPath(data, clean)
+	if !ok {
+		return nil, fmt.Errorf("path %q not found in workflow input field %q", path, key)
+	}
+	return value, nil
+}
+
+func secretReference(name string, scope Scope) (any, error) {
+	value, ok := scope.Secret[name]
+	if !ok {
+		return nil, fmt.Errorf("secret %q not found", name)
+	}
+	return value, nil
+}
+
+func cleanReferencePath(path string, prefix string) string {
+	clean := strings.TrimSpace(strings.TrimPrefix(path, "."))
+	if prefix != "" && strings.HasPrefix(clean, prefix) {
+		clean = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(clean, prefix), "."))
+	}
+	return clean
+}
+
+func lookupPath(base any, path string) (any, bool) {
+	value := base
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		switch current := value.(type) {
+		case map[string]any:
+			next, ok := current[part]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, false
+			}
+			value = current[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func unwrapHTTPBody(value any) (any, bool) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	data, hasData := object["data"]
+	_, hasMeta := object["_lunieHttp"]
+	if !hasData || !hasMeta {
+		return nil, false
+	}
+	return data, true
+}
+
+func coerceInterpolated(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return stringify(v)
+	}
+}
+
+func coerceSingle(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case map[string]any, []any:
+		return v
+	default:
+		return stringify(v)
+	}
+}
+
+func stringify(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func replaceAll(pattern *regexp.Regexp, text string, replace func(groups []string) (string, error)) (string, error) {
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		groups := make([]string, len(loc)/2)
+		for i := range groups {
+			if loc[2*i] >= 0 {
+				groups[i] = text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		replacement, err := replace(groups)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(replacement)
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
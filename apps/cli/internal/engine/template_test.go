@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testScope() Scope {
+	return Scope{
+		Input: map[string]any{"apiBase": "https://api.test", "postId": float64(7), "filters": map[string]any{"tag": "new"}},
+		Steps: map[string]any{
+			"fetch": map[string]any{
+				"statusCode": float64(200),
+				"body": map[string]any{
+					"_lunieHttp": map[string]any{"contentType": "application/json"},
+					"data":       map[string]any{"user": map[string]any{"name": "Ada"}, "items": []any{"a", "b"}},
+				},
+			},
+		},
+		Secret: map[string]string{"TOKEN": "t0k"},
+	}
+}
+
+func TestResolveFullReferencesKeepObjects(t *testing.T) {
+	value, err := Resolve(map[string]any{
+		"user":    "{{ steps.fetch.user }}",
+		"filters": "{{input.filters}}",
+		"id":      "{{input.postId}}",
+		"first":   "{{steps.fetch.output.items.0}}",
+	}, testScope())
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	want := map[string]any{
+		"user":    map[string]any{"name": "Ada"},
+		"filters": map[string]any{"tag": "new"},
+		"id":      "7",
+		"first":   "a",
+	}
+	if !reflect.DeepEqual(value, want) {
+		t.Fatalf("unexpected value: %#v", value)
+	}
+}
+
+func TestResolveInterpolatesStrings(t *testing.T) {
+	value, err := Resolve("{{input.apiBase}}/posts/{{input.postId}}?name={{steps.fetch.user.name}}&t={{secret.TOKEN}}&f={{input.filters}}", testScope())
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if value != `https://api.test/posts/7?name=Ada&t=t0k&f={"tag":"new"}` {
+		t.Fatalf("unexpected value: %v", value)
+	}
+}
+
+func TestResolveReportsMissingReferences(t *testing.T) {
+	cases := map[string]string{
+		"{{steps.other.id}}":        `referenced step "other"`,
+		"x {{input.nope}}":          `input field "nope"`,
+		"{{secret.MISSING}}":        `secret "MISSING"`,
+		"{{steps.fetch.user.age}}":  `path ".user.age" not found`,
+		"{{input.filters.tag.len}}": `not found in workflow input field "filters"`,
+	}
+	for template, want := range cases {
+		_, err := Resolve(template, testScope())
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected error containing %q, got %v", template, want, err)
+		}
+	}
+}
+
+func TestSecretNames(t *testing.T) {
+	names := SecretNames(map[string]any{"headers": map[string]any{"a": "{{secret.A}} {{ secret.B }}"}, "body": []any{"{{secret.A}}"}})
+	if len(names) != 2 {
+		t.Fatalf("unexpected names: %v", names)
+	}
+}
@@ -1,6 +1,199 @@
+// Package errors classifies CLI failures so scripts can tell them apart by
+// exit code or by the class in the JSON error envelope.
 package errors
 
-type APIError struct {
-	Code    string
-	Message string
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Exit codes are part of the CLI contract; keep docs/CLI-Usage.md in sync.
+const (
+	ExitError        = 1
+	ExitRunFailed    = 2
+	ExitRunTimeout   = 3
+	ExitDifferences  = 4
+	ExitUsage        = 5
+	ExitValidation   = 6
+	ExitUnauthorized = 7
+	ExitForbidden    = 8
+	ExitNotFound     = 9
+	ExitConflict     = 10
+	ExitRateLimited  = 11
+	ExitNetwork      = 12
+	ExitTimeout      = 13
+	ExitServer       = 14
+	ExitInterrupted  = 130
+)
+
+// Class is a sentinel error naming a failure category. Errors are matched
+// against it with errors.Is.
+type Class struct {
+	name     string
+	exitCode int
+}
+
+func (c *Class) Error() string {
+	return strings.ReplaceAll(c.name, "_", " ")
+}
+
+// Name is the stable identifier used in the JSON error envelope.
+func (c *Class) Name() string {
+	return c.name
+}
+
+func (c *Class) ExitCode() int {
+	return c.exitCode
+}
+
+var (
+	ErrUsage        = &Class{name: "usage", exitCode: ExitUsage}
+	ErrValidation   = &Class{name: "validation", exitCode: ExitValidation}
+	ErrUnauthorized = &Class{name: "unauthorized", exitCode: ExitUnauthorized}
+	ErrForbidden    = &Class{name: "forbidden", exitCode: ExitForbidden}
+	ErrNotFound     = &Class{name: "not_found", exitCode: ExitNotFound}
+	ErrConflict     = &Class{name: "conflict", exitCode: ExitConflict}
+	ErrRateLimited  = &Class{name: "rate_limited", exitCode: ExitRateLimited}
+	ErrNetwork      = &Class{name: "network", exitCode: ExitNetwork}
+	ErrTimeout      = &Class{name: "timeout", exitCode: ExitTimeout}
+	ErrServer       = &Class{name: "server", exitCode: ExitServer}
+	ErrInterrupted  = &Class{name: "interrupted", exitCode: ExitInterrupted}
+)
+
+var classes = []*Class{
+	ErrInterrupted,
+	ErrUsage,
+	ErrValidation,
+	ErrUnauthorized,
+	ErrForbidden,
+	ErrNotFound,
+	ErrConflict,
+	ErrRateLimited,
+	ErrTimeout,
+	ErrNetwork,
+	ErrServer,
+}
+
+// FromResponse maps an API failure to its class. The status code wins; the
+// error code is used when the status is missing, e.g. in older envelopes.
+func FromResponse(statusCode int, code string) *Class {
+	switch {
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusConflict:
+		return ErrConflict
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case statusCode >= 500:
+		return ErrServer
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	switch {
+	case code == "NOT_FOUND", strings.HasSuffix(code, "_NOT_FOUND"):
+		return ErrNotFound
+	case code == "UNAUTHORIZED", strings.HasPrefix(code, "AUTH_"):
+		return ErrUnauthorized
+	case code == "FORBIDDEN", strings.HasSuffix(code, "_FORBIDDEN"):
+		return ErrForbidden
+	case code == "VALIDATION_ERROR", code == "BAD_REQUEST", strings.Contains(code, "_INVALID_"):
+		return ErrValidation
+	case code == "CONFLICT", code == "UNIQUE_CONSTRAINT":
+		return ErrConflict
+	case code == "RATE_LIMITED":
+		return ErrRateLimited
+	case code == "INTERNAL_ERROR":
+		return ErrServer
+	}
+	return nil
+}
+
+// Classify returns the class of err, or nil for unclassified failures.
+func Classify(err error) *Class {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrInterrupted
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	for _, class := range classes {
+		if errors.Is(err, class) {
+			return class
+		}
+	}
+	return nil
+}
+
+// ExitCode returns the process exit code for err's class, or ExitError.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if class := Classify(err); class != nil {
+		return class.ExitCode()
+	}
+	return ExitError
+}
+
+// Usage marks err as invalid command-line usage.
+func Usage(err error) error {
+	return &classified{class: ErrUsage, err: err}
+}
+
+// Validation marks err as input that failed validation, such as a definition
+// rejected by lint.
+func Validation(err error) error {
+	return &classified{class: ErrValidation, err: err}
+}
+
+// Conflict marks err as a request the server refused in its current state.
+func Conflict(err error) error {
+	return &classified{class: ErrConflict, err: err}
+}
+
+// Network marks err as a failure to reach the server.
+func Network(err error) error {
+	return &classified{class: ErrNetwork, err: err}
+}
+
+type classified struct {
+	class *Class
+	err   error
+}
+
+func (e *classified) Error() string {
+	return e.err.Error()
+}
+
+func (e *classified) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+func IsNetwork(err error) bool {
+	return errors.Is(err, ErrNetwork)
 }
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFromResponsePrefersStatusCode(t *testing.T) {
+	cases := []struct {
+		status int
+		code   string
+		want   *Class
+	}{
+		{http.StatusNotFound, "WORKFLOW_NOT_FOUND", ErrNotFound},
+		{http.StatusUnauthorized, "AUTH_INVALID_TOKEN", ErrUnauthorized},
+		{http.StatusBadRequest, "VALIDATION_ERROR", ErrValidation},
+		{http.StatusConflict, "WORKFLOW_INVALID_STATE", ErrConflict},
+		{http.StatusTooManyRequests, "", ErrRateLimited},
+		{http.StatusBadGateway, "", ErrServer},
+		{0, "SECRET_NOT_FOUND", ErrNotFound},
+		{0, "AUTH_REVOKED_TOKEN", ErrUnauthorized},
+		{0, "UNIQUE_CONSTRAINT", ErrConflict},
+		{0, "SOMETHING_ELSE", nil},
+	}
+	for _, tc := range cases {
+		if got := FromResponse(tc.status, tc.code); got != tc.want {
+			t.Errorf("FromResponse(%d, %q) = %v, want %v", tc.status, tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestExitCodeFollowsWrappedClass(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{errors.New("boom"), ExitError},
+		{fmt.Errorf("get: %w", Usage(errors.New("bad flag"))), ExitUsage},
+		{Network(errors.New("connection refused")), ExitNetwork},
+		{Validation(errors.New("1 of 1 file(s) failed lint")), ExitValidation},
+		{Conflict(errors.New("trigger is inactive")), ExitConflict},
+		{fmt.Errorf("GET /health: %w", context.DeadlineExceeded), ExitTimeout},
+		{fmt.Errorf("GET /health: %w", context.Canceled), ExitInterrupted},
+	}
+	for _, tc := range cases {
+		if got := ExitCode(tc.err); got != tc.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestClassifiedKeepsMessage(t *testing.T) {
+	err := Usage(errors.New("invalid run number: x"))
+	if err.Error() != "invalid run number: x" || !errors.Is(err, ErrUsage) {
+		t.Fatalf("unexpected usage error: %v", err)
+	}
+}
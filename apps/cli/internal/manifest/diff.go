@@ -0,0 +1,166 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+type Difference struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+func Diff(before any, after any) []Difference {
+	left, err := Normalize(before)
+	if err != nil {
+		left = before
+	}
+	right, err := Normalize(after)
+	if err != nil {
+		right = after
+	}
+
+	diffs := []Difference{}
+	diffValues("", left, right, &diffs)
+	return diffs
+}
+
+func diffValues(path string, before any, after any, diffs *[]Difference) {
+	switch left := before.(type) {
+	case map[string]any:
+		right, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(left)+len(right))
+		for key := range left {
+			keys = append(keys, key)
+		}
+		for key := range right {
+			if _, ok := left[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := joinPath(path, key)
+			leftValue, inLeft := left[key]
+			rightValue, inRight := right[key]
+			switch {
+			case !inLeft:
+				*diffs = append(*diffs, Difference{Path: childPath, Kind: DiffAdded, After: rightValue})
+			case !inRight:
+				*diffs = append(*diffs, Difference{Path: childPath, Kind: DiffRemoved, Before: leftValue})
+			default:
+				diffValues(childPath, leftValue, rightValue, diffs)
+			}
+		}
+		return
+	case []any:
+		right, ok := after.([]any)
+		if !ok {
+			break
+		}
+		if leftKeys, rightKeys, ok := keyedItems(left, right); ok {
+			diffKeyedItems(path, left, right, leftKeys, rightKeys, diffs)
+			return
+		}
+		for i := 0; i < len(left) || i < len(right); i++ {
+			childPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(left):
+				*diffs = append(*diffs, Difference{Path: childPath, Kind: DiffAdded, After: right[i]})
+			case i >= len(right):
+				*diffs = append(*diffs, Difference{Path: childPath, Kind: DiffRemoved, Before: left[i]})
+			default:
+				diffValues(childPath, left[i], right[i], diffs)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*diffs = append(*diffs, Difference{Path: path, Kind: DiffChanged, Before: before, After: after})
+	}
+}
+
+func diffKeyedItems(path string, left []any, right []any, leftKeys []string, rightKeys []string, diffs *[]Difference) {
+	rightIndex := map[string]int{}
+	for i, key := range rightKeys {
+		rightIndex[key] = i
+	}
+	leftIndex := map[string]int{}
+	leftCommon := []string{}
+	for i, key := range leftKeys {
+		leftIndex[key] = i
+		childPath := fmt.Sprintf("%s[key=%s]", path, key)
+		j, ok := rightIndex[key]
+		if !ok {
+			*diffs = append(*diffs, Difference{Path: childPath, Kind: DiffRemoved, Before: left[i]})
+			continue
+		}
+		leftCommon = append(leftCommon, key)
+		diffValues(childPath, left[i], right[j], diffs)
+	}
+	rightCommon := []string{}
+	for j, key := range rightKeys {
+		if _, ok := leftIndex[key]; ok {
+			rightCommon = append(rightCommon, key)
+			continue
+		}
+		*diffs = append(*diffs, Difference{Path: fmt.Sprintf("%s[key=%s]", path, key), Kind: DiffAdded, After: right[j]})
+	}
+	if !reflect.DeepEqual(leftCommon, rightCommon) {
+		*diffs = append(*diffs, Difference{Path: path, Kind: DiffChanged, Before: leftKeys, After: rightKeys})
+	}
+}
+
+func keyedItems(left []any, right []any) ([]string, []string, bool) {
+	if len(left) == 0 && len(right) == 0 {
+		return nil, nil, false
+	}
+	leftKeys, ok := itemKeys(left)
+	if !ok {
+		return nil, nil, false
+	}
+	rightKeys, ok := itemKeys(right)
+	if !ok {
+		return nil, nil, false
+	}
+	return leftKeys, rightKeys, true
+}
+
+func itemKeys(items []any) ([]string, bool) {
+	keys := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	for _, item := range items {
+		object, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		key, ok := object["key"].(string)
+		if !ok || key == "" || seen[key] {
+			return nil, false
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys, true
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
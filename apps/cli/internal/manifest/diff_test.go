@@ -0,0 +1,62 @@
+package manifest
+
+import "testing"
+
+func TestDiffReportsNestedChanges(t *testing.T) {
+	before := map[string]any{
+		"steps": []any{
+			map[string]any{"key": "fetch", "type": "http", "request": map[string]any{"url": "https://a"}},
+			map[string]any{"key": "notify", "type": "http"},
+		},
+	}
+	after := map[string]any{
+		"steps": []any{
+			map[string]any{"key": "fetch", "type": "http", "request": map[string]any{"url": "https://b"}},
+			map[string]any{"key": "check", "type": "condition"},
+		},
+	}
+
+	diffs := Diff(before, after)
+	want := []Difference{
+		{Path: "steps[key=fetch].request.url", Kind: DiffChanged, Before: "https://a", After: "https://b"},
+		{Path: "steps[key=notify]", Kind: DiffRemoved},
+		{Path: "steps[key=check]", Kind: DiffAdded},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("unexpected diff: %+v", diffs)
+	}
+	for i := range want {
+		if diffs[i].Path != want[i].Path || diffs[i].Kind != want[i].Kind {
+			t.Fatalf("unexpected diff at %d: %+v", i, diffs[i])
+		}
+	}
+	if diffs[0].Before != "https://a" || diffs[0].After != "https://b" {
+		t.Fatalf("unexpected values: %+v", diffs[0])
+	}
+}
+
+func TestDiffDetectsReorderedSteps(t *testing.T) {
+	before := map[string]any{"steps": []any{map[string]any{"key": "a"}, map[string]any{"key": "b"}}}
+	after := map[string]any{"steps": []any{map[string]any{"key": "b"}, map[string]any{"key": "a"}}}
+
+	diffs := Diff(before, after)
+	if len(diffs) != 1 || diffs[0].Path != "steps" || diffs[0].Kind != DiffChanged {
+		t.Fatalf("expected reorder diff, got %+v", diffs)
+	}
+}
+
+func TestDiffIgnoresYAMLNumberTypes(t *testing.T) {
+	before := map[string]any{"timeoutMs": float64(5000), "tags": []any{"x"}}
+	after := map[string]any{"timeoutMs": 5000, "tags": []string{"x"}}
+
+	if diffs := Diff(before, after); len(diffs) != 0 {
+		t.Fatalf("expected no differences, got %+v", diffs)
+	}
+}
+
+func TestDiffIndexesUnkeyedArrays(t *testing.T) {
+	diffs := Diff([]any{"a", "b"}, []any{"a", "c", "d"})
+	if len(diffs) != 2 || diffs[0].Path != "[1]" || diffs[1].Path != "[2]" || diffs[1].Kind != DiffAdded {
+		t.Fatalf("unexpected diff: %+v", diffs)
+	}
+}
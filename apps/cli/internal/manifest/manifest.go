@@ -0,0 +1,354 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/yaml.v3"
+)
+
+const KindWorkflow = "Workflow"
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type Workflow struct {
+	Kind       string    `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Key        string    `json:"key" yaml:"key"`
+	Name       string    `json:"name,omitempty" yaml:"name,omitempty"`
+	IsActive   *bool     `json:"isActive,omitempty" yaml:"isActive,omitempty"`
+	Definition any       `json:"definition" yaml:"definition"`
+	Triggers   []Trigger `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	Secrets    []string  `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	Source string `json:"-" yaml:"-"`
+}
+
+type Trigger struct {
+	Key      string `json:"key" yaml:"key"`
+	Type     string `json:"type" yaml:"type"`
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	IsActive *bool  `json:"isActive,omitempty" yaml:"isActive,omitempty"`
+	Config   any    `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+func (w Workflow) DisplayName() string {
+	if strings.TrimSpace(w.Name) != "" {
+		return strings.TrimSpace(w.Name)
+	}
+	return w.Key
+}
+
+func (w Workflow) Active() bool {
+	return w.IsActive == nil || *w.IsActive
+}
+
+func (t Trigger) DisplayName() string {
+	if strings.TrimSpace(t.Name) != "" {
+		return strings.TrimSpace(t.Name)
+	}
+	return t.Key
+}
+
+func (t Trigger) Active() bool {
+	return t.IsActive == nil || *t.IsActive
+}
+
+func (t Trigger) ConfigValue() any {
+	if t.Config == nil {
+		return map[string]any{}
+	}
+	return t.Config
+}
+
+func Load(path string) ([]Workflow, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no manifest files found in %s", path)
+		}
+	}
+
+	workflows := []Workflow{}
+	seen := map[string]string{}
+	for _, file := range files {
+		items, err := LoadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if previous, ok := seen[item.Key]; ok {
+				return nil, fmt.Errorf("%s: workflow %q is already declared in %s", file, item.Key, previous)
+			}
+			seen[item.Key] = file
+			workflows = append(workflows, item)
+		}
+	}
+
+	return workflows, nil
+}
+
+func LoadFile(path string) ([]Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := []Workflow{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for index := 0; ; index++ {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		var raw any
+		if err := node.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if raw == nil {
+			continue
+		}
+
+		normalized, err := Normalize(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		var workflow Workflow
+		strict := json.NewDecoder(bytes.NewReader(encoded))
+		strict.DisallowUnknownFields()
+		if err := strict.Decode(&workflow); err != nil {
+			return nil, fmt.Errorf("%s (document %d): %w", path, index+1, err)
+		}
+		workflow.Source = path
+		if err := workflow.Validate(); err != nil {
+			return nil, fmt.Errorf("%s (document %d): %w", path, index+1, err)
+		}
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
+
+func (w *Workflow) Validate() error {
+	if w.Kind != "" && w.Kind != KindWorkflow {
+		return fmt.Errorf("unsupported kind %q (expected %s)", w.Kind, KindWorkflow)
+	}
+	w.Key = strings.TrimSpace(w.Key)
+	if !keyPattern.MatchString(w.Key) {
+		return fmt.Errorf("invalid workflow key %q (use lowercase letters, numbers and hyphens)", w.Key)
+	}
+	if _, ok := w.Definition.(map[string]any); !ok {
+		return fmt.Errorf("workflow %q: definition must be an object", w.Key)
+	}
+
+	triggerKeys := map[string]bool{}
+	for i := range w.Triggers {
+		trigger := &w.Triggers[i]
+		trigger.Key = strings.TrimSpace(trigger.Key)
+		trigger.Type = strings.ToUpper(strings.TrimSpace(trigger.Type))
+		if !keyPattern.MatchString(trigger.Key) {
+			return fmt.Errorf("workflow %q: invalid trigger key %q", w.Key, trigger.Key)
+		}
+		if triggerKeys[trigger.Key] {
+			return fmt.Errorf("workflow %q: duplicate trigger key %q", w.Key, trigger.Key)
+		}
+		triggerKeys[trigger.Key] = true
+		switch trigger.Type {
+		case "MANUAL", "WEBHOOK", "CRON":
+		default:
+			return fmt.Errorf("workflow %q: trigger %q has invalid type %q (MANUAL|CRON|WEBHOOK)", w.Key, trigger.Key, trigger.Type)
+		}
+	}
+
+	for i, name := range w.Secrets {
+		w.Secrets[i] = strings.TrimSpace(name)
+		if w.Secrets[i] == "" {
+			return fmt.Errorf("workflow %q: secret names cannot be empty", w.Key)
+		}
+	}
+
+	return nil
+}
+
+func Normalize(value any) (any, error) {
+	data, err := json.Marshal(convertYAML(value))
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func Equal(a any, b any) bool {
+	left, err := Normalize(a)
+	if err != nil {
+		return false
+	}
+	right, err := Normalize(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+var (
+	definitionFields  = fieldSet("input", "notifications", "steps")
+	stepFields        = fieldSet("key", "type", "dependsOn", "input", "outputPolicy", "rateLimit", "request")
+	httpRequestFields = fieldSet("method", "url", "headers", "query", "body", "timeoutMs")
+)
+
+// ServerDefinition returns definition as the server stores it: the server's
+// schema drops unknown keys from the definition, its steps and HTTP requests,
+// and defaults missing steps to []. Comparing against it keeps apply and diff
+// from reporting changes the server would discard.
+func ServerDefinition(definition any) any {
+	normalized, err := Normalize(definition)
+	if err != nil {
+		return definition
+	}
+	object, ok := normalized.(map[string]any)
+	if !ok {
+		return normalized
+	}
+
+	out := pickFields(object, definitionFields)
+	steps, ok := out["steps"]
+	if !ok {
+		out["steps"] = []any{}
+		return out
+	}
+	list, ok := steps.([]any)
+	if !ok {
+		return out
+	}
+	for i, item := range list {
+		step, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		step = pickFields(step, stepFields)
+		if request, ok := step["request"].(map[string]any); ok && step["type"] == "http" {
+			step["request"] = pickFields(request, httpRequestFields)
+		}
+		list[i] = step
+	}
+	return out
+}
+
+func fieldSet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+func pickFields(object map[string]any, fields map[string]bool) map[string]any {
+	out := make(map[string]any, len(object))
+	for key, value := range object {
+		if fields[key] {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func convertYAML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = convertYAML(item)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = convertYAML(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = convertYAML(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func manifestFiles(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// Slugify mirrors the server's slugifyKey: NFKD decomposition splits accented
+// letters into a base letter and combining marks, and every non-ASCII rune left
+// over is dropped, so "Café" becomes "cafe".
+func Slugify(value string) string {
+	var b strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(strings.TrimSpace(norm.NFKD.String(value))) {
+		switch {
+		case r > 0x7f:
+			continue
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+			lastHyphen = false
+		default:
+			if !lastHyphen {
+				b.WriteByte('-')
+				lastHyphen = true
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFileReadsMultipleYAMLDocuments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflows.yaml")
+	content := `key: sync-crm
+name: Sync CRM
+definition:
+  steps:
+    - key: fetch
+      type: http
+      request: {method: GET, url: "https://example.com"}
+triggers:
+  - key: nightly
+    type: cron
+    config: {cron: "0 2 * * *", timezone: UTC}
+secrets: [CRM_TOKEN]
+---
+key: daily-digest
+definition: {steps: []}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	workflows, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("expected manifests to load, got %v", err)
+	}
+	if len(workflows) != 2 {
+		t.Fatalf("expected 2 workflows, got %d", len(workflows))
+	}
+	if workflows[0].Triggers[0].Type != "CRON" {
+		t.Fatalf("expected trigger type to be normalized, got %q", workflows[0].Triggers[0].Type)
+	}
+	if workflows[1].DisplayName() != "daily-digest" || !workflows[1].Active() {
+		t.Fatalf("unexpected defaults for second workflow: %#v", workflows[1])
+	}
+}
+
+func TestLoadFileRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflow.json")
+	if err := os.WriteFile(path, []byte(`{"key":"a","definition":{},"trigers":[]}`), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	_, err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), "trigers") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestEqualIgnoresNumberRepresentation(t *testing.T) {
+	left := map[string]any{"timeoutMs": 1000, "tags": []any{"a"}}
+	right := map[string]any{"timeoutMs": float64(1000), "tags": []any{"a"}}
+	if !Equal(left, right) {
+		t.Fatal("expected int and float values to compare equal")
+	}
+	if Equal(left, map[string]any{"timeoutMs": 2000, "tags": []any{"a"}}) {
+		t.Fatal("expected different values to compare unequal")
+	}
+}
+
+func TestServerDefinitionDropsKeysTheServerStrips(t *testing.T) {
+	manifest := map[string]any{
+		"description": "kept in git only",
+		"steps": []any{
+			map[string]any{"key": "fetch", "type": "http", "note": "x", "request": map[string]any{"method": "GET", "url": "https://x", "retries": 3}},
+			map[string]any{"key": "shape", "type": "transform", "request": map[string]any{"output": map[string]any{"extra": true}}},
+		},
+	}
+	stored := map[string]any{
+		"steps": []any{
+			map[string]any{"key": "fetch", "type": "http", "request": map[string]any{"method": "GET", "url": "https://x"}},
+			map[string]any{"key": "shape", "type": "transform", "request": map[string]any{"output": map[string]any{"extra": true}}},
+		},
+	}
+	if !Equal(ServerDefinition(manifest), ServerDefinition(stored)) {
+		t.Fatalf("expected stripped keys to be ignored, got %v", ServerDefinition(manifest))
+	}
+	if !Equal(ServerDefinition(map[string]any{"input": map[string]any{"a": 1}}), map[string]any{"input": map[string]any{"a": 1}, "steps": []any{}}) {
+		t.Fatal("expected missing steps to default to []")
+	}
+	if len(Diff(ServerDefinition(stored), ServerDefinition(manifest))) != 0 {
+		t.Fatal("expected no diff after normalization")
+	}
+}
+
+func TestSlugifyMatchesServerKeys(t *testing.T) {
+	tests := map[string]string{
+		"Sync CRM":        "sync-crm",
+		"daily_digest":    "daily-digest",
+		"  Ops -- Check ": "ops-check",
+		"Manual":          "manual",
+		"Café Sync":       "cafe-sync",
+		"Ünïcödé ﬁles":    "unicode-files",
+		"東京 report":       "report",
+	}
+	for input, want := range tests {
+		if got := Slugify(input); got != want {
+			t.Fatalf("Slugify(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
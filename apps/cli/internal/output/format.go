@@ -0,0 +1,233 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mode is a parsed --output value. Expr holds the expression of the jsonpath
+// and go-template formats.
+type Mode struct {
+	Format   Format
+	Expr     string
+	path     *jsonPathTemplate
+	template *template.Template
+}
+
+var current = Mode{Format: FormatTable}
+
+// ParseMode parses an --output value such as "yaml" or "jsonpath={.id}".
+func ParseMode(value string) (Mode, error) {
+	value = strings.TrimSpace(value)
+	name, expr, hasExpr := strings.Cut(value, "=")
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	if format == "" {
+		format = FormatTable
+	}
+
+	switch format {
+	case FormatTable, FormatWide, FormatJSON, FormatYAML, FormatCSV, FormatTSV:
+		if hasExpr {
+			return Mode{}, fmt.Errorf("output format %s does not take an expression", format)
+		}
+		return Mode{Format: format}, nil
+	case FormatJSONPath:
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return Mode{}, err
+		}
+		return Mode{Format: format, Expr: expr, path: path}, nil
+	case FormatGoTemplate:
+		if strings.TrimSpace(expr) == "" {
+			return Mode{}, fmt.Errorf("go-template output needs a template: --output 'go-template={{.id}}'")
+		}
+		tmpl, err := template.New("output").Funcs(templateFuncs).Parse(expr)
+		if err != nil {
+			return Mode{}, fmt.Errorf("invalid go-template: %w", err)
+		}
+		return Mode{Format: format, Expr: expr, template: tmpl}, nil
+	}
+	return Mode{}, fmt.Errorf("invalid output format: %s (expected %s)", value, FormatNames)
+}
+
+const FormatNames = "table|wide|json|yaml|csv|tsv|jsonpath=<expr>|go-template=<template>"
+
+func SetMode(mode Mode) {
+	current = mode
+}
+
+func CurrentMode() Mode {
+	return current
+}
+
+// Structured reports whether the format prints data documents rather than
+// table rows.
+func (f Format) Structured() bool {
+	switch f {
+	case FormatJSON, FormatYAML, FormatJSONPath, FormatGoTemplate:
+		return true
+	}
+	return false
+}
+
+// Delimited reports whether table rows are written as CSV or TSV.
+func (f Format) Delimited() bool {
+	return f == FormatCSV || f == FormatTSV
+}
+
+// Print writes v in the current structured format; table formats fall back
+// to JSON.
+func Print(v any) error {
+	switch current.Format {
+	case FormatYAML:
+		return printYAML(v, false)
+	case FormatJSONPath, FormatGoTemplate:
+		return printExpr(v)
+	default:
+		return PrintJSON(v)
+	}
+}
+
+// PrintRecord writes one item of a stream: a JSON line, a YAML document, or
+// the result of the jsonpath or go-template expression.
+func PrintRecord(v any) error {
+	switch current.Format {
+	case FormatYAML:
+		return printYAML(v, true)
+	case FormatJSONPath, FormatGoTemplate:
+		return printExpr(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	}
+}
+
+// genericValue converts v to the maps and slices encoding/json produces, so
+// expressions use the same field names as the JSON output.
+func genericValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// printYAML goes through JSON so field names, omitempty and key order match
+// the JSON output. JSON is valid YAML, so it decodes into a node tree whose
+// flow styles are reset to block style.
+func printYAML(v any, document bool) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	if document {
+		buf.WriteString("---\n")
+	}
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(buf.Bytes())
+	return err
+}
+
+// resetYAMLStyle switches nodes to block style. Strings get the style the
+// encoder picks for them, which quotes values such as "no" that YAML 1.1
+// readers would take for booleans.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		var encoded yaml.Node
+		if err := encoded.Encode(node.Value); err == nil {
+			node.Style = encoded.Style
+		}
+	}
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+func printExpr(v any) error {
+	value, err := genericValue(v)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if current.Format == FormatGoTemplate {
+		if err := current.template.Execute(&buf, value); err != nil {
+			return fmt.Errorf("go-template: %w", err)
+		}
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+	} else {
+		text, err := current.path.execute(value)
+		if err != nil {
+			return fmt.Errorf("jsonpath %s: %w", current.Expr, err)
+		}
+		buf.WriteString(text)
+	}
+	_, err = os.Stdout.Write(buf.Bytes())
+	return err
+}
+
+// scalarText prints strings and numbers bare and other values as compact JSON.
+func scalarText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"join": func(sep string, values []any) string {
+		parts := make([]string, 0, len(values))
+		for _, value := range values {
+			parts = append(parts, scalarText(value))
+		}
+		return strings.Join(parts, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
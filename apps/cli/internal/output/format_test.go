@@ -0,0 +1,165 @@
+package output
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func captureStdout(t *testing.T, mode string, fn func() error) string {
+	t.Helper()
+	parsed, err := ParseMode(mode)
+	if err != nil {
+		t.Fatalf("parse %q: %v", mode, err)
+	}
+	previousMode, previousStdout := current, os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	SetMode(parsed)
+	os.Stdout = writer
+	defer func() { current, os.Stdout = previousMode, previousStdout }()
+
+	runErr := fn()
+	writer.Close()
+	data, _ := io.ReadAll(reader)
+	if runErr != nil {
+		t.Fatalf("print: %v", runErr)
+	}
+	return string(data)
+}
+
+type sampleRun struct {
+	Number  int      `json:"number"`
+	Status  string   `json:"status"`
+	Tags    []string `json:"tags,omitempty"`
+	Enabled string   `json:"enabled"`
+}
+
+var sampleRuns = map[string]any{"items": []sampleRun{
+	{Number: 7, Status: "FAILED", Tags: []string{"nightly"}, Enabled: "true"},
+	{Number: 8, Status: "SUCCEEDED", Enabled: "no"},
+}}
+
+func TestParseModeValidatesFormats(t *testing.T) {
+	for _, value := range []string{"", "table", "WIDE", "yaml", "csv", "tsv", "jsonpath={.items[*].id}", "go-template={{.id}}"} {
+		if _, err := ParseMode(value); err != nil {
+			t.Fatalf("expected %q to parse: %v", value, err)
+		}
+	}
+	for _, value := range []string{"xml", "json=x", "jsonpath=", "jsonpath={.items[}", "go-template={{.id"} {
+		if _, err := ParseMode(value); err == nil {
+			t.Fatalf("expected %q to fail", value)
+		}
+	}
+}
+
+func TestPrintYAMLKeepsJSONFieldOrderAndTypes(t *testing.T) {
+	got := captureStdout(t, "yaml", func() error { return Print(sampleRuns) })
+	want := `items:
+  - number: 7
+    status: FAILED
+    tags:
+      - nightly
+    enabled: "true"
+  - number: 8
+    status: SUCCEEDED
+    enabled: "no"
+`
+	if got != want {
+		t.Fatalf("unexpected yaml:\n%s", got)
+	}
+}
+
+func TestPrintJSONPath(t *testing.T) {
+	cases := map[string]string{
+		"jsonpath={.items[*].number}":                     "7\n8\n",
+		"jsonpath=$.items[-1].status":                     "SUCCEEDED\n",
+		"jsonpath=items[?(@.status == 'FAILED')].number":  "7\n",
+		"jsonpath={..tags[0]}":                            "nightly\n",
+		"jsonpath={.items[0:1]}":                          `{"enabled":"true","number":7,"status":"FAILED","tags":["nightly"]}` + "\n",
+		"jsonpath={.items[?(@.number >= 8)]['status']}":   "SUCCEEDED\n",
+		"jsonpath={.items[?(@.tags)].number}":             "7\n",
+		"jsonpath={.items[*].tags[0]}":                    "nightly\n",
+		"jsonpath={.items[?(@.number > 9)].status}":       "",
+		"jsonpath={.items[?(@.status != 'a==b')].number}": "7\n8\n",
+	}
+	for mode, want := range cases {
+		got := captureStdout(t, mode, func() error { return Print(sampleRuns) })
+		if got != want {
+			t.Fatalf("%s: expected %q, got %q", mode, want, got)
+		}
+	}
+}
+
+func TestPrintJSONPathTemplates(t *testing.T) {
+	cases := map[string]string{
+		`jsonpath={range .items[*]}{.number}{"\t"}{.status}{"\n"}{end}`:  "7\tFAILED\n8\tSUCCEEDED\n",
+		`jsonpath=runs: {.items[*].number}{'\n'}`:                        "runs: 7 8\n",
+		`jsonpath={range .items[*]}{$.items[0].number}-{@.number} {end}`: "7-7 7-8 ",
+	}
+	for mode, want := range cases {
+		got := captureStdout(t, mode, func() error { return Print(sampleRuns) })
+		if got != want {
+			t.Fatalf("%s: expected %q, got %q", mode, want, got)
+		}
+	}
+
+	for _, mode := range []string{"jsonpath={range .items[*]}{.number}", "jsonpath={.number}{end}", `jsonpath={"\t}`} {
+		if _, err := ParseMode(mode); err == nil {
+			t.Fatalf("expected %q to be rejected", mode)
+		}
+	}
+}
+
+func TestPrintJSONPathRejectsMissingKeys(t *testing.T) {
+	cases := map[string]string{
+		"jsonpath={.missing}":         "jsonpath {.missing}: missing is not found",
+		"jsonpath={.items[0].nope}":   "jsonpath {.items[0].nope}: nope is not found",
+		"jsonpath={.items[5].number}": "jsonpath {.items[5].number}: array index [5] is out of bounds",
+	}
+	for mode, want := range cases {
+		parsed, err := ParseMode(mode)
+		if err != nil {
+			t.Fatalf("parse %q: %v", mode, err)
+		}
+		previous := current
+		SetMode(parsed)
+		err = Print(sampleRuns)
+		SetMode(previous)
+		if err == nil || err.Error() != want {
+			t.Fatalf("%s: expected %q, got %v", mode, want, err)
+		}
+	}
+}
+
+func TestPrintGoTemplate(t *testing.T) {
+	mode := `go-template={{range .items}}{{.number}} {{lower .status}}{{"\n"}}{{end}}`
+	got := captureStdout(t, mode, func() error { return Print(sampleRuns) })
+	if got != "7 failed\n8 succeeded\n" {
+		t.Fatalf("unexpected template output: %q", got)
+	}
+
+	got = captureStdout(t, "go-template={{.status}}", func() error { return PrintRecord(sampleRun{Status: "RUNNING"}) })
+	if got != "RUNNING\n" {
+		t.Fatalf("expected a trailing newline per record, got %q", got)
+	}
+}
+
+func TestPrintListTableAsDelimited(t *testing.T) {
+	rows := [][]string{{"7", "FAILED", "a,b"}, {"8", "SUCCEEDED", ""}}
+	got := captureStdout(t, "csv", func() error { return PrintListTable([]string{"RUN", "STATUS", "NOTE"}, rows) })
+	if got != "RUN,STATUS,NOTE\n7,FAILED,\"a,b\"\n8,SUCCEEDED,\n" {
+		t.Fatalf("unexpected csv: %q", got)
+	}
+
+	got = captureStdout(t, "tsv", func() error { return PrintListRows([]string{"RUN", "STATUS"}, [][]string{{"9", "RUNNING"}}, false) })
+	if got != "9\tRUNNING\n" {
+		t.Fatalf("unexpected tsv: %q", got)
+	}
+	if strings.Contains(captureStdout(t, "csv", func() error { return PrintListTable([]string{"S"}, [][]string{{ColorStatus("FAILED")}}) }), "\x1b") {
+		t.Fatal("expected csv output without colors")
+	}
+}
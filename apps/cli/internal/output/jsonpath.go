@@ -0,0 +1,613 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression. It supports the subset used in
+// practice: .field, ['field'], [n], [start:end], [*], .*, ..field (recursive
+// descent) and filters such as [?(@.status == 'FAILED')].
+type jsonPath struct {
+	steps []pathStep
+}
+
+type pathStep struct {
+	recursive bool
+	wildcard  bool
+	field     string
+	isField   bool
+	index     *int
+	slice     *[2]*int
+	filter    *pathFilter
+}
+
+type pathFilter struct {
+	path     *jsonPath
+	operator string
+	literal  any
+}
+
+// jsonPathTemplate is a kubectl-style template: text mixed with {expr},
+// {"literal"} and {range expr}...{end} actions. A template that is a single
+// expression prints one value per line; otherwise the text is printed as
+// written and the values of one expression are separated by spaces.
+type jsonPathTemplate struct {
+	nodes []templateNode
+}
+
+type templateNode struct {
+	text     string
+	path     *jsonPath
+	fromRoot bool
+	isRange  bool
+	body     []templateNode
+}
+
+func parseJSONPath(expr string) (*jsonPathTemplate, error) {
+	text := strings.TrimSpace(expr)
+	if text == "" || text == "{}" {
+		return nil, fmt.Errorf("jsonpath output needs an expression: --output 'jsonpath={.items[*].id}'")
+	}
+	if !strings.Contains(text, "{") {
+		text = "{" + text + "}"
+	}
+	nodes, _, err := parseTemplateNodes(text, 0, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %q: %w", expr, err)
+	}
+	return &jsonPathTemplate{nodes: nodes}, nil
+}
+
+// parseTemplateNodes parses text from offset start until the end of the
+// template or, inside a range, until its {end}. It returns the offset after
+// the last parsed action.
+func parseTemplateNodes(text string, start int, inRange bool) ([]templateNode, int, error) {
+	nodes := []templateNode{}
+	i := start
+	for i < len(text) {
+		if text[i] != '{' {
+			end := strings.IndexByte(text[i:], '{')
+			if end < 0 {
+				end = len(text)
+			} else {
+				end += i
+			}
+			nodes = append(nodes, templateNode{text: text[i:end]})
+			i = end
+			continue
+		}
+
+		end := closingBrace(text, i)
+		if end < 0 {
+			return nil, 0, fmt.Errorf("missing '}' for '{' at offset %d", i)
+		}
+		action := strings.TrimSpace(text[i+1 : end])
+		i = end + 1
+		switch {
+		case action == "":
+			return nil, 0, fmt.Errorf("empty action at offset %d", end-1)
+		case action == "end":
+			if !inRange {
+				return nil, 0, fmt.Errorf("{end} without {range}")
+			}
+			return nodes, i, nil
+		case strings.HasPrefix(action, "range "):
+			node, err := parseTemplatePath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, 0, err
+			}
+			body, next, err := parseTemplateNodes(text, i, true)
+			if err != nil {
+				return nil, 0, err
+			}
+			node.isRange, node.body = true, body
+			nodes = append(nodes, node)
+			i = next
+		case action[0] == '"' || action[0] == '\'':
+			literal, err := unquoteLiteral(action)
+			if err != nil {
+				return nil, 0, err
+			}
+			nodes = append(nodes, templateNode{text: literal})
+		default:
+			node, err := parseTemplatePath(action)
+			if err != nil {
+				return nil, 0, err
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	if inRange {
+		return nil, 0, fmt.Errorf("{range} without {end}")
+	}
+	return nodes, i, nil
+}
+
+// parseTemplatePath parses the expression of an action. Paths starting with $
+// start at the document; other paths start at the current range item.
+func parseTemplatePath(text string) (templateNode, error) {
+	node := templateNode{fromRoot: strings.HasPrefix(text, "$")}
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "$"), "@")
+	if text == "." {
+		text = ""
+	}
+	path, err := parsePathSteps(text)
+	if err != nil {
+		return templateNode{}, err
+	}
+	node.path = path
+	return node, nil
+}
+
+func unquoteLiteral(text string) (string, error) {
+	if len(text) < 2 || text[len(text)-1] != text[0] {
+		return "", fmt.Errorf("unterminated string %s", text)
+	}
+	if text[0] == '\'' {
+		text = `"` + strings.ReplaceAll(text[1:len(text)-1], `"`, `\"`) + `"`
+	}
+	literal, err := strconv.Unquote(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", text)
+	}
+	return literal, nil
+}
+
+func closingBrace(text string, start int) int {
+	var quote byte
+	for i := start + 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *jsonPathTemplate) execute(root any) (string, error) {
+	var buf strings.Builder
+	if len(t.nodes) == 1 && t.nodes[0].path != nil && !t.nodes[0].isRange {
+		results, err := t.nodes[0].path.find(root)
+		if err != nil {
+			return "", err
+		}
+		for _, result := range results {
+			buf.WriteString(scalarText(result))
+			buf.WriteByte('\n')
+		}
+		return buf.String(), nil
+	}
+	if err := executeNodes(&buf, t.nodes, root, root); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func executeNodes(buf *strings.Builder, nodes []templateNode, root any, item any) error {
+	for _, node := range nodes {
+		if node.path == nil {
+			buf.WriteString(node.text)
+			continue
+		}
+		start := item
+		if node.fromRoot {
+			start = root
+		}
+		results, err := node.path.find(start)
+		if err != nil {
+			return err
+		}
+		for i, result := range results {
+			if node.isRange {
+				if err := executeNodes(buf, node.body, root, result); err != nil {
+					return err
+				}
+				continue
+			}
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(scalarText(result))
+		}
+	}
+	return nil
+}
+
+func parsePathSteps(text string) (*jsonPath, error) {
+	path := &jsonPath{}
+	i := 0
+	if text != "" && text[0] != '.' && text[0] != '[' {
+		text = "." + text
+	}
+	for i < len(text) {
+		switch text[i] {
+		case '.':
+			step := pathStep{}
+			i++
+			if i < len(text) && text[i] == '.' {
+				step.recursive = true
+				i++
+			}
+			if i < len(text) && text[i] == '[' {
+				if !step.recursive {
+					return nil, fmt.Errorf("unexpected '[' after '.'")
+				}
+				bracket, next, err := parseBracket(text, i)
+				if err != nil {
+					return nil, err
+				}
+				bracket.recursive = true
+				path.steps = append(path.steps, bracket)
+				i = next
+				continue
+			}
+			start := i
+			for i < len(text) && text[i] != '.' && text[i] != '[' {
+				i++
+			}
+			name := text[start:i]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("empty field name at offset %d", start)
+			case "*":
+				step.wildcard = true
+			default:
+				step.field = name
+				step.isField = true
+			}
+			path.steps = append(path.steps, step)
+		case '[':
+			step, next, err := parseBracket(text, i)
+			if err != nil {
+				return nil, err
+			}
+			path.steps = append(path.steps, step)
+			i = next
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", text[i], i)
+		}
+	}
+	return path, nil
+}
+
+// parseBracket parses the [...] selector starting at text[start] and returns
+// the offset after the closing bracket.
+func parseBracket(text string, start int) (pathStep, int, error) {
+	end := closingBracket(text, start)
+	if end < 0 {
+		return pathStep{}, 0, fmt.Errorf("missing ']' for '[' at offset %d", start)
+	}
+	inner := strings.TrimSpace(text[start+1 : end])
+	next := end + 1
+
+	switch {
+	case inner == "*":
+		return pathStep{wildcard: true}, next, nil
+	case strings.HasPrefix(inner, "?"):
+		filter, err := parseFilter(inner[1:])
+		if err != nil {
+			return pathStep{}, 0, err
+		}
+		return pathStep{filter: filter}, next, nil
+	case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+		return pathStep{field: inner[1 : len(inner)-1], isField: true}, next, nil
+	case strings.Contains(inner, ":"):
+		from, to, _ := strings.Cut(inner, ":")
+		bounds := [2]*int{}
+		for i, raw := range []string{from, to} {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return pathStep{}, 0, fmt.Errorf("invalid slice bound %q", raw)
+			}
+			bounds[i] = &value
+		}
+		return pathStep{slice: &bounds}, next, nil
+	}
+
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return pathStep{}, 0, fmt.Errorf("invalid selector [%s]", inner)
+	}
+	return pathStep{index: &index}, next, nil
+}
+
+func closingBracket(text string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseFilter parses "(@.path op literal)" or "(@.path)".
+func parseFilter(text string) (*pathFilter, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "(") || !strings.HasSuffix(text, ")") {
+		return nil, fmt.Errorf("filter must look like [?(@.field == 'value')]")
+	}
+	text = strings.TrimSpace(text[1 : len(text)-1])
+
+	left, operator, right := text, "", ""
+	if at, candidate := filterOperator(text); at >= 0 {
+		left, operator, right = strings.TrimSpace(text[:at]), candidate, strings.TrimSpace(text[at+len(candidate):])
+	}
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("filter must start with @")
+	}
+	path, err := parsePathSteps(strings.TrimPrefix(left, "@"))
+	if err != nil {
+		return nil, err
+	}
+	filter := &pathFilter{path: path, operator: operator}
+	if operator == "" {
+		return filter, nil
+	}
+
+	switch {
+	case len(right) >= 2 && (right[0] == '\'' || right[0] == '"') && right[len(right)-1] == right[0]:
+		filter.literal = right[1 : len(right)-1]
+	case right == "true", right == "false":
+		filter.literal = right == "true"
+	case right == "null":
+		filter.literal = nil
+	default:
+		number, err := strconv.ParseFloat(right, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter value %q", right)
+		}
+		filter.literal = number
+	}
+	return filter, nil
+}
+
+// filterOperator returns the offset and text of the first operator outside
+// quoted text, or -1 if the filter only tests for a value.
+func filterOperator(text string) (int, string) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		default:
+			for _, candidate := range filterOperators {
+				if strings.HasPrefix(text[i:], candidate) {
+					return i, candidate
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+func (p *jsonPath) evaluate(root any) []any {
+	nodes, _ := p.walk(root, false)
+	return nodes
+}
+
+// find evaluates the path like kubectl does: a field or index that matches
+// nothing is an error, while wildcards, slices and filters may match nothing.
+func (p *jsonPath) find(root any) ([]any, error) {
+	return p.walk(root, true)
+}
+
+func (p *jsonPath) walk(root any, strict bool) ([]any, error) {
+	nodes := []any{root}
+	for _, step := range p.steps {
+		next := []any{}
+		for _, node := range nodes {
+			candidates := []any{node}
+			if step.recursive {
+				candidates = descendants(node, nil)
+			}
+			for _, candidate := range candidates {
+				next = append(next, step.apply(candidate)...)
+			}
+		}
+		if strict && len(nodes) > 0 && len(next) == 0 && !step.recursive {
+			switch {
+			case step.isField:
+				return nil, fmt.Errorf("%s is not found", step.field)
+			case step.index != nil:
+				return nil, fmt.Errorf("array index [%d] is out of bounds", *step.index)
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+func (s pathStep) apply(node any) []any {
+	switch {
+	case s.isField:
+		if object, ok := node.(map[string]any); ok {
+			if value, ok := object[s.field]; ok {
+				return []any{value}
+			}
+		}
+	case s.wildcard:
+		return children(node)
+	case s.index != nil:
+		if array, ok := node.([]any); ok {
+			index := *s.index
+			if index < 0 {
+				index += len(array)
+			}
+			if index >= 0 && index < len(array) {
+				return []any{array[index]}
+			}
+		}
+	case s.slice != nil:
+		if array, ok := node.([]any); ok {
+			from, to := 0, len(array)
+			if s.slice[0] != nil {
+				from = clampIndex(*s.slice[0], len(array))
+			}
+			if s.slice[1] != nil {
+				to = clampIndex(*s.slice[1], len(array))
+			}
+			if from < to {
+				return array[from:to]
+			}
+		}
+	case s.filter != nil:
+		matches := []any{}
+		for _, child := range children(node) {
+			if s.filter.matches(child) {
+				matches = append(matches, child)
+			}
+		}
+		return matches
+	}
+	return nil
+}
+
+func clampIndex(index int, length int) int {
+	if index < 0 {
+		index += length
+	}
+	return max(0, min(index, length))
+}
+
+// children returns array elements, or object values in key order.
+func children(node any) []any {
+	switch typed := node.(type) {
+	case []any:
+		return typed
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		values := make([]any, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, typed[key])
+		}
+		return values
+	}
+	return nil
+}
+
+func descendants(node any, out []any) []any {
+	out = append(out, node)
+	for _, child := range children(node) {
+		out = descendants(child, out)
+	}
+	return out
+}
+
+func (f *pathFilter) matches(node any) bool {
+	values := f.path.evaluate(node)
+	if f.operator == "" {
+		return len(values) > 0 && values[0] != nil && values[0] != false
+	}
+	if len(values) == 0 {
+		return f.operator == "!="
+	}
+	return compareFilter(values[0], f.operator, f.literal)
+}
+
+func compareFilter(value any, operator string, literal any) bool {
+	if number, ok := literal.(float64); ok {
+		actual, ok := numberValue(value)
+		if !ok {
+			return operator == "!="
+		}
+		switch operator {
+		case "==":
+			return actual == number
+		case "!=":
+			return actual != number
+		case "<":
+			return actual < number
+		case "<=":
+			return actual <= number
+		case ">":
+			return actual > number
+		case ">=":
+			return actual >= number
+		}
+		return false
+	}
+
+	if text, ok := literal.(string); ok {
+		actual, ok := value.(string)
+		if !ok {
+			return operator == "!="
+		}
+		switch operator {
+		case "==":
+			return actual == text
+		case "!=":
+			return actual != text
+		case "<":
+			return actual < text
+		case "<=":
+			return actual <= text
+		case ">":
+			return actual > text
+		case ">=":
+			return actual >= text
+		}
+		return false
+	}
+
+	switch operator {
+	case "==":
+		return value == literal
+	case "!=":
+		return value != literal
+	}
+	return false
+}
+
+func numberValue(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		number, err := typed.Float64()
+		return number, err == nil
+	case float64:
+		return typed, true
+	}
+	return 0, false
+}
@@ -14,8 +14,14 @@ import (
 type Format string
 
 const (
-	FormatTable Format = "table"
-	FormatJSON  Format = "json"
+	FormatTable      Format = "table"
+	FormatWide       Format = "wide"
+	FormatJSON       Format = "json"
+	FormatYAML       Format = "yaml"
+	FormatCSV        Format = "csv"
+	FormatTSV        Format = "tsv"
+	FormatJSONPath   Format = "jsonpath"
+	FormatGoTemplate Format = "go-template"
 )
 
 func PrintJSON(v any) error {
@@ -40,7 +46,7 @@ func BoolLabel(value bool) string {
 }
 
 func PrintPagination(meta api.Pagination) error {
-	if meta.TotalPages <= 1 {
+	if meta.TotalPages <= 1 || current.Format.Delimited() {
 		return nil
 	}
 
@@ -80,6 +86,46 @@ func PrintError(err error) {
 	fmt.Fprintf(os.Stderr, "ERROR %s\n", err.Error())
 }
 
+type ErrorEnvelope struct {
+	Ok       bool        `json:"ok"`
+	ExitCode int         `json:"exitCode"`
+	Error    ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Class      string `json:"class"`
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Details    any    `json:"details,omitempty"`
+}
+
+// PrintErrorJSON writes err to stderr as a single JSON document so automation
+// using --output json can parse failures as well as results.
+func PrintErrorJSON(err error, class string, exitCode int) {
+	if err == nil {
+		return
+	}
+
+	envelope := ErrorEnvelope{
+		ExitCode: exitCode,
+		Error:    ErrorDetail{Class: class, Message: err.Error()},
+	}
+	if apiErr := api.AsAPIError(err); apiErr != nil {
+		envelope.Error.Code = apiErr.Code
+		envelope.Error.Message = apiErr.Message
+		envelope.Error.StatusCode = apiErr.StatusCode
+		envelope.Error.Details = apiErr.Details
+	}
+
+	encoder := json.NewEncoder(os.Stderr)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if encodeErr := encoder.Encode(envelope); encodeErr != nil {
+		PrintError(err)
+	}
+}
+
 var noColorOverride bool
 
 func SetNoColor(value bool) {
@@ -105,8 +151,25 @@ func ColorStatus(status string) string {
 	}
 }
 
+func ColorDiff(kind string, text string) string {
+	if !colorEnabled() {
+		return text
+	}
+
+	switch kind {
+	case "added":
+		return colorize(text, "\x1b[32m")
+	case "removed":
+		return colorize(text, "\x1b[31m")
+	case "changed":
+		return colorize(text, "\x1b[33m")
+	default:
+		return text
+	}
+}
+
 func colorEnabled() bool {
-	if noColorOverride || os.Getenv("NO_COLOR") != "" {
+	if noColorOverride || os.Getenv("NO_COLOR") != "" || current.Format.Delimited() {
 		return false
 	}
 	return term.IsTerminal(int(os.Stdout.Fd()))
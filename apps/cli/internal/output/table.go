@@ -1,17 +1,31 @@
 package output
 
 import (
+	"encoding/csv"
 	"fmt"
+	"os"
 )
 
 func PrintListTable(headers []string, rows [][]string) error {
+	return PrintListRows(headers, rows, true)
+}
+
+// PrintListRows prints rows in the current table format, with the header line
+// only when header is true, so lists can be streamed a page at a time.
+func PrintListRows(headers []string, rows [][]string, header bool) error {
+	if current.Format.Delimited() {
+		return printDelimited(headers, rows, header)
+	}
+
 	w := NewTableWriter()
 
-	for i, header := range headers {
-		if i == len(headers)-1 {
-			fmt.Fprintln(w, header)
-		} else {
-			fmt.Fprint(w, header+"\t")
+	if header {
+		for i, header := range headers {
+			if i == len(headers)-1 {
+				fmt.Fprintln(w, header)
+			} else {
+				fmt.Fprint(w, header+"\t")
+			}
 		}
 	}
 
@@ -29,6 +43,14 @@ func PrintListTable(headers []string, rows [][]string) error {
 }
 
 func PrintKVTable(pairs [][2]string) error {
+	if current.Format.Delimited() {
+		rows := make([][]string, 0, len(pairs))
+		for _, pair := range pairs {
+			rows = append(rows, []string{pair[0], pair[1]})
+		}
+		return printDelimited([]string{"FIELD", "VALUE"}, rows, true)
+	}
+
 	w := NewTableWriter()
 	fmt.Fprintln(w, "FIELD\tVALUE")
 	for _, pair := range pairs {
@@ -36,3 +58,19 @@ func PrintKVTable(pairs [][2]string) error {
 	}
 	return w.Flush()
 }
+
+func printDelimited(headers []string, rows [][]string, header bool) error {
+	w := csv.NewWriter(os.Stdout)
+	if current.Format == FormatTSV {
+		w.Comma = '\t'
+	}
+	if header {
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
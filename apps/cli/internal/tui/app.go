@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"context"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gentij/lunie/apps/cli/internal/api"
 	"github.com/gentij/lunie/apps/cli/internal/config"
@@ -19,9 +21,9 @@ func NewApp(client *api.Client, serverURL string, tokenSet bool, cfg config.Conf
 	return &App{client: client, serverURL: serverURL, tokenSet: tokenSet, config: cfg, configPath: configPath}
 }
 
-func (a *App) Start() error {
-	model := app.NewModel(a.client, a.serverURL, a.tokenSet, a.config, a.configPath)
-	program := tea.NewProgram(model, tea.WithAltScreen())
+func (a *App) Start(ctx context.Context) error {
+	model := app.NewModel(ctx, a.client, a.serverURL, a.tokenSet, a.config, a.configPath)
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx))
 	_, err := program.Run()
 	return err
 }
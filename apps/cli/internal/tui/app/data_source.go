@@ -1,9 +1,12 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,7 +14,7 @@ import (
 	"github.com/gentij/lunie/apps/cli/internal/tui/data"
 )
 
-const apiPageSize = 100
+const apiPageSize = api.MaxPageSize
 
 type apiListSort struct {
 	By    string
@@ -67,8 +70,48 @@ func normalizedSnapshotSortOptions(input snapshotSortOptions) snapshotSortOption
 	}
 }
 
+// loaderWorkers bounds the API requests that snapshot loads and lazy loads
+// make at the same time.
+const loaderWorkers = 4
+
+type resourceKind int
+
+const (
+	resourceWorkflows resourceKind = iota
+	resourceSecrets
+	resourceVersions
+	resourceTriggers
+	resourceRuns
+	resourceEvents
+	resourceStepRuns
+	resourceTokens
+)
+
+// loadJob lists one resource: workflows, secrets or API tokens, the versions,
+// triggers or runs of a workflow, the events of a trigger, or the step runs
+// of a run.
+type loadJob struct {
+	Kind       resourceKind
+	WorkflowID string
+	ParentID   string
+}
+
+// resourceLoadedMsg delivers one finished job. Snapshot loads number their
+// messages with load and chain the next receive through next; lazy loads
+// have load 0.
+type resourceLoadedMsg struct {
+	load  int
+	job   loadJob
+	store data.Store
+	err   error
+	done  int
+	total int
+	next  tea.Cmd
+}
+
+// snapshotLoadedMsg reports that every job of a snapshot load has finished.
 type snapshotLoadedMsg struct {
-	store     data.Store
+	load      int
 	apiStatus string
 	err       error
 }
@@ -77,346 +120,606 @@ type mutationResultMsg struct {
 	successMessage string
 	err            error
 	refresh        bool
+	createdToken   *api.CreatedAPIToken
 }
 
-func fetchSnapshotCmd(client *api.Client, sortOptions snapshotSortOptions, delay time.Duration, fail bool) tea.Cmd {
+// fetchSnapshotCmd starts a snapshot load. Workflows and secrets are listed
+// first; each workflow then queues its versions, triggers and runs. Events
+// and step runs are left to lazy loads.
+func fetchSnapshotCmd(ctx context.Context, client *api.Client, slots chan struct{}, load int, sortOptions snapshotSortOptions, delay time.Duration, flaky bool) tea.Cmd {
 	return func() tea.Msg {
 		if delay > 0 {
-			time.Sleep(delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return snapshotLoadedMsg{load: load, err: ctx.Err()}
+			}
 		}
-		if fail {
-			return snapshotLoadedMsg{err: fmt.Errorf("simulated flaky network")}
+		if client == nil {
+			return snapshotLoadedMsg{load: load, err: fmt.Errorf("api client is not configured"), apiStatus: "OFFLINE"}
+		}
+		if flaky {
+			client = flakyClient(client)
+		}
+		loader := &snapshotLoader{
+			id:      load,
+			ctx:     ctx,
+			client:  client,
+			slots:   slots,
+			sort:    normalizedSnapshotSortOptions(sortOptions),
+			results: make(chan resourceLoadedMsg),
+		}
+		loader.submit(loadJob{Kind: resourceWorkflows}, loadJob{Kind: resourceSecrets})
+		go func() {
+			loader.wg.Wait()
+			close(loader.results)
+		}()
+		return loader.next()
+	}
+}
+
+// fetchResourceCmd runs a single lazy load job.
+func fetchResourceCmd(ctx context.Context, client *api.Client, slots chan struct{}, sortOptions snapshotSortOptions, job loadJob) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil {
+			return resourceLoadedMsg{job: job, err: fmt.Errorf("api client is not configured")}
 		}
-		store, err := loadStoreFromAPI(client, normalizedSnapshotSortOptions(sortOptions))
-		if err != nil {
-			return snapshotLoadedMsg{err: err, apiStatus: "OFFLINE"}
+		store, err := fetchResource(ctx, client, slots, normalizedSnapshotSortOptions(sortOptions), job)
+		return resourceLoadedMsg{job: job, store: store, err: err}
+	}
+}
+
+type snapshotLoader struct {
+	id      int
+	ctx     context.Context
+	client  *api.Client
+	slots   chan struct{}
+	sort    snapshotSortOptions
+	results chan resourceLoadedMsg
+	wg      sync.WaitGroup
+	total   atomic.Int32
+	done    atomic.Int32
+
+	mu  sync.Mutex
+	err error
+}
+
+// reserve counts jobs before they start, so the load cannot look finished
+// while a parent is still handing its children over.
+func (l *snapshotLoader) reserve(count int) {
+	l.wg.Add(count)
+	l.total.Add(int32(count))
+}
+
+func (l *snapshotLoader) submit(jobs ...loadJob) {
+	l.reserve(len(jobs))
+	for _, job := range jobs {
+		go l.run(job)
+	}
+}
+
+// run fetches one job and hands the result to the model. A workflows job
+// queues its children only after its own message is received, so the model
+// always knows a workflow before its versions, triggers and runs arrive.
+func (l *snapshotLoader) run(job loadJob) {
+	defer l.wg.Done()
+	store, err := fetchResource(l.ctx, l.client, l.slots, l.sort, job)
+	if err != nil {
+		l.fail(err)
+	}
+
+	children := []loadJob{}
+	if err == nil && job.Kind == resourceWorkflows {
+		for _, wf := range store.Workflows {
+			children = append(children,
+				loadJob{Kind: resourceVersions, WorkflowID: wf.ID},
+				loadJob{Kind: resourceTriggers, WorkflowID: wf.ID},
+				loadJob{Kind: resourceRuns, WorkflowID: wf.ID},
+			)
 		}
-		return snapshotLoadedMsg{store: store, apiStatus: "CONNECTED"}
+		l.reserve(len(children))
+	}
+
+	msg := resourceLoadedMsg{
+		load:  l.id,
+		job:   job,
+		store: store,
+		err:   err,
+		done:  int(l.done.Add(1)),
+		total: int(l.total.Load()),
+	}
+	select {
+	case l.results <- msg:
+	case <-l.ctx.Done():
+	}
+
+	for _, child := range children {
+		go l.run(child)
 	}
 }
 
-func loadStoreFromAPI(client *api.Client, sortOptions snapshotSortOptions) (data.Store, error) {
-	if client == nil {
-		return data.Store{}, fmt.Errorf("api client is not configured")
+func (l *snapshotLoader) fail(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = err
 	}
+}
 
-	apiWorkflows, err := listAllWorkflows(client, sortOptions.Workflows)
+// next waits for the next finished job, or reports the end of the load once
+// all jobs are done.
+func (l *snapshotLoader) next() tea.Msg {
+	msg, ok := <-l.results
+	if ok {
+		msg.next = l.next
+		return msg
+	}
+	if err := l.ctx.Err(); err != nil {
+		return snapshotLoadedMsg{load: l.id, err: err}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return snapshotLoadedMsg{load: l.id, err: l.err, apiStatus: "OFFLINE"}
+	}
+	return snapshotLoadedMsg{load: l.id, apiStatus: "CONNECTED"}
+}
+
+// fetchResource lists every page of one job while holding a worker slot and
+// converts the result into a partial store.
+func fetchResource(ctx context.Context, client *api.Client, slots chan struct{}, sortOptions snapshotSortOptions, job loadJob) (data.Store, error) {
+	release, err := acquireSlot(ctx, slots)
 	if err != nil {
 		return data.Store{}, err
 	}
+	defer release()
 
-	versionsByWorkflow := map[string][]api.WorkflowVersion{}
-	triggersByWorkflow := map[string][]api.Trigger{}
-	runsByWorkflow := map[string][]api.WorkflowRun{}
-	eventsByTrigger := map[string][]api.Event{}
-	stepsByRun := map[string][]api.StepRun{}
-
-	for _, wf := range apiWorkflows {
-		versions, err := listAllWorkflowVersions(client, wf.ID, sortOptions.WorkflowVersions)
+	store := data.Store{}
+	switch job.Kind {
+	case resourceWorkflows:
+		items, err := listAllWorkflows(ctx, client, sortOptions.Workflows)
 		if err != nil {
-			return data.Store{}, err
+			return store, err
 		}
-		versionsByWorkflow[wf.ID] = versions
-
-		triggers, err := listAllTriggers(client, wf.ID, sortOptions.Triggers)
+		store.Workflows = convertWorkflows(items)
+	case resourceSecrets:
+		items, err := listAllSecrets(ctx, client, sortOptions.Secrets)
 		if err != nil {
-			return data.Store{}, err
+			return store, err
 		}
-		triggersByWorkflow[wf.ID] = triggers
-
-		runs, err := listAllWorkflowRuns(client, wf.ID, sortOptions.Runs)
+		store.Secrets = convertSecrets(items)
+	case resourceVersions:
+		items, err := listAllWorkflowVersions(ctx, client, job.WorkflowID, sortOptions.WorkflowVersions)
 		if err != nil {
-			return data.Store{}, err
+			return store, err
 		}
-		runsByWorkflow[wf.ID] = runs
-
-		for _, trigger := range triggers {
-			events, err := listAllEvents(client, wf.ID, trigger.ID, sortOptions.Events)
-			if err != nil {
-				return data.Store{}, err
-			}
-			eventsByTrigger[trigger.ID] = events
+		store.WorkflowVersions = convertVersions(items)
+	case resourceTriggers:
+		items, err := listAllTriggers(ctx, client, job.WorkflowID, sortOptions.Triggers)
+		if err != nil {
+			return store, err
 		}
-
-		for _, run := range runs {
-			steps, err := listAllStepRuns(client, wf.ID, run.ID, sortOptions.StepRuns)
-			if err != nil {
-				return data.Store{}, err
-			}
-			stepsByRun[run.ID] = steps
+		store.Triggers = convertTriggers(items)
+	case resourceRuns:
+		items, err := listAllWorkflowRuns(ctx, client, job.WorkflowID, sortOptions.Runs)
+		if err != nil {
+			return store, err
+		}
+		store.Runs = convertRuns(items)
+	case resourceEvents:
+		items, err := listAllEvents(ctx, client, job.WorkflowID, job.ParentID, sortOptions.Events)
+		if err != nil {
+			return store, err
+		}
+		store.Events = convertEvents(items)
+	case resourceStepRuns:
+		items, err := listAllStepRuns(ctx, client, job.WorkflowID, job.ParentID, sortOptions.StepRuns)
+		if err != nil {
+			return store, err
 		}
+		store.StepRuns = convertStepRuns(items)
+	case resourceTokens:
+		items, err := listAllAPITokens(ctx, client)
+		if err != nil {
+			return store, err
+		}
+		store.ApiTokens = convertTokens(items)
 	}
+	return store, nil
+}
 
-	secrets, err := listAllSecrets(client, sortOptions.Secrets)
-	if err != nil {
-		return data.Store{}, err
+func acquireSlot(ctx context.Context, slots chan struct{}) (func(), error) {
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
+}
 
-	store := data.Store{}
-	triggerTypeByID := map[string]string{}
-	runByEventID := map[string]string{}
-	runIndexByID := map[string]int{}
-
-	for _, wf := range apiWorkflows {
-		versions := versionsByWorkflow[wf.ID]
-		latestVersion := 0
-		for _, version := range versions {
-			if version.Version > latestVersion {
-				latestVersion = version.Version
-			}
-			store.WorkflowVersions = append(store.WorkflowVersions, data.WorkflowVersion{
-				ID:             version.ID,
-				WorkflowID:     version.WorkflowID,
-				Version:        version.Version,
-				CreatedAt:      parseTime(version.CreatedAt),
-				DefinitionJSON: stringifyJSON(version.Definition, "{}"),
-			})
-		}
+// deltaPageSize keeps a delta refresh to one small request per list when
+// little has changed.
+const deltaPageSize = 25
 
-		for _, trigger := range triggersByWorkflow[wf.ID] {
-			name := "(unnamed)"
-			if trigger.Name != nil && strings.TrimSpace(*trigger.Name) != "" {
-				name = *trigger.Name
-			}
-			triggerTypeByID[trigger.ID] = trigger.Type
-			store.Triggers = append(store.Triggers, data.Trigger{
-				ID:         trigger.ID,
-				WorkflowID: trigger.WorkflowID,
-				Key:        trigger.Key,
-				Type:       strings.ToLower(trigger.Type),
-				Name:       name,
-				Active:     trigger.IsActive,
-				CreatedAt:  parseTime(trigger.CreatedAt),
-				ConfigJSON: stringifyJSON(trigger.Config, "{}"),
-			})
-		}
+// deltaLoadedMsg carries the entities a delta refresh found changed.
+type deltaLoadedMsg struct {
+	load  int
+	delta data.Delta
+	err   error
+}
 
-		for _, run := range runsByWorkflow[wf.ID] {
-			if run.EventID != nil && strings.TrimSpace(*run.EventID) != "" {
-				runByEventID[*run.EventID] = run.ID
-			}
-			triggerType := "manual"
-			if run.TriggerID != nil {
-				if t, ok := triggerTypeByID[*run.TriggerID]; ok {
-					triggerType = strings.ToLower(t)
-				}
-			}
-			startedAt := parseNullableTime(run.StartedAt)
-			if startedAt.IsZero() {
-				startedAt = parseTime(run.CreatedAt)
+// fetchDeltaCmd fetches only what changed since index was taken: workflows,
+// secrets, and the triggers and runs of every workflow, each listed by
+// updatedAt desc until a page has no changes. Workflows that changed reload
+// their versions. Deletions are left to the next snapshot load.
+func fetchDeltaCmd(ctx context.Context, client *api.Client, slots chan struct{}, load int, index data.Index, delay time.Duration, flaky bool) tea.Cmd {
+	return func() tea.Msg {
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return deltaLoadedMsg{load: load, err: ctx.Err()}
 			}
-			store.Runs = append(store.Runs, data.WorkflowRun{
-				ID:          run.ID,
-				WorkflowID:  run.WorkflowID,
-				Number:      run.Number,
-				Status:      run.Status,
-				TriggerType: triggerType,
-				StartedAt:   startedAt,
-				Duration:    durationFromTimes(run.StartedAt, run.FinishedAt),
-				InputJSON:   stringifyJSON(run.Input, "{}"),
-				OutputJSON:  stringifyJSON(run.Output, "{}"),
-				ErrorJSON:   "",
-			})
-			runIndexByID[run.ID] = len(store.Runs) - 1
 		}
+		if client == nil {
+			return deltaLoadedMsg{load: load, err: fmt.Errorf("api client is not configured")}
+		}
+		if flaky {
+			client = flakyClient(client)
+		}
+		delta, err := loadDelta(ctx, client, slots, index)
+		return deltaLoadedMsg{load: load, delta: delta, err: err}
+	}
+}
 
-		for _, trigger := range triggersByWorkflow[wf.ID] {
-			for _, event := range eventsByTrigger[trigger.ID] {
-				var runID *string
-				if linkedRunID, ok := runByEventID[event.ID]; ok {
-					rid := linkedRunID
-					runID = &rid
-				}
-				eventType := "event"
-				if event.Type != nil && strings.TrimSpace(*event.Type) != "" {
-					eventType = strings.ToLower(*event.Type)
-				}
-				metadata := map[string]any{}
-				if event.ExternalID != nil && strings.TrimSpace(*event.ExternalID) != "" {
-					metadata["externalId"] = *event.ExternalID
-				}
-				store.Events = append(store.Events, data.Event{
-					ID:          event.ID,
-					TriggerID:   event.TriggerID,
-					Type:        eventType,
-					ReceivedAt:  parseTime(event.ReceivedAt),
-					RunID:       runID,
-					PayloadJSON: stringifyJSON(event.Payload, "{}"),
-					Metadata:    stringifyJSON(metadata, "{}"),
-				})
-			}
+func loadDelta(ctx context.Context, client *api.Client, slots chan struct{}, index data.Index) (data.Delta, error) {
+	delta := data.Delta{WorkflowVersions: map[string][]data.WorkflowVersion{}}
+
+	release, err := acquireSlot(ctx, slots)
+	if err != nil {
+		return delta, err
+	}
+	workflows, err := listChanged(ctx, func(ctx context.Context, page int) (api.Paginated[api.Workflow], error) {
+		return client.ListWorkflows(ctx, page, deltaPageSize, "updatedAt", "desc")
+	}, func(wf api.Workflow) bool {
+		return index.Changed(data.EntityWorkflow, wf.ID, parseTime(wf.UpdatedAt))
+	})
+	release()
+	if err != nil {
+		return delta, err
+	}
+	delta.Workflows = convertWorkflows(workflows)
+
+	workflowIDs := []string{}
+	for id := range index[data.EntityWorkflow] {
+		workflowIDs = append(workflowIDs, id)
+	}
+	for _, wf := range workflows {
+		if !index.Known(data.EntityWorkflow, wf.ID) {
+			workflowIDs = append(workflowIDs, wf.ID)
 		}
 	}
 
-	for runID, steps := range stepsByRun {
-		for _, step := range steps {
-			store.StepRuns = append(store.StepRuns, data.StepRun{
-				ID:        step.ID,
-				RunID:     step.WorkflowRunID,
-				StepKey:   step.StepKey,
-				Status:    step.Status,
-				StartedAt: parseNullableOrCreated(step.StartedAt, step.CreatedAt),
-				Duration:  durationFromStep(step),
-				Log:       stringifyLog(step.Logs),
-			})
-			if step.Status == "FAILED" && step.Error != nil {
-				if idx, ok := runIndexByID[runID]; ok && strings.TrimSpace(store.Runs[idx].ErrorJSON) == "" {
-					store.Runs[idx].ErrorJSON = stringifyJSON(step.Error, "{}")
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	spawn := func(fetch func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := acquireSlot(ctx, slots)
+			if err == nil {
+				err = fetch()
+				release()
+			}
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
 				}
+				mu.Unlock()
 			}
-		}
+		}()
 	}
 
-	for _, secret := range secrets {
-		description := ""
-		if secret.Description != nil {
-			description = *secret.Description
-		}
-		store.Secrets = append(store.Secrets, data.Secret{
-			ID:          secret.ID,
-			Name:        secret.Name,
-			Description: description,
-			CreatedAt:   parseTime(secret.CreatedAt),
+	spawn(func() error {
+		secrets, err := listChanged(ctx, func(ctx context.Context, page int) (api.Paginated[api.Secret], error) {
+			return client.ListSecrets(ctx, page, deltaPageSize, "updatedAt", "desc")
+		}, func(secret api.Secret) bool {
+			return index.Changed(data.EntitySecret, secret.ID, parseTime(secret.UpdatedAt))
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		delta.Secrets = convertSecrets(secrets)
+		return err
+	})
+	for _, workflowID := range workflowIDs {
+		spawn(func() error {
+			triggers, err := listChanged(ctx, func(ctx context.Context, page int) (api.Paginated[api.Trigger], error) {
+				return client.ListTriggers(ctx, workflowID, page, deltaPageSize, "updatedAt", "desc")
+			}, func(trigger api.Trigger) bool {
+				return index.Changed(data.EntityTrigger, trigger.ID, parseTime(trigger.UpdatedAt))
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			delta.Triggers = append(delta.Triggers, convertTriggers(triggers)...)
+			return err
+		})
+		spawn(func() error {
+			runs, err := listChanged(ctx, func(ctx context.Context, page int) (api.Paginated[api.WorkflowRun], error) {
+				return client.ListWorkflowRuns(ctx, workflowID, page, deltaPageSize, "updatedAt", "desc")
+			}, func(run api.WorkflowRun) bool {
+				return index.Changed(data.EntityRun, run.ID, parseTime(run.UpdatedAt))
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			delta.Runs = append(delta.Runs, convertRuns(runs)...)
+			return err
 		})
 	}
-
-	for _, wf := range apiWorkflows {
-		latestVersion := 0
-		for _, version := range versionsByWorkflow[wf.ID] {
-			if version.Version > latestVersion {
-				latestVersion = version.Version
+	for _, wf := range workflows {
+		spawn(func() error {
+			versions, err := listNewVersions(ctx, client, wf.ID, index)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil && len(versions) > 0 {
+				delta.WorkflowVersions[wf.ID] = convertVersions(versions)
 			}
-		}
-		store.Workflows = append(store.Workflows, data.Workflow{
-			ID:            wf.ID,
-			Key:           wf.Key,
-			Name:          wf.Name,
-			Active:        wf.IsActive,
-			LatestVersion: latestVersion,
-			UpdatedAt:     parseTime(wf.UpdatedAt),
+			return err
 		})
 	}
-
-	return store, nil
+	wg.Wait()
+	return delta, firstErr
 }
 
-func listAllWorkflows(client *api.Client, sortSpec apiListSort) ([]api.Workflow, error) {
-	items := make([]api.Workflow, 0)
-	page := 1
-	for {
-		result, err := client.ListWorkflows(page, apiPageSize, sortSpec.By, sortSpec.Order)
+// listChanged walks a list sorted by updatedAt desc and keeps the items that
+// changed. It stops at the first page where nothing did, since every later
+// page holds older updates.
+func listChanged[T any](ctx context.Context, fetch api.PageFunc[T], changed func(T) bool) ([]T, error) {
+	items := []T{}
+	for result, err := range api.Pages(ctx, fetch) {
 		if err != nil {
 			return nil, err
 		}
-		items = append(items, result.Items...)
-		if !result.Pagination.HasNext {
+		fresh := 0
+		for _, item := range result.Items {
+			if changed(item) {
+				items = append(items, item)
+				fresh++
+			}
+		}
+		if fresh == 0 {
 			break
 		}
-		page++
 	}
 	return items, nil
 }
 
-func listAllWorkflowVersions(client *api.Client, workflowID string, sortSpec apiListSort) ([]api.WorkflowVersion, error) {
-	items := make([]api.WorkflowVersion, 0)
-	page := 1
-	for {
-		result, err := client.ListWorkflowVersions(workflowID, page, apiPageSize, sortSpec.By, sortSpec.Order)
+// listNewVersions walks the versions of a workflow from the newest and stops
+// at the first one the store already holds.
+func listNewVersions(ctx context.Context, client *api.Client, workflowID string, index data.Index) ([]api.WorkflowVersion, error) {
+	versions := []api.WorkflowVersion{}
+	for version, err := range api.Paginate(ctx, func(ctx context.Context, page int) (api.Paginated[api.WorkflowVersion], error) {
+		return client.ListWorkflowVersions(ctx, workflowID, page, deltaPageSize, "version", "desc")
+	}) {
 		if err != nil {
 			return nil, err
 		}
-		items = append(items, result.Items...)
-		if !result.Pagination.HasNext {
+		if index.Known(data.EntityWorkflowVersion, version.ID) {
 			break
 		}
-		page++
+		versions = append(versions, version)
 	}
-	return items, nil
+	return versions, nil
 }
 
-func listAllTriggers(client *api.Client, workflowID string, sortSpec apiListSort) ([]api.Trigger, error) {
-	items := make([]api.Trigger, 0)
-	page := 1
-	for {
-		result, err := client.ListTriggers(workflowID, page, apiPageSize, sortSpec.By, sortSpec.Order)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, result.Items...)
-		if !result.Pagination.HasNext {
-			break
-		}
-		page++
+func convertWorkflows(items []api.Workflow) []data.Workflow {
+	workflows := make([]data.Workflow, 0, len(items))
+	for _, wf := range items {
+		workflows = append(workflows, data.Workflow{
+			ID:        wf.ID,
+			Key:       wf.Key,
+			Name:      wf.Name,
+			Active:    wf.IsActive,
+			UpdatedAt: parseTime(wf.UpdatedAt),
+		})
 	}
-	return items, nil
+	return workflows
 }
 
-func listAllWorkflowRuns(client *api.Client, workflowID string, sortSpec apiListSort) ([]api.WorkflowRun, error) {
-	items := make([]api.WorkflowRun, 0)
-	page := 1
-	for {
-		result, err := client.ListWorkflowRuns(workflowID, page, apiPageSize, sortSpec.By, sortSpec.Order)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, result.Items...)
-		if !result.Pagination.HasNext {
-			break
-		}
-		page++
+func convertVersions(items []api.WorkflowVersion) []data.WorkflowVersion {
+	versions := make([]data.WorkflowVersion, 0, len(items))
+	for _, version := range items {
+		versions = append(versions, data.WorkflowVersion{
+			ID:             version.ID,
+			WorkflowID:     version.WorkflowID,
+			Version:        version.Version,
+			CreatedAt:      parseTime(version.CreatedAt),
+			DefinitionJSON: stringifyJSON(version.Definition, "{}"),
+		})
 	}
-	return items, nil
+	return versions
 }
 
-func listAllEvents(client *api.Client, workflowID string, triggerID string, sortSpec apiListSort) ([]api.Event, error) {
-	items := make([]api.Event, 0)
-	page := 1
-	for {
-		result, err := client.ListEvents(workflowID, triggerID, page, apiPageSize, sortSpec.By, sortSpec.Order)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, result.Items...)
-		if !result.Pagination.HasNext {
-			break
-		}
-		page++
+func convertTriggers(items []api.Trigger) []data.Trigger {
+	triggers := make([]data.Trigger, 0, len(items))
+	for _, trigger := range items {
+		name := "(unnamed)"
+		if trigger.Name != nil && strings.TrimSpace(*trigger.Name) != "" {
+			name = *trigger.Name
+		}
+		triggers = append(triggers, data.Trigger{
+			ID:         trigger.ID,
+			WorkflowID: trigger.WorkflowID,
+			Key:        trigger.Key,
+			Type:       strings.ToLower(trigger.Type),
+			Name:       name,
+			Active:     trigger.IsActive,
+			CreatedAt:  parseTime(trigger.CreatedAt),
+			UpdatedAt:  parseTime(trigger.UpdatedAt),
+			ConfigJSON: stringifyJSON(trigger.Config, "{}"),
+		})
 	}
-	return items, nil
+	return triggers
 }
 
-func listAllStepRuns(client *api.Client, workflowID string, runID string, sortSpec apiListSort) ([]api.StepRun, error) {
-	items := make([]api.StepRun, 0)
-	page := 1
-	for {
-		result, err := client.ListStepRuns(workflowID, runID, page, apiPageSize, sortSpec.By, sortSpec.Order)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, result.Items...)
-		if !result.Pagination.HasNext {
-			break
-		}
-		page++
+// convertRuns leaves TriggerType as manual; linkStore resolves it once the
+// run's trigger is known.
+func convertRuns(items []api.WorkflowRun) []data.WorkflowRun {
+	runs := make([]data.WorkflowRun, 0, len(items))
+	for _, run := range items {
+		startedAt := parseNullableTime(run.StartedAt)
+		if startedAt.IsZero() {
+			startedAt = parseTime(run.CreatedAt)
+		}
+		runs = append(runs, data.WorkflowRun{
+			ID:          run.ID,
+			WorkflowID:  run.WorkflowID,
+			Number:      run.Number,
+			Status:      run.Status,
+			TriggerID:   stringValue(run.TriggerID),
+			EventID:     stringValue(run.EventID),
+			TriggerType: "manual",
+			StartedAt:   startedAt,
+			Duration:    durationFromTimes(run.StartedAt, run.FinishedAt),
+			InputJSON:   stringifyJSON(run.Input, "{}"),
+			OutputJSON:  stringifyJSON(run.Output, "{}"),
+			UpdatedAt:   parseTime(run.UpdatedAt),
+		})
 	}
-	return items, nil
+	return runs
 }
 
-func listAllSecrets(client *api.Client, sortSpec apiListSort) ([]api.Secret, error) {
-	items := make([]api.Secret, 0)
-	page := 1
-	for {
-		result, err := client.ListSecrets(page, apiPageSize, sortSpec.By, sortSpec.Order)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, result.Items...)
-		if !result.Pagination.HasNext {
-			break
+func convertEvents(items []api.Event) []data.Event {
+	events := make([]data.Event, 0, len(items))
+	for _, event := range items {
+		eventType := "event"
+		if event.Type != nil && strings.TrimSpace(*event.Type) != "" {
+			eventType = strings.ToLower(*event.Type)
+		}
+		metadata := map[string]any{}
+		if event.ExternalID != nil && strings.TrimSpace(*event.ExternalID) != "" {
+			metadata["externalId"] = *event.ExternalID
+		}
+		events = append(events, data.Event{
+			ID:          event.ID,
+			TriggerID:   event.TriggerID,
+			Type:        eventType,
+			ReceivedAt:  parseTime(event.ReceivedAt),
+			PayloadJSON: stringifyJSON(event.Payload, "{}"),
+			Metadata:    stringifyJSON(metadata, "{}"),
+		})
+	}
+	return events
+}
+
+func convertStepRuns(items []api.StepRun) []data.StepRun {
+	steps := make([]data.StepRun, 0, len(items))
+	for _, step := range items {
+		errorJSON := ""
+		if step.Error != nil {
+			errorJSON = stringifyJSON(step.Error, "")
+		}
+		steps = append(steps, data.StepRun{
+			ID:        step.ID,
+			RunID:     step.WorkflowRunID,
+			StepKey:   step.StepKey,
+			Status:    step.Status,
+			StartedAt: parseNullableOrCreated(step.StartedAt, step.CreatedAt),
+			Duration:  durationFromStep(step),
+			Log:       step.LogText(),
+			ErrorJSON: errorJSON,
+		})
+	}
+	return steps
+}
+
+func convertSecrets(items []api.Secret) []data.Secret {
+	secrets := make([]data.Secret, 0, len(items))
+	for _, secret := range items {
+		description := ""
+		if secret.Description != nil {
+			description = *secret.Description
 		}
-		page++
+		secrets = append(secrets, data.Secret{
+			ID:          secret.ID,
+			Name:        secret.Name,
+			Description: description,
+			CreatedAt:   parseTime(secret.CreatedAt),
+			UpdatedAt:   parseTime(secret.UpdatedAt),
+		})
 	}
-	return items, nil
+	return secrets
+}
+
+func convertTokens(items []api.APIToken) []data.ApiToken {
+	tokens := make([]data.ApiToken, 0, len(items))
+	for _, token := range items {
+		var lastUsed *time.Time
+		if token.LastUsedAt != nil {
+			ts := parseTime(*token.LastUsedAt)
+			lastUsed = &ts
+		}
+		tokens = append(tokens, data.ApiToken{
+			ID:         token.ID,
+			Name:       token.Name,
+			Scopes:     token.Scopes,
+			CreatedAt:  parseTime(token.CreatedAt),
+			LastUsedAt: lastUsed,
+			Revoked:    token.RevokedAt != nil,
+		})
+	}
+	return tokens
+}
+
+func listAllWorkflows(ctx context.Context, client *api.Client, sortSpec apiListSort) ([]api.Workflow, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.Workflow], error) {
+		return client.ListWorkflows(ctx, page, apiPageSize, sortSpec.By, sortSpec.Order)
+	})
+}
+
+func listAllWorkflowVersions(ctx context.Context, client *api.Client, workflowID string, sortSpec apiListSort) ([]api.WorkflowVersion, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.WorkflowVersion], error) {
+		return client.ListWorkflowVersions(ctx, workflowID, page, apiPageSize, sortSpec.By, sortSpec.Order)
+	})
+}
+
+func listAllTriggers(ctx context.Context, client *api.Client, workflowID string, sortSpec apiListSort) ([]api.Trigger, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.Trigger], error) {
+		return client.ListTriggers(ctx, workflowID, page, apiPageSize, sortSpec.By, sortSpec.Order)
+	})
+}
+
+func listAllWorkflowRuns(ctx context.Context, client *api.Client, workflowID string, sortSpec apiListSort) ([]api.WorkflowRun, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.WorkflowRun], error) {
+		return client.ListWorkflowRuns(ctx, workflowID, page, apiPageSize, sortSpec.By, sortSpec.Order)
+	})
+}
+
+func listAllEvents(ctx context.Context, client *api.Client, workflowID string, triggerID string, sortSpec apiListSort) ([]api.Event, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.Event], error) {
+		return client.ListEvents(ctx, workflowID, triggerID, page, apiPageSize, sortSpec.By, sortSpec.Order)
+	})
+}
+
+func listAllStepRuns(ctx context.Context, client *api.Client, workflowID string, runID string, sortSpec apiListSort) ([]api.StepRun, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.StepRun], error) {
+		return client.ListStepRuns(ctx, workflowID, runID, page, apiPageSize, sortSpec.By, sortSpec.Order)
+	})
+}
+
+func listAllSecrets(ctx context.Context, client *api.Client, sortSpec apiListSort) ([]api.Secret, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.Secret], error) {
+		return client.ListSecrets(ctx, page, apiPageSize, sortSpec.By, sortSpec.Order)
+	})
+}
+
+func listAllAPITokens(ctx context.Context, client *api.Client) ([]api.APIToken, error) {
+	return api.All(ctx, func(ctx context.Context, page int) (api.Paginated[api.APIToken], error) {
+		return client.ListAPITokens(ctx, page, apiPageSize, "desc")
+	})
 }
 
 func parseTime(value string) time.Time {
@@ -481,17 +784,9 @@ func stringifyJSON(value any, fallback string) string {
 	return string(encoded)
 }
 
-func stringifyLog(value any) string {
-	switch v := value.(type) {
-	case nil:
+func stringValue(value *string) string {
+	if value == nil {
 		return ""
-	case string:
-		return v
-	default:
-		encoded, err := json.MarshalIndent(v, "", "  ")
-		if err != nil {
-			return ""
-		}
-		return string(encoded)
 	}
+	return *value
 }
@@ -0,0 +1,302 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/config"
+	"github.com/gentij/lunie/apps/cli/internal/tui/data"
+)
+
+func writePage(w http.ResponseWriter, items string) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"ok":true,"data":{"items":[%s],"pagination":{"page":1,"pageSize":100,"hasNext":false}}}`, items)
+}
+
+func TestSnapshotLoaderStreamsResourcesWithinWorkerLimit(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.URL.Path == "/workflows":
+			items := []string{}
+			for i := range 5 {
+				items = append(items, fmt.Sprintf(`{"id":"wf_%d","key":"wf-%d","name":"wf-%d","isActive":true}`, i, i, i))
+			}
+			writePage(w, strings.Join(items, ","))
+		case r.URL.Path == "/secrets":
+			writePage(w, `{"id":"sec_1","name":"token"}`)
+		case len(parts) == 3 && parts[2] == "versions":
+			writePage(w, fmt.Sprintf(`{"id":"v_%s","workflowId":"%s","version":3}`, parts[1], parts[1]))
+		case len(parts) == 3 && parts[2] == "triggers":
+			writePage(w, fmt.Sprintf(`{"id":"trg_%s","workflowId":"%s","key":"cron","type":"CRON","isActive":true}`, parts[1], parts[1]))
+		case len(parts) == 3 && parts[2] == "runs":
+			writePage(w, fmt.Sprintf(`{"id":"run_%s","workflowId":"%s","number":1,"status":"SUCCEEDED","triggerId":"trg_%s","createdAt":"2026-01-01T00:00:00Z"}`, parts[1], parts[1], parts[1]))
+		default:
+			t.Errorf("unexpected request during snapshot load: %s", r.URL.Path)
+			writePage(w, "")
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "")
+	slots := make(chan struct{}, 2)
+	cmd := fetchSnapshotCmd(context.Background(), client, slots, 7, defaultSnapshotSortOptions(), 0, false)
+
+	store := data.Store{}
+	resources := 0
+	var done snapshotLoadedMsg
+	for cmd != nil {
+		switch msg := cmd().(type) {
+		case resourceLoadedMsg:
+			if msg.load != 7 || msg.err != nil {
+				t.Fatalf("unexpected resource message: %+v", msg)
+			}
+			if msg.done > msg.total {
+				t.Fatalf("progress %d/%d", msg.done, msg.total)
+			}
+			applyResource(&store, msg.job, msg.store)
+			resources++
+			cmd = msg.next
+		case snapshotLoadedMsg:
+			done = msg
+			cmd = nil
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+
+	if done.err != nil || done.apiStatus != "CONNECTED" {
+		t.Fatalf("unexpected completion: %+v", done)
+	}
+	if resources != 2+5*3 {
+		t.Fatalf("expected 17 resource messages, got %d", resources)
+	}
+	if got := peak.Load(); got > 2 {
+		t.Fatalf("expected at most 2 requests in flight, saw %d", got)
+	}
+	if len(store.Workflows) != 5 || len(store.Runs) != 5 || len(store.Triggers) != 5 || len(store.Secrets) != 1 {
+		t.Fatalf("incomplete store: %d workflows, %d runs, %d triggers, %d secrets", len(store.Workflows), len(store.Runs), len(store.Triggers), len(store.Secrets))
+	}
+	for _, run := range store.Runs {
+		if run.TriggerType != "cron" {
+			t.Fatalf("expected run trigger type to be linked, got %+v", run)
+		}
+	}
+	for _, wf := range store.Workflows {
+		if wf.LatestVersion != 3 {
+			t.Fatalf("expected latest version to be linked, got %+v", wf)
+		}
+	}
+}
+
+func TestLazyLoadFetchesStepsOfSelectedRunOnce(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		writePage(w, `{"id":"step_1","workflowRunId":"run_1","stepKey":"fetch","status":"FAILED","error":{"message":"boom"},"createdAt":"2026-01-01T00:00:00Z"}`)
+	}))
+	defer server.Close()
+
+	m := NewModel(context.Background(), api.NewClient(server.URL, ""), server.URL, false, config.Config{}, "")
+	m.resize(120, 40)
+	m.uiReady = true
+	m.view = ViewRuns
+	m.store = data.Store{
+		Workflows: []data.Workflow{{ID: "wf_1", Key: "wf"}},
+		Runs:      []data.WorkflowRun{{ID: "run_1", WorkflowID: "wf_1", Number: 1, Status: "FAILED", StartedAt: time.Now()}},
+	}
+	m.refreshView()
+	m.table.SetCursor(0)
+
+	cmd := m.lazyLoadCmd()
+	if cmd == nil {
+		t.Fatal("expected a step run load for the selected run")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+
+	if len(requests) != 1 || requests[0] != "/workflows/wf_1/runs/run_1/steps" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	if steps := stepsForRun(&m.store, "run_1"); len(steps) != 1 || steps[0].StepKey != "fetch" {
+		t.Fatalf("expected loaded steps, got %+v", steps)
+	}
+	if run, _ := runByID(&m.store, "run_1"); !strings.Contains(run.ErrorJSON, "boom") {
+		t.Fatalf("expected failed step error on run, got %q", run.ErrorJSON)
+	}
+	if cmd := m.lazyLoadCmd(); cmd != nil {
+		t.Fatal("expected steps to be loaded only once per snapshot")
+	}
+}
+
+func TestApplyResourceDropsChildrenOfRemovedWorkflows(t *testing.T) {
+	store := data.Store{
+		Workflows: []data.Workflow{{ID: "wf_a"}, {ID: "wf_b"}},
+		Triggers:  []data.Trigger{{ID: "trg_b", WorkflowID: "wf_b"}},
+		Runs:      []data.WorkflowRun{{ID: "run_a", WorkflowID: "wf_a"}, {ID: "run_b", WorkflowID: "wf_b"}},
+		StepRuns:  []data.StepRun{{ID: "step_b", RunID: "run_b"}},
+		Events:    []data.Event{{ID: "evt_b", TriggerID: "trg_b"}},
+	}
+
+	applyResource(&store, loadJob{Kind: resourceWorkflows}, data.Store{Workflows: []data.Workflow{{ID: "wf_a"}}})
+
+	if len(store.Runs) != 1 || store.Runs[0].ID != "run_a" {
+		t.Fatalf("expected only run_a, got %+v", store.Runs)
+	}
+	if len(store.Triggers) != 0 || len(store.StepRuns) != 0 || len(store.Events) != 0 {
+		t.Fatalf("expected children of wf_b to be dropped: %+v", store)
+	}
+}
+
+func TestDeltaRefreshStopsAtUnchangedPage(t *testing.T) {
+	updated := "2026-01-01T00:00:00Z"
+	changed := "2026-01-01T00:05:00Z"
+	requests := []string{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path+"?page="+r.URL.Query().Get("page"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		page := r.URL.Query().Get("page")
+		switch r.URL.Path {
+		case "/workflows":
+			writePage(w, `{"id":"wf_1","key":"wf","name":"wf","isActive":true,"updatedAt":"`+updated+`"}`)
+		case "/workflows/wf_1/runs":
+			run := func(id string, at string) string {
+				return `{"id":"` + id + `","workflowId":"wf_1","status":"SUCCEEDED","createdAt":"` + updated + `","updatedAt":"` + at + `"}`
+			}
+			if page == "1" {
+				fmt.Fprintf(w, `{"ok":true,"data":{"items":[%s,%s],"pagination":{"page":1,"hasNext":true}}}`, run("run_2", changed), run("run_new", changed))
+			} else {
+				fmt.Fprintf(w, `{"ok":true,"data":{"items":[%s],"pagination":{"page":%s,"hasNext":true}}}`, run("run_1", updated), page)
+			}
+		default:
+			writePage(w, "")
+		}
+	}))
+	defer server.Close()
+
+	at := parseTime(updated)
+	store := data.Store{
+		Workflows: []data.Workflow{{ID: "wf_1", UpdatedAt: at}},
+		Runs: []data.WorkflowRun{
+			{ID: "run_1", WorkflowID: "wf_1", Status: "SUCCEEDED", UpdatedAt: at},
+			{ID: "run_2", WorkflowID: "wf_1", Status: "RUNNING", UpdatedAt: at},
+		},
+	}
+
+	msg := fetchDeltaCmd(context.Background(), api.NewClient(server.URL, ""), make(chan struct{}, 2), 3, store.Index(), 0, false)()
+	delta, ok := msg.(deltaLoadedMsg)
+	if !ok || delta.err != nil || delta.load != 3 {
+		t.Fatalf("unexpected delta message: %+v", msg)
+	}
+	if len(delta.delta.Workflows) != 0 || len(delta.delta.WorkflowVersions) != 0 {
+		t.Fatalf("expected unchanged workflow to be skipped: %+v", delta.delta)
+	}
+	if len(delta.delta.Runs) != 2 {
+		t.Fatalf("expected the two changed runs, got %+v", delta.delta.Runs)
+	}
+	// The walk stops at page 2; api.Pages may have started at most the next
+	// two pages by then.
+	mu.Lock()
+	defer mu.Unlock()
+	for _, request := range requests {
+		if request == "/workflows/wf_1/runs?page=5" || strings.Contains(request, "/versions") {
+			t.Fatalf("unexpected request %s in %v", request, requests)
+		}
+	}
+
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
+	m.resize(120, 40)
+	m.view = ViewRuns
+	m.store = store
+	m.loadID = 3
+	m.refreshView()
+	updatedModel, _ := m.Update(delta)
+	m = updatedModel.(Model)
+
+	if _, ok := m.highlights["run_2"]; !ok {
+		t.Fatal("expected status change to be highlighted")
+	}
+	if _, ok := m.highlights["run_new"]; !ok {
+		t.Fatal("expected new run to be highlighted")
+	}
+	if _, ok := m.highlights["run_1"]; ok {
+		t.Fatal("expected unchanged run not to be highlighted")
+	}
+	if len(m.store.Runs) != 3 {
+		t.Fatalf("expected merged runs, got %+v", m.store.Runs)
+	}
+	for i, row := range m.table.Rows() {
+		_, changed := m.highlights[m.filteredRowIDs[i]]
+		want := changed && i != m.table.Cursor()
+		if strings.HasPrefix(row[0], "* ") != want {
+			t.Fatalf("row %s: expected changed marker %t, got %q", m.filteredRowIDs[i], want, row[0])
+		}
+	}
+
+	if !m.expireHighlights(time.Now().Add(changeHighlight)) || len(m.highlights) != 0 {
+		t.Fatal("expected highlights to expire")
+	}
+}
+
+func TestDeltaRefreshStopsAtKnownVersion(t *testing.T) {
+	updated := "2026-01-01T00:00:00Z"
+	changed := "2026-01-01T00:05:00Z"
+	var versionPages atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/workflows":
+			writePage(w, `{"id":"wf_1","key":"wf","name":"wf","isActive":true,"updatedAt":"`+changed+`"}`)
+		case "/workflows/wf_1/versions":
+			versionPages.Add(1)
+			version := func(n int) string {
+				return fmt.Sprintf(`{"id":"ver_%d","workflowId":"wf_1","version":%d,"createdAt":"%s"}`, n, n, updated)
+			}
+			fmt.Fprintf(w, `{"ok":true,"data":{"items":[%s,%s],"pagination":{"page":1,"hasNext":true}}}`, version(3), version(2))
+		default:
+			writePage(w, "")
+		}
+	}))
+	defer server.Close()
+
+	store := data.Store{
+		Workflows:        []data.Workflow{{ID: "wf_1", UpdatedAt: parseTime(updated)}},
+		WorkflowVersions: []data.WorkflowVersion{{ID: "ver_2", WorkflowID: "wf_1", Version: 2}},
+	}
+
+	msg := fetchDeltaCmd(context.Background(), api.NewClient(server.URL, ""), make(chan struct{}, 2), 1, store.Index(), 0, false)()
+	delta, ok := msg.(deltaLoadedMsg)
+	if !ok || delta.err != nil {
+		t.Fatalf("unexpected delta message: %+v", msg)
+	}
+	versions := delta.delta.WorkflowVersions["wf_1"]
+	if len(versions) != 1 || versions[0].ID != "ver_3" {
+		t.Fatalf("expected only the new version, got %+v", versions)
+	}
+	if n := versionPages.Load(); n > 3 {
+		t.Fatalf("expected the walk to stop at the known version, got %d pages", n)
+	}
+}
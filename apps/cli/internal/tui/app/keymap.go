@@ -33,6 +33,7 @@ type KeyMap struct {
 	RevokeToken   key.Binding
 	ToggleWrap    key.Binding
 	LogSearch     key.Binding
+	NetworkPane   key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -67,6 +68,7 @@ func DefaultKeyMap() KeyMap {
 		RevokeToken:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "archive/revoke")),
 		ToggleWrap:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "wrap logs")),
 		LogSearch:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search logs")),
+		NetworkPane:   key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "network log")),
 	}
 }
 
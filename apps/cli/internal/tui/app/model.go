@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -56,6 +57,8 @@ const (
 	paletteCreateSecret
 	paletteUpdateSecret
 	paletteDeleteSecret
+	paletteCreateToken
+	paletteRevokeToken
 	paletteSetStatusScope
 	paletteShowCLIHandoff
 	paletteClearRecent
@@ -142,6 +145,8 @@ const (
 	actionModalUpdateSecret
 	actionModalConfirmDelete
 	actionModalCLIHandoff
+	actionModalCreateToken
+	actionModalRevealToken
 )
 
 type actionModalState struct {
@@ -159,6 +164,8 @@ type actionModalState struct {
 	WorkflowID     string
 	TriggerID      string
 	SecretID       string
+	TokenID        string
+	TokenSecret    string
 	DeleteKind     string
 	TriggerType    string
 	TriggerActive  bool
@@ -215,6 +222,8 @@ func (n navItem) Title() string       { return n.Label }
 func (n navItem) Description() string { return "" }
 
 type Model struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
 	client     *api.Client
 	serverURL  string
 	tokenSet   bool
@@ -294,11 +303,29 @@ type Model struct {
 
 	paginator paginator.Model
 
-	snapshotSort snapshotSortOptions
+	snapshotSort   snapshotSortOptions
+	snapshotCancel context.CancelFunc
+	retries        *retryTracker
+
+	loadID       int
+	loadDone     int
+	loadTotal    int
+	loadSlots    chan struct{}
+	lazyLoaded   map[string]bool
+	lastFullLoad time.Time
+	highlights   map[string]time.Time
+
+	network       *networkLog
+	showNetwork   bool
+	networkCursor int
 }
 
-func NewModel(client *api.Client, serverURL string, tokenSet bool, cfg config.Config, configPath string) Model {
+func NewModel(ctx context.Context, client *api.Client, serverURL string, tokenSet bool, cfg config.Config, configPath string) Model {
 	now := time.Now()
+	retries := newRetryTracker()
+	network := newNetworkLog(networkLogSize)
+	client = tracedClient(client, network)
+	ctx, cancel := context.WithCancel(api.WithRetryObserver(ctx, retries.observe))
 	store := data.Store{}
 	keys := DefaultKeyMap()
 	helper := help.New()
@@ -329,6 +356,8 @@ func NewModel(client *api.Client, serverURL string, tokenSet bool, cfg config.Co
 	pager.SetTotalPages(1)
 
 	model := Model{
+		ctx:                ctx,
+		cancel:             cancel,
 		client:             client,
 		serverURL:          serverURL,
 		tokenSet:           tokenSet,
@@ -372,6 +401,12 @@ func NewModel(client *api.Client, serverURL string, tokenSet bool, cfg config.Co
 		sortDesc:           true,
 		statusScopeByView:  map[ViewID]statusScope{},
 		snapshotSort:       defaultSnapshotSortOptions(),
+		retries:            retries,
+		loadID:             1,
+		loadSlots:          make(chan struct{}, loaderWorkers),
+		lazyLoaded:         map[string]bool{},
+		highlights:         map[string]time.Time{},
+		network:            network,
 	}
 	model.setNetworkProfile(NetworkNormal)
 
@@ -389,10 +424,31 @@ func (m Model) Init() tea.Cmd {
 		width, height := initialSize()
 		return tea.WindowSizeMsg{Width: width, Height: height}
 	}
-	return tea.Batch(windowSizeCmd, pulseTick(), fetchSnapshotCmd(m.client, m.snapshotSort, m.profileDelay(), m.profileShouldFail(false)))
+	return tea.Batch(windowSizeCmd, pulseTick(), fetchSnapshotCmd(m.ctx, m.client, m.loadSlots, m.loadID, m.snapshotSort, m.profileDelay(), m.profileShouldFail(false)))
 }
 
+// Update handles msg and then starts any lazy loads the new selection needs.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	next, ok := updated.(Model)
+	if !ok {
+		return updated, cmd
+	}
+	if lazy := next.lazyLoadCmd(); lazy != nil {
+		return next, tea.Batch(cmd, lazy)
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case resourceLoadedMsg:
+		return m.handleResourceLoaded(msg)
+	case snapshotLoadedMsg:
+		return m.handleSnapshotLoaded(msg)
+	case deltaLoadedMsg:
+		return m.handleDeltaLoaded(msg)
+	}
 	if m.inspector.Active {
 		return m.updateInspector(msg)
 	}
@@ -407,38 +463,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case pulseMsg:
 		m.pulseOn = !m.pulseOn
 		cmds := []tea.Cmd{pulseTick()}
-		if m.autoRefresh && !m.refreshPending && time.Since(m.lastRefresh) >= m.refreshEvery {
-			m.startMockRefresh(false)
-			cmds = append(cmds, fetchSnapshotCmd(m.client, m.snapshotSort, m.profileDelay(), m.profileShouldFail(false)))
+		if m.expireHighlights(time.Now()) {
+			m.refreshView()
 		}
-		return m, tea.Batch(cmds...)
-	case snapshotLoadedMsg:
-		m.refreshPending = false
-		m.lastRefresh = time.Now()
-		if msg.err != nil {
-			m.apiStatus = "OFFLINE"
-			if !m.uiReady {
-				m.uiReady = true
-				m.mainState = SurfaceError
-				m.contextState = SurfaceError
-				m.refreshView()
-			}
-			if m.mainState != SurfaceError {
-				m.mainState = SurfaceStale
-			}
-			if m.contextState != SurfaceError {
-				m.contextState = SurfaceStale
+		if m.autoRefresh && !m.refreshPending && time.Since(m.lastRefresh) >= m.refreshEvery {
+			if m.lastFullLoad.IsZero() || time.Since(m.lastFullLoad) >= fullRefreshEvery {
+				m.startMockRefresh(false)
+				cmds = append(cmds, m.refreshSnapshotCmd(false))
+			} else {
+				m.refreshPending = true
+				m.refreshCount++
+				cmds = append(cmds, m.refreshDeltaCmd())
 			}
-			return m, m.pushToast(ToastWarn, "Failed to sync data (ctrl+r retry)")
 		}
-		m.store = msg.store
-		m.apiStatus = msg.apiStatus
-		m.uiReady = true
-		m.mainState = SurfaceIdle
-		m.contextState = SurfaceIdle
-		m.refreshView()
-		m.syncSurfaceStates()
-		return m, nil
+		return m, tea.Batch(cmds...)
 	case toastClearMsg:
 		if m.toast.Active && m.toast.ID == msg.id {
 			m.toast = ToastState{}
@@ -449,13 +487,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			return m, m.pushToast(ToastError, mutationErrorMessage(msg.err))
 		}
+		if msg.createdToken != nil {
+			m.openRevealTokenModal(*msg.createdToken)
+		}
 		cmds := []tea.Cmd{}
 		if strings.TrimSpace(msg.successMessage) != "" {
 			cmds = append(cmds, m.pushToast(ToastSuccess, msg.successMessage))
 		}
 		if msg.refresh {
 			m.startMockRefresh(false)
-			cmds = append(cmds, fetchSnapshotCmd(m.client, m.snapshotSort, m.profileDelay(), m.profileShouldFail(false)))
+			cmds = append(cmds, m.refreshSnapshotCmd(false))
 		}
 		return m, tea.Batch(cmds...)
 	}
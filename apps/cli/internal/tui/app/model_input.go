@@ -20,6 +20,9 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	}
+	if m.showNetwork {
+		return m.updateNetworkPane(msg)
+	}
 	if m.showPalette {
 		return m.updatePalette(msg)
 	}
@@ -31,6 +34,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	if key.Matches(msg, m.keys.Quit) {
+		m.cancel()
 		return m, tea.Quit
 	}
 	if key.Matches(msg, m.keys.Help) {
@@ -38,10 +42,16 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.help.ShowAll = true
 		return m, nil
 	}
+	if key.Matches(msg, m.keys.NetworkPane) {
+		m.showNetwork = true
+		m.networkCursor = 0
+		return m, nil
+	}
 	if key.Matches(msg, m.keys.Retry) && m.canRetry() {
 		m.startMockRefresh(true)
 		clearToastCmd := m.pushToast(ToastInfo, "Retrying refresh...")
-		return m, tea.Batch(fetchSnapshotCmd(m.client, m.snapshotSort, m.profileDelay(), m.profileShouldFail(true)), clearToastCmd)
+		refreshCmd := m.refreshSnapshotCmd(true)
+		return m, tea.Batch(refreshCmd, clearToastCmd)
 	}
 	if key.Matches(msg, m.keys.Palette) {
 		m.palette = buildPalette(m.theme, m.paletteRecent, m.paletteState())
@@ -138,8 +148,11 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.view == ViewSecrets && key.Matches(msg, m.keys.RevokeToken) {
 		return m, m.openDeleteSecretModalCmd()
 	}
+	if m.view == ViewTokens && key.Matches(msg, m.keys.CreateTrigger) {
+		return m, m.openCreateTokenModalCmd()
+	}
 	if m.view == ViewTokens && key.Matches(msg, m.keys.RevokeToken) {
-		return m, m.pushToast(ToastWarn, "API tokens are not available yet")
+		return m, m.openRevokeTokenModalCmd()
 	}
 
 	if m.focus == FocusSidebar {
@@ -162,7 +175,8 @@ func (m *Model) updateMain(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cycleSortColumn()
 			if m.syncServerSortForCurrentView() {
 				m.startMockRefresh(false)
-				return m, fetchSnapshotCmd(m.client, m.snapshotSort, m.profileDelay(), m.profileShouldFail(false))
+				refreshCmd := m.refreshSnapshotCmd(false)
+				return m, refreshCmd
 			}
 			return m, nil
 		}
@@ -170,7 +184,8 @@ func (m *Model) updateMain(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.toggleSortDirection()
 			if m.syncServerSortForCurrentView() {
 				m.startMockRefresh(false)
-				return m, fetchSnapshotCmd(m.client, m.snapshotSort, m.profileDelay(), m.profileShouldFail(false))
+				refreshCmd := m.refreshSnapshotCmd(false)
+				return m, refreshCmd
 			}
 			return m, nil
 		}
@@ -498,6 +513,12 @@ func (m *Model) runPaletteAction(action paletteAction) tea.Cmd {
 	case paletteDeleteSecret:
 		m.rememberPaletteAction(action)
 		return m.openDeleteSecretModalCmd()
+	case paletteCreateToken:
+		m.rememberPaletteAction(action)
+		return m.openCreateTokenModalCmd()
+	case paletteRevokeToken:
+		m.rememberPaletteAction(action)
+		return m.openRevokeTokenModalCmd()
 	case paletteSetStatusScope:
 		m.rememberPaletteAction(action)
 		if !supportsStatusScope(m.view) {
@@ -523,3 +544,17 @@ func (m *Model) runPaletteAction(action paletteAction) tea.Cmd {
 	}
 	return nil
 }
+
+func (m *Model) updateNetworkPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.NetworkPane), key.Matches(msg, m.keys.Back):
+		m.showNetwork = false
+	case key.Matches(msg, m.keys.Up):
+		m.networkCursor = max(m.networkCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.networkCursor = min(m.networkCursor+1, max(len(m.network.snapshot())-1, 0))
+	case key.Matches(msg, m.keys.JumpTop):
+		m.networkCursor = 0
+	}
+	return m, nil
+}
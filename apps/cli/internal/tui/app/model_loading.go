@@ -0,0 +1,262 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gentij/lunie/apps/cli/internal/tui/data"
+)
+
+func (m Model) handleResourceLoaded(msg resourceLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.load != 0 {
+		if msg.load != m.loadID {
+			return m, nil
+		}
+		m.loadDone = max(m.loadDone, msg.done)
+		m.loadTotal = max(m.loadTotal, msg.total)
+	}
+	if msg.err != nil {
+		if msg.load == 0 && !errors.Is(msg.err, context.Canceled) {
+			return m, tea.Batch(msg.next, m.pushToast(ToastWarn, "Failed to load "+resourceLabel(msg.job.Kind)))
+		}
+		return m, msg.next
+	}
+
+	applyResource(&m.store, msg.job, msg.store)
+	if !m.uiReady {
+		m.uiReady = true
+		m.mainState = SurfaceRefreshing
+		m.contextState = SurfaceRefreshing
+	}
+	m.refreshView()
+	if msg.job.Kind == resourceStepRuns && m.inspector.Active && m.inspector.RunID == msg.job.ParentID {
+		m.syncInspectorSteps()
+	}
+	return m, msg.next
+}
+
+func (m Model) handleSnapshotLoaded(msg snapshotLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.load != m.loadID || errors.Is(msg.err, context.Canceled) {
+		return m, nil
+	}
+	m.refreshPending = false
+	m.lastRefresh = time.Now()
+	m.loadDone = 0
+	m.loadTotal = 0
+	m.retries.settle()
+	if msg.err != nil {
+		return m, m.syncFailed()
+	}
+	m.lastFullLoad = m.lastRefresh
+	m.syncSucceeded(msg.apiStatus)
+	return m, nil
+}
+
+// handleDeltaLoaded merges a delta refresh into the store in place and marks
+// the rows it changed.
+func (m Model) handleDeltaLoaded(msg deltaLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.load != m.loadID || errors.Is(msg.err, context.Canceled) {
+		return m, nil
+	}
+	m.refreshPending = false
+	m.lastRefresh = time.Now()
+	m.retries.settle()
+	if msg.err != nil {
+		return m, m.syncFailed()
+	}
+	m.markChanges(m.store.Merge(msg.delta), m.lastRefresh)
+	m.syncSucceeded("CONNECTED")
+	return m, nil
+}
+
+func (m *Model) syncFailed() tea.Cmd {
+	m.apiStatus = "OFFLINE"
+	if !m.uiReady {
+		m.uiReady = true
+		m.mainState = SurfaceError
+		m.contextState = SurfaceError
+		m.refreshView()
+	}
+	if m.mainState != SurfaceError {
+		m.mainState = SurfaceStale
+	}
+	if m.contextState != SurfaceError {
+		m.contextState = SurfaceStale
+	}
+	return m.pushToast(ToastWarn, "Failed to sync data (ctrl+r retry)")
+}
+
+func (m *Model) syncSucceeded(apiStatus string) {
+	m.apiStatus = apiStatus
+	m.uiReady = true
+	m.mainState = SurfaceIdle
+	m.contextState = SurfaceIdle
+	m.refreshView()
+	m.syncSurfaceStates()
+}
+
+// changeHighlight is how long a row that changed status stays marked.
+const changeHighlight = 3 * time.Second
+
+// markChanges highlights rows that were added or changed status, and drops
+// the lazily loaded steps and events of changed runs and triggers so they
+// load again when selected.
+func (m *Model) markChanges(changes []data.Change, now time.Time) {
+	for _, change := range changes {
+		if change.Kind == data.ChangeAdded || change.Kind == data.ChangeStatus {
+			m.highlights[change.ID] = now.Add(changeHighlight)
+		}
+		switch change.Entity {
+		case data.EntityRun:
+			delete(m.lazyLoaded, lazyKey(loadJob{Kind: resourceStepRuns, ParentID: change.ID}))
+		case data.EntityTrigger:
+			delete(m.lazyLoaded, lazyKey(loadJob{Kind: resourceEvents, ParentID: change.ID}))
+		}
+	}
+}
+
+// expireHighlights drops highlights that ran out and reports whether any did.
+func (m *Model) expireHighlights(now time.Time) bool {
+	expired := false
+	for id, until := range m.highlights {
+		if !now.Before(until) {
+			delete(m.highlights, id)
+			expired = true
+		}
+	}
+	return expired
+}
+
+// applyResource replaces the part of the store a job covers with its result.
+func applyResource(store *data.Store, job loadJob, part data.Store) {
+	switch job.Kind {
+	case resourceWorkflows:
+		store.Workflows = part.Workflows
+	case resourceSecrets:
+		store.Secrets = part.Secrets
+	case resourceVersions:
+		store.WorkflowVersions = replaceScope(store.WorkflowVersions, part.WorkflowVersions, func(v data.WorkflowVersion) bool { return v.WorkflowID == job.WorkflowID })
+	case resourceTriggers:
+		store.Triggers = replaceScope(store.Triggers, part.Triggers, func(t data.Trigger) bool { return t.WorkflowID == job.WorkflowID })
+	case resourceRuns:
+		store.Runs = replaceScope(store.Runs, part.Runs, func(r data.WorkflowRun) bool { return r.WorkflowID == job.WorkflowID })
+	case resourceEvents:
+		store.Events = replaceScope(store.Events, part.Events, func(e data.Event) bool { return e.TriggerID == job.ParentID })
+	case resourceStepRuns:
+		store.StepRuns = replaceScope(store.StepRuns, part.StepRuns, func(s data.StepRun) bool { return s.RunID == job.ParentID })
+	case resourceTokens:
+		store.ApiTokens = part.ApiTokens
+	}
+	store.Link()
+}
+
+func replaceScope[T any](items []T, replacement []T, inScope func(T) bool) []T {
+	kept := make([]T, 0, len(items)+len(replacement))
+	for _, item := range items {
+		if !inScope(item) {
+			kept = append(kept, item)
+		}
+	}
+	return append(kept, replacement...)
+}
+
+// lazyLoadCmd fetches the step runs of the selected or inspected run and the
+// events of the selected trigger. The Events view needs the events of every
+// trigger, and the Tokens view the API tokens. Each scope is fetched once per
+// snapshot load.
+func (m *Model) lazyLoadCmd() tea.Cmd {
+	cmds := []tea.Cmd{}
+	for _, job := range m.lazyJobs() {
+		key := lazyKey(job)
+		if m.lazyLoaded[key] {
+			continue
+		}
+		m.lazyLoaded[key] = true
+		cmds = append(cmds, fetchResourceCmd(m.ctx, m.client, m.loadSlots, m.snapshotSort, job))
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) lazyJobs() []loadJob {
+	if !m.uiReady || m.client == nil {
+		return nil
+	}
+	if m.inspector.Active {
+		return runStepJobs(&m.store, m.inspector.RunID)
+	}
+	switch m.view {
+	case ViewDashboard, ViewRuns:
+		return runStepJobs(&m.store, m.selectedRowID())
+	case ViewTriggers:
+		if trigger, ok := triggerByID(&m.store, m.selectedRowID()); ok {
+			return []loadJob{{Kind: resourceEvents, WorkflowID: trigger.WorkflowID, ParentID: trigger.ID}}
+		}
+	case ViewEvents:
+		jobs := make([]loadJob, 0, len(m.store.Triggers))
+		for _, trigger := range m.store.Triggers {
+			jobs = append(jobs, loadJob{Kind: resourceEvents, WorkflowID: trigger.WorkflowID, ParentID: trigger.ID})
+		}
+		return jobs
+	case ViewTokens:
+		return []loadJob{{Kind: resourceTokens}}
+	}
+	return nil
+}
+
+func runStepJobs(store *data.Store, runID string) []loadJob {
+	run, ok := runByID(store, runID)
+	if !ok {
+		return nil
+	}
+	return []loadJob{{Kind: resourceStepRuns, WorkflowID: run.WorkflowID, ParentID: run.ID}}
+}
+
+func lazyKey(job loadJob) string {
+	return resourceLabel(job.Kind) + ":" + job.ParentID
+}
+
+func resourceLabel(kind resourceKind) string {
+	switch kind {
+	case resourceWorkflows:
+		return "workflows"
+	case resourceSecrets:
+		return "secrets"
+	case resourceVersions:
+		return "versions"
+	case resourceTriggers:
+		return "triggers"
+	case resourceRuns:
+		return "runs"
+	case resourceEvents:
+		return "events"
+	case resourceTokens:
+		return "tokens"
+	default:
+		return "steps"
+	}
+}
+
+// syncInspectorSteps reloads the inspector's step list after its run's steps
+// arrive.
+func (m *Model) syncInspectorSteps() {
+	steps := stepsForRun(&m.store, m.inspector.RunID)
+	items := make([]list.Item, 0, len(steps))
+	for _, step := range steps {
+		items = append(items, step)
+	}
+	m.inspector.Steps.SetItems(items)
+	m.inspector.SyncLog(steps)
+}
+
+func loadProgressLabel(m Model) string {
+	if m.loadTotal == 0 {
+		return ""
+	}
+	return "Sync " + itoa(m.loadDone) + "/" + itoa(m.loadTotal)
+}
@@ -3,9 +3,11 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/gentij/lunie/apps/cli/internal/api"
 )
 
 func newActionInput(prompt string, placeholder string, value string, limit int) textinput.Model {
@@ -96,6 +98,22 @@ func stringFromAny(value any) string {
 	return fmt.Sprintf("%v", value)
 }
 
+// parseScopes splits a comma-separated scope list, dropping blanks and
+// duplicates. No scopes means full access.
+func parseScopes(raw string) []string {
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, scope := range strings.Split(raw, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
 func isCronTriggerType(value string) bool {
 	return strings.EqualFold(strings.TrimSpace(value), "CRON")
 }
@@ -241,6 +259,15 @@ func (m *Model) actionModalValidationError() string {
 		if !nameChanged && !descriptionChanged && !valueChanged {
 			return "No changes to update"
 		}
+	case actionModalCreateToken:
+		if strings.TrimSpace(m.action.Primary.Value()) == "" {
+			return "Token name cannot be empty"
+		}
+		for _, scope := range parseScopes(m.action.Secondary.Value()) {
+			if !slices.Contains(api.APITokenScopes, scope) {
+				return "Unknown scope " + scope
+			}
+		}
 	case actionModalConfirmDelete:
 		kind := strings.TrimSpace(strings.ToLower(m.action.DeleteKind))
 		switch kind {
@@ -256,6 +283,10 @@ func (m *Model) actionModalValidationError() string {
 			if strings.TrimSpace(m.action.SecretID) == "" {
 				return "Missing delete target"
 			}
+		case "token":
+			if strings.TrimSpace(m.action.TokenID) == "" {
+				return "Missing revoke target"
+			}
 		default:
 			if strings.TrimSpace(m.action.SecretID) == "" && strings.TrimSpace(m.action.WorkflowID) == "" {
 				return "Missing delete target"
@@ -16,7 +16,7 @@ func (m *Model) updateActionModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.action = actionModalState{}
 			return m, nil
 		}
-		if m.action.Mode == actionModalCLIHandoff {
+		if m.action.Mode == actionModalCLIHandoff || m.action.Mode == actionModalRevealToken {
 			if key.Matches(keyMsg, m.keys.Enter) {
 				m.action = actionModalState{}
 			}
@@ -131,6 +131,19 @@ func (m *Model) updateActionModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.refreshActionValidation()
 					return m, nil
 				}
+			case actionModalCreateToken:
+				if m.action.Focus == 0 {
+					m.action.Primary.SetValue("")
+					m.action.Primary.CursorEnd()
+					m.refreshActionValidation()
+					return m, nil
+				}
+				if m.action.Focus == 1 {
+					m.action.Secondary.SetValue("")
+					m.action.Secondary.CursorEnd()
+					m.refreshActionValidation()
+					return m, nil
+				}
 			}
 		}
 	}
@@ -181,6 +194,17 @@ func (m *Model) updateActionModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.refreshActionValidation()
 			return m, cmd
 		}
+	case actionModalCreateToken:
+		if m.action.Focus == 0 {
+			m.action.Primary, cmd = m.action.Primary.Update(msg)
+			m.refreshActionValidation()
+			return m, cmd
+		}
+		if m.action.Focus == 1 {
+			m.action.Secondary, cmd = m.action.Secondary.Update(msg)
+			m.refreshActionValidation()
+			return m, cmd
+		}
 	case actionModalConfirmDelete:
 		m.action.Confirm, cmd = m.action.Confirm.Update(msg)
 		m.refreshActionValidation()
@@ -234,6 +258,11 @@ func (m *Model) submitActionModal() tea.Cmd {
 		description := strings.TrimSpace(m.action.Tertiary.Value())
 		m.action = actionModalState{}
 		return m.updateSecretCmd(secretID, name, value, description)
+	case actionModalCreateToken:
+		name := strings.TrimSpace(m.action.Primary.Value())
+		scopes := parseScopes(m.action.Secondary.Value())
+		m.action = actionModalState{}
+		return m.createTokenCmd(name, scopes)
 	case actionModalConfirmDelete:
 		if errMessage := m.actionModalValidationError(); errMessage != "" {
 			m.action.Validation = errMessage
@@ -244,10 +273,14 @@ func (m *Model) submitActionModal() tea.Cmd {
 		workflowID := m.action.WorkflowID
 		triggerID := m.action.TriggerID
 		secretID := m.action.SecretID
+		tokenID := m.action.TokenID
 		m.action = actionModalState{}
 		if kind == "secret" {
 			return m.deleteSecretCmd(secretID)
 		}
+		if kind == "token" {
+			return m.revokeTokenCmd(tokenID)
+		}
 		if strings.TrimSpace(triggerID) != "" {
 			return m.deleteTriggerCmd(workflowID, triggerID)
 		}
@@ -278,6 +311,8 @@ func (m *Model) cycleActionModalFocus(delta int) {
 		}
 	case actionModalCreateSecret, actionModalUpdateSecret:
 		total = 3
+	case actionModalCreateToken:
+		total = 2
 	case actionModalConfirmDelete:
 		total = 1
 	default:
@@ -332,6 +367,13 @@ func (m *Model) syncActionModalFocus() {
 		if m.action.Focus == 2 {
 			m.action.Tertiary.Focus()
 		}
+	case actionModalCreateToken:
+		if m.action.Focus == 0 {
+			m.action.Primary.Focus()
+		}
+		if m.action.Focus == 1 {
+			m.action.Secondary.Focus()
+		}
 	case actionModalConfirmDelete:
 		m.action.Confirm.Focus()
 	}
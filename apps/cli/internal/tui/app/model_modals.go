@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gentij/lunie/apps/cli/internal/api"
 )
 
 func (m *Model) openRenameWorkflowModalCmd() tea.Cmd {
@@ -179,6 +180,63 @@ func (m *Model) openDeleteSecretModalCmd() tea.Cmd {
 	return nil
 }
 
+func (m *Model) openCreateTokenModalCmd() tea.Cmd {
+	if m.mutationPending {
+		return m.pushToast(ToastWarn, "Another action is still in progress")
+	}
+	if m.view != ViewTokens {
+		return m.pushToast(ToastWarn, "Open API Tokens to create")
+	}
+	nameInput := newActionInput("name> ", "Token name", "", 120)
+	scopesInput := newActionInput("scopes> ", "Comma-separated; empty for full access", "", 500)
+	m.action = actionModalState{
+		Active:      true,
+		Mode:        actionModalCreateToken,
+		Title:       "Create API Token",
+		Description: "The token is shown once after it is created.",
+		Primary:     nameInput,
+		Secondary:   scopesInput,
+		Focus:       0,
+	}
+	m.syncActionModalFocus()
+	return nil
+}
+
+// openRevealTokenModal shows a newly created token. The modal is the only
+// place the TUI keeps the raw token, and closing it drops it.
+func (m *Model) openRevealTokenModal(token api.CreatedAPIToken) {
+	m.action = actionModalState{
+		Active:      true,
+		Mode:        actionModalRevealToken,
+		Title:       "API Token Created",
+		Description: "Copy the token for \"" + token.Name + "\" now; it will not be shown again.",
+		TokenID:     token.ID,
+		TokenSecret: token.Token,
+	}
+}
+
+func (m *Model) openRevokeTokenModalCmd() tea.Cmd {
+	if m.mutationPending {
+		return m.pushToast(ToastWarn, "Another action is still in progress")
+	}
+	if m.view != ViewTokens {
+		return m.pushToast(ToastWarn, "Open API Tokens to revoke")
+	}
+	selected := m.selectedRowID()
+	tok, ok := tokenByID(&m.store, selected)
+	if !ok {
+		return m.pushToast(ToastWarn, "Select a token first")
+	}
+	if tok.Revoked {
+		return m.pushToast(ToastInfo, "Token already revoked")
+	}
+	phrase := "REVOKE " + tok.Name
+	description := "Revoke API token \"" + tok.Name + "\"; clients using it lose access"
+	m.openDeleteConfirmModal("Revoke API Token", description, phrase, "token", "", "", "")
+	m.action.TokenID = tok.ID
+	return nil
+}
+
 func (m *Model) openDeleteWorkflowModalCmd() tea.Cmd {
 	if m.mutationPending {
 		return m.pushToast(ToastWarn, "Another action is still in progress")
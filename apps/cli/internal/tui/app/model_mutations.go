@@ -20,13 +20,14 @@ func (m *Model) toggleWorkflowActiveCmd() tea.Cmd {
 		return m.pushToast(ToastWarn, "Select a workflow first")
 	}
 	client := m.client
+	ctx := m.ctx
 	next := !wf.Active
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
 			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
 		}
-		_, err := client.UpdateWorkflowByKey(wf.Key, map[string]any{"isActive": next})
+		_, err := client.UpdateWorkflowByKey(ctx, wf.Key, map[string]any{"isActive": next})
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -54,12 +55,13 @@ func (m *Model) queueRunForSelectedWorkflowCmd() tea.Cmd {
 		return m.pushToast(ToastWarn, "Select a workflow first")
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
 			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
 		}
-		result, err := client.RunWorkflowByKey(wf.Key, map[string]any{}, map[string]any{})
+		result, err := client.RunWorkflowByKey(ctx, wf.Key, map[string]any{}, map[string]any{})
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -87,13 +89,14 @@ func (m *Model) toggleTriggerActiveCmd() tea.Cmd {
 		return m.pushToast(ToastWarn, "Select a trigger first")
 	}
 	client := m.client
+	ctx := m.ctx
 	next := !trg.Active
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
 			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
 		}
-		_, err := client.UpdateTriggerByKey(workflowKey(&m.store, trg.WorkflowID), trg.Key, map[string]any{"isActive": next})
+		_, err := client.UpdateTriggerByKey(ctx, workflowKey(&m.store, trg.WorkflowID), trg.Key, map[string]any{"isActive": next})
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -114,6 +117,7 @@ func (m *Model) deleteWorkflowCmd(workflowID string) tea.Cmd {
 		return m.pushToast(ToastWarn, "Select a workflow first")
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
@@ -123,7 +127,7 @@ func (m *Model) deleteWorkflowCmd(workflowID string) tea.Cmd {
 		if !ok {
 			return mutationResultMsg{err: fmt.Errorf("workflow not found")}
 		}
-		_, err := client.DeleteWorkflowByKey(workflow.Key)
+		_, err := client.DeleteWorkflowByKey(ctx, workflow.Key)
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -141,6 +145,7 @@ func (m *Model) deleteTriggerCmd(workflowID string, triggerID string) tea.Cmd {
 		return m.pushToast(ToastWarn, "Select a trigger first")
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
@@ -150,7 +155,7 @@ func (m *Model) deleteTriggerCmd(workflowID string, triggerID string) tea.Cmd {
 		if !ok {
 			return mutationResultMsg{err: fmt.Errorf("trigger not found")}
 		}
-		_, err := client.DeleteTriggerByKey(workflowKey(&m.store, workflowID), trigger.Key)
+		_, err := client.DeleteTriggerByKey(ctx, workflowKey(&m.store, workflowID), trigger.Key)
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -175,12 +180,13 @@ func (m *Model) createSecretCmd(name string, value string, description string) t
 		payload["description"] = description
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
 			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
 		}
-		_, err := client.CreateSecret(payload)
+		_, err := client.CreateSecret(ctx, payload)
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -219,12 +225,13 @@ func (m *Model) updateSecretCmd(secretID string, name string, value string, desc
 		return m.pushToast(ToastWarn, "No changes to update")
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
 			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
 		}
-		_, err := client.UpdateSecret(secretID, patch)
+		_, err := client.UpdateSecret(ctx, secretID, patch)
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -241,12 +248,13 @@ func (m *Model) deleteSecretCmd(secretID string) tea.Cmd {
 		return m.pushToast(ToastWarn, "Select a secret first")
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
 			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
 		}
-		_, err := client.DeleteSecret(secretID)
+		_, err := client.DeleteSecret(ctx, secretID)
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -266,6 +274,7 @@ func (m *Model) renameWorkflowCmd(workflowID string, name string) tea.Cmd {
 		return m.pushToast(ToastWarn, "Workflow name cannot be empty")
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
@@ -275,7 +284,7 @@ func (m *Model) renameWorkflowCmd(workflowID string, name string) tea.Cmd {
 		if !ok {
 			return mutationResultMsg{err: fmt.Errorf("workflow not found")}
 		}
-		_, err := client.UpdateWorkflowByKey(workflow.Key, map[string]any{"name": name})
+		_, err := client.UpdateWorkflowByKey(ctx, workflow.Key, map[string]any{"name": name})
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -326,12 +335,13 @@ func (m *Model) updateTriggerCmd(workflowID string, triggerID string, name strin
 		return m.pushToast(ToastWarn, "No changes to update")
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	return func() tea.Msg {
 		if client == nil {
 			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
 		}
-		_, err := client.UpdateTriggerByKey(workflowKey(&m.store, workflowID), trigger.Key, patch)
+		_, err := client.UpdateTriggerByKey(ctx, workflowKey(&m.store, workflowID), trigger.Key, patch)
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
@@ -355,6 +365,7 @@ func (m *Model) createTriggerCmd(workflowID string, triggerType string, name str
 		configValue = map[string]any{}
 	}
 	client := m.client
+	ctx := m.ctx
 	m.mutationPending = true
 	triggerType = strings.ToUpper(strings.TrimSpace(triggerType))
 	return func() tea.Msg {
@@ -367,10 +378,56 @@ func (m *Model) createTriggerCmd(workflowID string, triggerType string, name str
 			"isActive": active,
 			"config":   configValue,
 		}
-		_, err := client.CreateTriggerByWorkflowKey(workflowKey(&m.store, workflowID), payload)
+		_, err := client.CreateTriggerByWorkflowKey(ctx, workflowKey(&m.store, workflowID), payload)
 		if err != nil {
 			return mutationResultMsg{err: err}
 		}
 		return mutationResultMsg{successMessage: "Trigger created", refresh: true}
 	}
 }
+
+func (m *Model) createTokenCmd(name string, scopes []string) tea.Cmd {
+	if m.mutationPending {
+		return m.pushToast(ToastWarn, "Another action is still in progress")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return m.pushToast(ToastWarn, "Token name cannot be empty")
+	}
+	client := m.client
+	ctx := m.ctx
+	m.mutationPending = true
+	return func() tea.Msg {
+		if client == nil {
+			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
+		}
+		created, err := client.CreateAPIToken(ctx, name, scopes)
+		if err != nil {
+			return mutationResultMsg{err: err}
+		}
+		return mutationResultMsg{successMessage: "API token created", refresh: true, createdToken: &created}
+	}
+}
+
+func (m *Model) revokeTokenCmd(tokenID string) tea.Cmd {
+	if m.mutationPending {
+		return m.pushToast(ToastWarn, "Another action is still in progress")
+	}
+	tokenID = strings.TrimSpace(tokenID)
+	if tokenID == "" {
+		return m.pushToast(ToastWarn, "Select a token first")
+	}
+	client := m.client
+	ctx := m.ctx
+	m.mutationPending = true
+	return func() tea.Msg {
+		if client == nil {
+			return mutationResultMsg{err: fmt.Errorf("api client unavailable")}
+		}
+		_, err := client.RevokeAPIToken(ctx, tokenID)
+		if err != nil {
+			return mutationResultMsg{err: err}
+		}
+		return mutationResultMsg{successMessage: "API token revoked", refresh: true}
+	}
+}
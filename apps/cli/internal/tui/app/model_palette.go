@@ -174,6 +174,20 @@ func paletteItemFromAction(action paletteAction, state paletteBuildState) palett
 			item.Detail = "Unavailable: select secret row"
 			item.DisabledReason = "Select a secret row in Secrets first"
 		}
+	case paletteCreateToken:
+		item.Label = "Action: Create API token"
+		if state.View != ViewTokens {
+			item.Enabled = false
+			item.Detail = "Unavailable in this view"
+			item.DisabledReason = "Open API Tokens first"
+		}
+	case paletteRevokeToken:
+		item.Label = "Action: Revoke selected API token"
+		if !(state.View == ViewTokens && state.HasSelection) {
+			item.Enabled = false
+			item.Detail = "Unavailable: select token row"
+			item.DisabledReason = "Select a token row in API Tokens first"
+		}
 	case paletteSetStatusScope:
 		if !state.HasScope {
 			item.Enabled = false
@@ -383,6 +397,20 @@ func buildPalette(theme styles.Theme, recentActions []paletteAction, state palet
 		deleteSecret.DisabledReason = "Select a secret row in Secrets first"
 	}
 
+	createToken := command("Action: Create API token", "Token", paletteAction{Kind: paletteCreateToken}, "create", "token", "api", "auth")
+	if state.View != ViewTokens {
+		createToken.Enabled = false
+		createToken.Detail = "Unavailable in this view"
+		createToken.DisabledReason = "Open API Tokens first"
+	}
+
+	revokeToken := command("Action: Revoke selected API token", "Token", paletteAction{Kind: paletteRevokeToken}, "revoke", "token", "api", "auth")
+	if !(state.View == ViewTokens && state.HasSelection) {
+		revokeToken.Enabled = false
+		revokeToken.Detail = "Unavailable: select token row"
+		revokeToken.DisabledReason = "Select a token row in API Tokens first"
+	}
+
 	showAllScope := command("Filter: Show all", "Status scope", paletteAction{Kind: paletteSetStatusScope, Value: "all"}, "filter", "status", "all")
 	showActiveScope := command("Filter: Active only", "Status scope", paletteAction{Kind: paletteSetStatusScope, Value: "active"}, "filter", "status", "active")
 	showInactiveScope := command("Filter: Inactive only", "Status scope", paletteAction{Kind: paletteSetStatusScope, Value: "inactive"}, "filter", "status", "inactive")
@@ -449,6 +477,8 @@ func buildPalette(theme styles.Theme, recentActions []paletteAction, state palet
 		createSecret,
 		updateSecret,
 		deleteSecret,
+		createToken,
+		revokeToken,
 		showAllScope,
 		showActiveScope,
 		showInactiveScope,
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -79,6 +80,35 @@ func (m *Model) startMockRefresh(manual bool) {
 	}
 }
 
+// fullRefreshEvery is how often auto-refresh reloads the whole snapshot
+// instead of a delta, which is the only way it sees deletions.
+const fullRefreshEvery = time.Minute
+
+// nextLoad cancels any load still in flight so a slow server cannot deliver
+// stale data after a newer refresh was requested.
+func (m *Model) nextLoad() context.Context {
+	if m.snapshotCancel != nil {
+		m.snapshotCancel()
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.snapshotCancel = cancel
+	m.loadID++
+	m.loadDone = 0
+	m.loadTotal = 0
+	return ctx
+}
+
+func (m *Model) refreshSnapshotCmd(fail bool) tea.Cmd {
+	ctx := m.nextLoad()
+	m.lazyLoaded = map[string]bool{}
+	return fetchSnapshotCmd(ctx, m.client, m.loadSlots, m.loadID, m.snapshotSort, m.profileDelay(), m.profileShouldFail(fail))
+}
+
+func (m *Model) refreshDeltaCmd() tea.Cmd {
+	ctx := m.nextLoad()
+	return fetchDeltaCmd(ctx, m.client, m.loadSlots, m.loadID, m.store.Index(), m.profileDelay(), m.profileShouldFail(false))
+}
+
 func (m *Model) syncSurfaceStates() {
 	if !m.uiReady {
 		return
@@ -1,18 +1,25 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gentij/lunie/apps/cli/internal/api"
 	"github.com/gentij/lunie/apps/cli/internal/config"
 	"github.com/gentij/lunie/apps/cli/internal/tui/data"
 )
 
 func TestRefreshView_PreservesSelectionByRowID(t *testing.T) {
 	now := time.Now()
-	m := NewModel(nil, "", false, config.Config{}, "")
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
 	m.view = ViewWorkflows
 	m.store = data.Store{
 		Workflows: []data.Workflow{
@@ -54,8 +61,24 @@ func TestParseJSONObject_RequiresObject(t *testing.T) {
 	}
 }
 
+func TestCreateTokenModalRejectsUnknownScopes(t *testing.T) {
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
+	m.view = ViewTokens
+	m.openCreateTokenModalCmd()
+	m.action.Primary.SetValue("ci")
+	m.action.Secondary.SetValue("runs:read, runs:admin")
+
+	if got := m.actionModalValidationError(); got != "Unknown scope runs:admin" {
+		t.Fatalf("expected unknown scope error, got %q", got)
+	}
+	m.action.Secondary.SetValue("runs:read, workflows:write")
+	if got := m.actionModalValidationError(); got != "" {
+		t.Fatalf("expected known scopes to pass, got %q", got)
+	}
+}
+
 func TestDeleteConfirmModal_RequiresExactPhrase(t *testing.T) {
-	m := NewModel(nil, "", false, config.Config{}, "")
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
 	m.openDeleteConfirmModal("Archive Workflow", "Archive test workflow", "ARCHIVE workflow-key", "workflow", "wf_test", "", "")
 
 	if got := m.actionModalValidationError(); got == "" {
@@ -74,7 +97,7 @@ func TestDeleteConfirmModal_RequiresExactPhrase(t *testing.T) {
 }
 
 func TestSubmitDeleteConfirmDispatchesDeleteCmd(t *testing.T) {
-	m := NewModel(nil, "", false, config.Config{}, "")
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
 	m.openDeleteConfirmModal("Archive Trigger", "Archive test trigger", "ARCHIVE trigger-key", "trigger", "wf_test", "trg_test", "")
 	m.action.Confirm.SetValue("ARCHIVE trigger-key")
 
@@ -97,7 +120,7 @@ func TestSubmitDeleteConfirmDispatchesDeleteCmd(t *testing.T) {
 }
 
 func TestCreateSecretValidation_RequiresNameAndValue(t *testing.T) {
-	m := NewModel(nil, "", false, config.Config{}, "")
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
 	m.action = actionModalState{
 		Active:    true,
 		Mode:      actionModalCreateSecret,
@@ -119,7 +142,7 @@ func TestCreateSecretValidation_RequiresNameAndValue(t *testing.T) {
 }
 
 func TestTriggerConfigFromAction_CronTypedFields(t *testing.T) {
-	m := NewModel(nil, "", false, config.Config{}, "")
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
 	m.action = actionModalState{
 		Mode:        actionModalCreateTrigger,
 		TriggerType: "CRON",
@@ -139,7 +162,7 @@ func TestTriggerConfigFromAction_CronTypedFields(t *testing.T) {
 }
 
 func TestTriggerValidation_CronRequiresFiveFields(t *testing.T) {
-	m := NewModel(nil, "", false, config.Config{}, "")
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
 	m.action = actionModalState{
 		Mode:        actionModalCreateTrigger,
 		WorkflowID:  "wf_1",
@@ -155,7 +178,7 @@ func TestTriggerValidation_CronRequiresFiveFields(t *testing.T) {
 
 func TestScopeRowsForCurrentView_ActiveOnlyWorkflows(t *testing.T) {
 	now := time.Now()
-	m := NewModel(nil, "", false, config.Config{}, "")
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
 	m.view = ViewWorkflows
 	m.store = data.Store{
 		Workflows: []data.Workflow{
@@ -175,3 +198,90 @@ func TestScopeRowsForCurrentView_ActiveOnlyWorkflows(t *testing.T) {
 		t.Fatalf("unexpected row id: %q", filteredIDs[0])
 	}
 }
+
+func TestNetworkPaneIsToggledByHiddenKey(t *testing.T) {
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
+	m.network.record(api.TraceEntry{Method: "GET", URL: "http://localhost/v1/api/workflows", StatusCode: 200})
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if !m.showNetwork {
+		t.Fatal("expected ctrl+n to open the network pane")
+	}
+	if view := Render(m); !strings.Contains(view, "/v1/api/workflows") {
+		t.Fatalf("expected network pane to list the request, got %q", view)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showNetwork {
+		t.Fatal("expected esc to close the network pane")
+	}
+}
+
+func TestCreateTokenRevealsSecretOnce(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api-tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"data":{"id":"tok_1","name":"ci","scopes":["runs:read"],"createdAt":"2026-01-01T00:00:00Z","lastUsedAt":null,"revokedAt":null,"token":"lunie_secret"}}`)
+	}))
+	defer server.Close()
+
+	m := NewModel(context.Background(), api.NewClient(server.URL, ""), server.URL, false, config.Config{}, "")
+	m.view = ViewTokens
+	m.openCreateTokenModalCmd()
+	m.action.Primary.SetValue("ci")
+	m.action.Secondary.SetValue("runs:read, runs:read,")
+
+	cmd := m.submitActionModal()
+	if cmd == nil {
+		t.Fatal("expected create command")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+
+	if scopes, _ := body["scopes"].([]any); len(scopes) != 1 || scopes[0] != "runs:read" {
+		t.Fatalf("expected deduplicated scopes, got %#v", body)
+	}
+	if m.action.Mode != actionModalRevealToken || m.action.TokenSecret != "lunie_secret" {
+		t.Fatalf("expected token to be revealed, got %+v", m.action)
+	}
+	if !strings.Contains(renderActionModal(m), "lunie_secret") {
+		t.Fatal("expected reveal modal to show the token")
+	}
+
+	m.updateActionModal(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.action.Active || m.action.TokenSecret != "" {
+		t.Fatalf("expected closing the modal to drop the token, got %+v", m.action)
+	}
+}
+
+func TestRevokeTokenModalSkipsRevokedTokens(t *testing.T) {
+	m := NewModel(context.Background(), nil, "", false, config.Config{}, "")
+	m.resize(120, 40)
+	m.view = ViewTokens
+	m.store = data.Store{ApiTokens: []data.ApiToken{
+		{ID: "tok_1", Name: "ci", CreatedAt: time.Now()},
+		{ID: "tok_2", Name: "old", CreatedAt: time.Now().Add(-time.Hour), Revoked: true},
+	}}
+	m.refreshView()
+
+	m.table.SetCursor(0)
+	m.openRevokeTokenModalCmd()
+	if m.action.Mode != actionModalConfirmDelete || m.action.TokenID != "tok_1" {
+		t.Fatalf("expected revoke confirmation for tok_1, got %+v", m.action)
+	}
+	m.action.Confirm.SetValue("REVOKE ci")
+	if got := m.actionModalValidationError(); got != "" {
+		t.Fatalf("expected valid confirmation, got %q", got)
+	}
+
+	m.action = actionModalState{}
+	m.table.SetCursor(1)
+	m.openRevokeTokenModalCmd()
+	if m.action.Active {
+		t.Fatal("expected revoked token not to open a confirmation")
+	}
+}
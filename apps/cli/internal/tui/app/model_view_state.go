@@ -137,7 +137,13 @@ func (m *Model) applyTableRows() {
 		cursor = 0
 		m.table.SetCursor(0)
 	}
-	styled := components.StyleRows(truncated, m.columns, cursor, m.styles)
+	changed := map[int]bool{}
+	for i, id := range m.filteredRowIDs {
+		if _, ok := m.highlights[id]; ok {
+			changed[i] = true
+		}
+	}
+	styled := components.StyleRows(truncated, m.columns, cursor, changed, m.styles)
 	m.table.SetRows(styled)
 	m.updatePaginator()
 	m.updateContext()
@@ -214,7 +220,11 @@ func (m *Model) applyTheme(themeKey string, persist bool) {
 
 	if persist {
 		m.config.Theme = key
-		_ = config.Save(m.configPath, m.config)
+		contextName := m.config.CurrentContext
+		_ = config.Update(m.configPath, func(cfg *config.Config) error {
+			cfg.SetTheme(contextName, key)
+			return nil
+		})
 	}
 
 	m.refreshView()
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+)
+
+const (
+	flakyFailEvery = 4
+	networkLogSize = 200
+)
+
+// retryTracker collects retry events from API calls running in tea.Cmd
+// goroutines so the chrome can render them on the next frame.
+type retryTracker struct {
+	mu          sync.Mutex
+	attempt     int
+	maxAttempts int
+	total       int
+}
+
+func newRetryTracker() *retryTracker {
+	return &retryTracker{}
+}
+
+func (t *retryTracker) observe(event api.RetryEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempt = event.Attempt
+	t.maxAttempts = event.MaxAttempts
+	t.total++
+}
+
+func (t *retryTracker) settle() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempt = 0
+	t.maxAttempts = 0
+}
+
+func (t *retryTracker) label() (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.attempt > 0 {
+		return "Retry " + strconv.Itoa(t.attempt) + "/" + strconv.Itoa(t.maxAttempts), true
+	}
+	if t.total > 0 {
+		return "Retries " + strconv.Itoa(t.total), false
+	}
+	return "", false
+}
+
+// networkLog keeps the most recent API round trips for the hidden network
+// pane. Entries arrive from tea.Cmd goroutines.
+type networkLog struct {
+	mu      sync.Mutex
+	entries []api.TraceEntry
+	limit   int
+}
+
+func newNetworkLog(limit int) *networkLog {
+	return &networkLog{limit: limit}
+}
+
+func (l *networkLog) record(entry api.TraceEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	if over := len(l.entries) - l.limit; over > 0 {
+		l.entries = append(l.entries[:0:0], l.entries[over:]...)
+	}
+}
+
+// snapshot returns the entries newest first.
+func (l *networkLog) snapshot() []api.TraceEntry {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make([]api.TraceEntry, len(l.entries))
+	for i, entry := range l.entries {
+		entries[len(l.entries)-1-i] = entry
+	}
+	return entries
+}
+
+// tracedClient returns a copy of client that records every request, with
+// redacted headers and bodies, into log.
+func tracedClient(client *api.Client, log *networkLog) *api.Client {
+	if client == nil {
+		return nil
+	}
+	clone := *client
+	httpClient := http.Client{}
+	if client.HTTPClient != nil {
+		httpClient = *client.HTTPClient
+	}
+	clone.HTTPClient = &httpClient
+	clone.EnableTrace(api.TraceBodies, log.record)
+	return &clone
+}
+
+// flakyClient returns a copy of client whose transport answers every few
+// requests with a 503, so the flaky network profile exercises the client's
+// real retry policy instead of failing the whole refresh.
+func flakyClient(client *api.Client) *api.Client {
+	clone := *client
+	httpClient := http.Client{}
+	if client.HTTPClient != nil {
+		httpClient = *client.HTTPClient
+	}
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient.Transport = &flakyTransport{base: base}
+	clone.HTTPClient = &httpClient
+	return &clone
+}
+
+type flakyTransport struct {
+	base  http.RoundTripper
+	calls atomic.Int32
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.calls.Add(1)%flakyFailEvery != 1 {
+		return t.base.RoundTrip(req)
+	}
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	body := `{"ok":false,"statusCode":503,"error":{"code":"SERVICE_UNAVAILABLE","message":"simulated flaky network"}}`
+	return &http.Response{
+		Status:     "503 Service Unavailable",
+		StatusCode: http.StatusServiceUnavailable,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
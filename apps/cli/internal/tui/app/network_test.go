@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gentij/lunie/apps/cli/internal/api"
+)
+
+func TestFlakyClientRetriesThroughTracker(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"status":"ok"}}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "")
+	client.Retry = api.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	tracker := newRetryTracker()
+	ctx := api.WithRetryObserver(context.Background(), tracker.observe)
+
+	if _, err := flakyClient(client).GetHealth(ctx); err != nil {
+		t.Fatalf("expected retry to recover, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the injected failure to be retried once, got %d server calls", calls)
+	}
+	if label, active := tracker.label(); label != "Retry 2/3" || !active {
+		t.Fatalf("unexpected in-flight label: %q %t", label, active)
+	}
+
+	tracker.settle()
+	if label, active := tracker.label(); label != "Retries 1" || active {
+		t.Fatalf("unexpected settled label: %q %t", label, active)
+	}
+
+	client.Retry = api.RetryPolicy{MaxAttempts: 1}
+	if _, err := flakyClient(client).GetHealth(context.Background()); err == nil {
+		t.Fatalf("expected injected failure without retries")
+	}
+}
+
+func TestNetworkLogKeepsNewestEntriesFirst(t *testing.T) {
+	log := newNetworkLog(2)
+	for _, path := range []string{"/a", "/b", "/c"} {
+		log.record(api.TraceEntry{URL: path})
+	}
+
+	entries := log.snapshot()
+	if len(entries) != 2 || entries[0].URL != "/c" || entries[1].URL != "/b" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestTracedClientRecordsWithoutTouchingOriginal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"status":"ok"}}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "token")
+	log := newNetworkLog(networkLogSize)
+	if _, err := tracedClient(client, log).GetHealth(context.Background()); err != nil {
+		t.Fatalf("health: %v", err)
+	}
+	if _, err := client.GetHealth(context.Background()); err != nil {
+		t.Fatalf("health: %v", err)
+	}
+
+	entries := log.snapshot()
+	if len(entries) != 1 || entries[0].StatusCode != http.StatusOK || entries[0].ResponseBody == "" {
+		t.Fatalf("expected one recorded request with a body, got %+v", entries)
+	}
+}
@@ -28,6 +28,9 @@ func Render(m Model) string {
 	if m.showHelp {
 		output = renderHelpScreen(m)
 	}
+	if m.showNetwork {
+		output = renderNetworkScreen(m)
+	}
 	if m.action.Active {
 		modal := renderActionModal(m)
 		output = renderOverlay(base, modal, m)
@@ -68,9 +71,14 @@ func renderSidebar(m Model) string {
 		chip(m, "API "+m.apiStatus, m.apiStatus == "CONNECTED"),
 		chip(m, refreshChip(m), false),
 		chip(m, "Net "+networkProfileLabel(m.networkProfile), m.networkProfile == NetworkFlaky),
-		chip(m, "Theme "+strings.Title(strings.ReplaceAll(m.themeName, "-", " ")), false),
-		m.styles.Dim.Render("Font hint: " + recommendedFont(m.themeName)),
 	}
+	if label, active := m.retries.label(); label != "" {
+		status = append(status, chip(m, label, active))
+	}
+	status = append(status,
+		chip(m, "Theme "+strings.Title(strings.ReplaceAll(m.themeName, "-", " ")), false),
+		m.styles.Dim.Render("Font hint: "+recommendedFont(m.themeName)),
+	)
 
 	content := joinSidebarContent(
 		[]string{brand, workspace, focus, "", section},
@@ -244,6 +252,22 @@ func renderActionModal(m Model) string {
 			m.action.Secondary.View(),
 			m.action.Tertiary.View(),
 		}, "\n")
+	case actionModalCreateToken:
+		hint = "tab next field  |  enter submit  |  esc cancel"
+		body = strings.Join([]string{
+			m.action.Description,
+			"",
+			m.action.Primary.View(),
+			m.action.Secondary.View(),
+		}, "\n")
+	case actionModalRevealToken:
+		hint = "enter/esc close"
+		body = strings.Join([]string{
+			m.action.Description,
+			"",
+			m.styles.PanelTitle.Render("Token"),
+			m.action.TokenSecret,
+		}, "\n")
 	case actionModalCLIHandoff:
 		hint = "enter/esc close"
 		body = strings.Join([]string{
@@ -18,10 +18,20 @@ func renderMainHeader(m Model, width int) string {
 		filter = "Filter: " + m.searchQuery
 	}
 	line1 := joinLeftRight(left, filter, width)
-	chips := []string{
+	chips := []string{}
+	if m.config.CurrentContext != "" {
+		chips = append(chips, chip(m, "Ctx "+m.config.CurrentContext, false))
+	}
+	chips = append(chips,
 		chip(m, "API "+m.apiStatus, m.apiStatus == "CONNECTED"),
 		chip(m, refreshChip(m), false),
 		chip(m, "Net "+networkProfileLabel(m.networkProfile), m.networkProfile == NetworkFlaky),
+	)
+	if label := loadProgressLabel(m); label != "" {
+		chips = append(chips, chip(m, label, true))
+	}
+	if label, active := m.retries.label(); label != "" {
+		chips = append(chips, chip(m, label, active))
 	}
 	if state := surfaceStateLabel(m.mainState); state != "" {
 		chips = append(chips, chip(m, state, m.mainState == SurfaceError || m.mainState == SurfaceStale))
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gentij/lunie/apps/cli/internal/api"
+	"github.com/gentij/lunie/apps/cli/internal/tui/utils"
+)
+
+// renderNetworkScreen shows recent API round trips, newest first, with the
+// redacted headers and bodies of the selected one.
+func renderNetworkScreen(m Model) string {
+	innerWidth := max(m.width-2, 1)
+	innerHeight := max(m.height-2, 1)
+	entries := m.network.snapshot()
+	cursor := min(m.networkCursor, max(len(entries)-1, 0))
+
+	headerTitle := m.styles.PanelTitle.Render("Network")
+	headerHint := m.styles.Dim.Render(fmt.Sprintf("%d requests  |  ↑/↓ select  |  esc or ctrl+n close", len(entries)))
+	divider := m.styles.Divider.Render(strings.Repeat("─", innerWidth))
+
+	bodyHeight := max(innerHeight-3, 1)
+	listHeight := max(min(len(entries), bodyHeight/3), 1)
+	start := 0
+	if cursor >= listHeight {
+		start = cursor - listHeight + 1
+	}
+
+	lines := make([]string, 0, bodyHeight)
+	if len(entries) == 0 {
+		lines = append(lines, m.styles.Dim.Render("No requests yet."))
+	}
+	for i := start; i < len(entries) && i < start+listHeight; i++ {
+		line := utils.Truncate(networkEntryLine(entries[i]), innerWidth)
+		if i == cursor {
+			line = m.styles.TableSelected.Width(innerWidth).Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(entries) > 0 {
+		lines = append(lines, divider)
+		detail := utils.WrapText(networkEntryDetail(entries[cursor]), innerWidth)
+		lines = append(lines, strings.Split(detail, "\n")...)
+	}
+	if len(lines) > bodyHeight {
+		lines = lines[:bodyHeight]
+	}
+
+	body := strings.Join(append([]string{headerTitle, headerHint, divider}, lines...), "\n")
+	body = sanitizeRenderable(body)
+	filled := applyBackgroundLayer(body, innerWidth, innerHeight, m.styles.PanelFill)
+	content := lipgloss.Place(innerWidth, innerHeight, lipgloss.Left, lipgloss.Top, filled)
+	panel := m.styles.PanelBorderFocus.Width(innerWidth).Height(innerHeight).Render(content)
+
+	return clampToViewport(panel, m.width, m.height)
+}
+
+func networkEntryLine(entry api.TraceEntry) string {
+	status := fmt.Sprintf("%d", entry.StatusCode)
+	if entry.Err != nil {
+		status = "ERR"
+	}
+	return fmt.Sprintf(
+		"%s  %-6s %-4s %6s  %s",
+		entry.Time.Format("15:04:05"),
+		entry.Method,
+		status,
+		entry.Latency.Round(time.Millisecond),
+		entry.URL,
+	)
+}
+
+func networkEntryDetail(entry api.TraceEntry) string {
+	lines := []string{api.FormatTraceLine(entry)}
+	lines = append(lines, networkHeaderLines("> ", entry.RequestHeader)...)
+	if entry.RequestBody != "" {
+		lines = append(lines, "", "Request body:", utils.PrettyJSON(entry.RequestBody))
+	}
+	lines = append(lines, networkHeaderLines("< ", entry.ResponseHeader)...)
+	if entry.ResponseBody != "" {
+		lines = append(lines, "", "Response body:", utils.PrettyJSON(entry.ResponseBody))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func networkHeaderLines(prefix string, header http.Header) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, prefix+name+": "+strings.Join(header[name], ", "))
+	}
+	return lines
+}
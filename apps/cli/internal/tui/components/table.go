@@ -30,7 +30,9 @@ func TableStyles(styleSet styles.StyleSet) table.Styles {
 	return style
 }
 
-func StyleRows(rows []table.Row, columns []table.Column, selected int, styleSet styles.StyleSet) []table.Row {
+// StyleRows pads the cells and marks the selected row with "> " and changed
+// rows with "* ".
+func StyleRows(rows []table.Row, columns []table.Column, selected int, changed map[int]bool, styleSet styles.StyleSet) []table.Row {
 	styled := make([]table.Row, len(rows))
 	widths := make([]int, len(columns))
 	for i, col := range columns {
@@ -51,6 +53,8 @@ func StyleRows(rows []table.Row, columns []table.Column, selected int, styleSet
 				marker := "  "
 				if i == selected {
 					marker = "> "
+				} else if changed[i] {
+					marker = "* "
 				}
 				cell = marker + padCell(cell, max(effectiveWidth-len(marker), 1))
 			} else {
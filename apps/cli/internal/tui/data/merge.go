@@ -0,0 +1,221 @@
+package data
+
+import (
+	"strings"
+	"time"
+)
+
+type Entity string
+
+const (
+	EntityWorkflow        Entity = "workflow"
+	EntityWorkflowVersion Entity = "workflowVersion"
+	EntityTrigger         Entity = "trigger"
+	EntityRun             Entity = "run"
+	EntitySecret          Entity = "secret"
+)
+
+// Index records the updatedAt the store holds for each entity, so a refresh
+// running in the background can tell changed items apart without reading
+// the store itself.
+type Index map[Entity]map[string]time.Time
+
+func (s *Store) Index() Index {
+	index := Index{
+		EntityWorkflow:        map[string]time.Time{},
+		EntityWorkflowVersion: map[string]time.Time{},
+		EntityTrigger:         map[string]time.Time{},
+		EntityRun:             map[string]time.Time{},
+		EntitySecret:          map[string]time.Time{},
+	}
+	for _, wf := range s.Workflows {
+		index[EntityWorkflow][wf.ID] = wf.UpdatedAt
+	}
+	for _, version := range s.WorkflowVersions {
+		index[EntityWorkflowVersion][version.ID] = version.CreatedAt
+	}
+	for _, trigger := range s.Triggers {
+		index[EntityTrigger][trigger.ID] = trigger.UpdatedAt
+	}
+	for _, run := range s.Runs {
+		index[EntityRun][run.ID] = run.UpdatedAt
+	}
+	for _, secret := range s.Secrets {
+		index[EntitySecret][secret.ID] = secret.UpdatedAt
+	}
+	return index
+}
+
+// Changed reports whether the store lacks the entity or holds an older copy.
+func (i Index) Changed(entity Entity, id string, updatedAt time.Time) bool {
+	known, ok := i[entity][id]
+	return !ok || !known.Equal(updatedAt)
+}
+
+// Known reports whether the store holds the entity at all.
+func (i Index) Known(entity Entity, id string) bool {
+	_, ok := i[entity][id]
+	return ok
+}
+
+// Delta holds the entities a delta refresh found changed. Versions never
+// change once created, so they are keyed by workflow and hold only the
+// versions the store lacks.
+type Delta struct {
+	Workflows        []Workflow
+	WorkflowVersions map[string][]WorkflowVersion
+	Triggers         []Trigger
+	Runs             []WorkflowRun
+	Secrets          []Secret
+}
+
+type ChangeKind int
+
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeUpdated
+	ChangeStatus
+)
+
+// Change describes one entity a merge added or updated. From and To hold the
+// status before and after: the run status, or active/inactive.
+type Change struct {
+	Entity Entity
+	Kind   ChangeKind
+	ID     string
+	From   string
+	To     string
+}
+
+// Merge applies delta in place: known entities keep their position and new
+// ones are put first, as they are the most recently updated.
+func (s *Store) Merge(delta Delta) []Change {
+	changes := []Change{}
+	s.Workflows = mergeByID(s.Workflows, delta.Workflows, EntityWorkflow, func(wf Workflow) (string, string) { return wf.ID, activeStatus(wf.Active) }, &changes)
+	s.Triggers = mergeByID(s.Triggers, delta.Triggers, EntityTrigger, func(t Trigger) (string, string) { return t.ID, activeStatus(t.Active) }, &changes)
+	s.Runs = mergeByID(s.Runs, delta.Runs, EntityRun, func(r WorkflowRun) (string, string) { return r.ID, r.Status }, &changes)
+	s.Secrets = mergeByID(s.Secrets, delta.Secrets, EntitySecret, func(sec Secret) (string, string) { return sec.ID, "" }, &changes)
+	known := make(map[string]bool, len(s.WorkflowVersions))
+	for _, version := range s.WorkflowVersions {
+		known[version.ID] = true
+	}
+	added := []WorkflowVersion{}
+	for _, versions := range delta.WorkflowVersions {
+		for _, version := range versions {
+			if !known[version.ID] {
+				known[version.ID] = true
+				added = append(added, version)
+			}
+		}
+	}
+	s.WorkflowVersions = append(added, s.WorkflowVersions...)
+	s.Link()
+	return changes
+}
+
+func mergeByID[T any](items []T, updates []T, entity Entity, key func(T) (string, string), changes *[]Change) []T {
+	positions := make(map[string]int, len(items))
+	for i, item := range items {
+		id, _ := key(item)
+		positions[id] = i
+	}
+	added := []T{}
+	for _, update := range updates {
+		id, status := key(update)
+		i, ok := positions[id]
+		if !ok {
+			added = append(added, update)
+			*changes = append(*changes, Change{Entity: entity, Kind: ChangeAdded, ID: id, To: status})
+			continue
+		}
+		_, before := key(items[i])
+		items[i] = update
+		kind := ChangeUpdated
+		if before != status {
+			kind = ChangeStatus
+		}
+		*changes = append(*changes, Change{Entity: entity, Kind: kind, ID: id, From: before, To: status})
+	}
+	if len(added) == 0 {
+		return items
+	}
+	return append(added, items...)
+}
+
+func activeStatus(active bool) string {
+	if active {
+		return "active"
+	}
+	return "inactive"
+}
+
+// Link fills in the fields that join entities loaded separately: latest
+// workflow versions, run trigger types, event run links and failed run
+// errors. It drops entities whose parent is gone.
+func (s *Store) Link() {
+	workflows := map[string]bool{}
+	for _, wf := range s.Workflows {
+		workflows[wf.ID] = true
+	}
+	s.WorkflowVersions = keep(s.WorkflowVersions, func(v WorkflowVersion) bool { return workflows[v.WorkflowID] })
+	s.Triggers = keep(s.Triggers, func(t Trigger) bool { return workflows[t.WorkflowID] })
+	s.Runs = keep(s.Runs, func(r WorkflowRun) bool { return workflows[r.WorkflowID] })
+
+	latest := map[string]int{}
+	for _, version := range s.WorkflowVersions {
+		latest[version.WorkflowID] = max(latest[version.WorkflowID], version.Version)
+	}
+	for i, wf := range s.Workflows {
+		if version, ok := latest[wf.ID]; ok {
+			s.Workflows[i].LatestVersion = version
+		}
+	}
+
+	triggerTypes := map[string]string{}
+	for _, trigger := range s.Triggers {
+		triggerTypes[trigger.ID] = trigger.Type
+	}
+	s.Events = keep(s.Events, func(e Event) bool { _, ok := triggerTypes[e.TriggerID]; return ok })
+
+	runs := map[string]bool{}
+	runByEvent := map[string]string{}
+	for i, run := range s.Runs {
+		runs[run.ID] = true
+		if run.EventID != "" {
+			runByEvent[run.EventID] = run.ID
+		}
+		if triggerType, ok := triggerTypes[run.TriggerID]; ok {
+			s.Runs[i].TriggerType = triggerType
+		}
+	}
+	s.StepRuns = keep(s.StepRuns, func(step StepRun) bool { return runs[step.RunID] })
+
+	for i, event := range s.Events {
+		s.Events[i].RunID = nil
+		if runID, ok := runByEvent[event.ID]; ok {
+			s.Events[i].RunID = &runID
+		}
+	}
+
+	runErrors := map[string]string{}
+	for _, step := range s.StepRuns {
+		if step.Status == "FAILED" && step.ErrorJSON != "" && runErrors[step.RunID] == "" {
+			runErrors[step.RunID] = step.ErrorJSON
+		}
+	}
+	for i, run := range s.Runs {
+		if strings.TrimSpace(run.ErrorJSON) == "" {
+			s.Runs[i].ErrorJSON = runErrors[run.ID]
+		}
+	}
+}
+
+func keep[T any](items []T, ok func(T) bool) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if ok(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeUpdatesInPlaceAndReportsChanges(t *testing.T) {
+	now := time.Now()
+	store := Store{
+		Workflows: []Workflow{{ID: "wf_1"}},
+		Triggers:  []Trigger{{ID: "trg_1", WorkflowID: "wf_1", Type: "cron"}},
+		Runs: []WorkflowRun{
+			{ID: "run_1", WorkflowID: "wf_1", Status: "RUNNING", UpdatedAt: now.Add(-time.Minute)},
+			{ID: "run_2", WorkflowID: "wf_1", Status: "SUCCEEDED", UpdatedAt: now.Add(-time.Hour)},
+		},
+		StepRuns: []StepRun{{ID: "step_1", RunID: "run_1", Status: "FAILED", ErrorJSON: `{"message":"boom"}`}},
+	}
+
+	changes := store.Merge(Delta{Runs: []WorkflowRun{
+		{ID: "run_3", WorkflowID: "wf_1", Status: "QUEUED", TriggerID: "trg_1", TriggerType: "manual", UpdatedAt: now},
+		{ID: "run_1", WorkflowID: "wf_1", Status: "FAILED", UpdatedAt: now},
+	}})
+
+	ids := []string{}
+	for _, run := range store.Runs {
+		ids = append(ids, run.ID)
+	}
+	if len(ids) != 3 || ids[0] != "run_3" || ids[1] != "run_1" || ids[2] != "run_2" {
+		t.Fatalf("expected new run first and known runs in place, got %v", ids)
+	}
+	if store.Runs[0].TriggerType != "cron" {
+		t.Fatalf("expected merged run to be linked to its trigger, got %q", store.Runs[0].TriggerType)
+	}
+	if store.Runs[1].ErrorJSON == "" {
+		t.Fatal("expected failed step error to be linked to the updated run")
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0] != (Change{Entity: EntityRun, Kind: ChangeAdded, ID: "run_3", To: "QUEUED"}) {
+		t.Fatalf("unexpected added change: %+v", changes[0])
+	}
+	if changes[1] != (Change{Entity: EntityRun, Kind: ChangeStatus, ID: "run_1", From: "RUNNING", To: "FAILED"}) {
+		t.Fatalf("unexpected status change: %+v", changes[1])
+	}
+}
+
+func TestIndexChanged(t *testing.T) {
+	now := time.Now()
+	store := Store{Runs: []WorkflowRun{{ID: "run_1", UpdatedAt: now}}}
+	index := store.Index()
+
+	if index.Changed(EntityRun, "run_1", now) {
+		t.Fatal("expected identical updatedAt to be unchanged")
+	}
+	if !index.Changed(EntityRun, "run_1", now.Add(time.Second)) {
+		t.Fatal("expected newer updatedAt to be changed")
+	}
+	if !index.Changed(EntityRun, "run_2", now) || index.Known(EntityRun, "run_2") {
+		t.Fatal("expected unknown run to be changed and not known")
+	}
+}
+
+func TestMergeAddsOnlyNewVersions(t *testing.T) {
+	store := Store{
+		Workflows:        []Workflow{{ID: "wf_1"}},
+		WorkflowVersions: []WorkflowVersion{{ID: "ver_1", WorkflowID: "wf_1", Version: 1}},
+	}
+
+	store.Merge(Delta{WorkflowVersions: map[string][]WorkflowVersion{
+		"wf_1": {{ID: "ver_2", WorkflowID: "wf_1", Version: 2}, {ID: "ver_1", WorkflowID: "wf_1", Version: 1}},
+	}})
+
+	if len(store.WorkflowVersions) != 2 || store.WorkflowVersions[0].ID != "ver_2" || store.WorkflowVersions[1].ID != "ver_1" {
+		t.Fatalf("expected the new version before the known one, got %+v", store.WorkflowVersions)
+	}
+	if store.Workflows[0].LatestVersion != 2 {
+		t.Fatalf("expected latest version 2, got %d", store.Workflows[0].LatestVersion)
+	}
+}
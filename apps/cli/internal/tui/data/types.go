@@ -27,6 +27,7 @@ type Trigger struct {
 	Name       string
 	Active     bool
 	CreatedAt  time.Time
+	UpdatedAt  time.Time
 	ConfigJSON string
 }
 
@@ -45,12 +46,15 @@ type WorkflowRun struct {
 	WorkflowID  string
 	Number      int
 	Status      string
+	TriggerID   string
+	EventID     string
 	TriggerType string
 	StartedAt   time.Time
 	Duration    time.Duration
 	InputJSON   string
 	OutputJSON  string
 	ErrorJSON   string
+	UpdatedAt   time.Time
 }
 
 type StepRun struct {
@@ -61,6 +65,7 @@ type StepRun struct {
 	StartedAt time.Time
 	Duration  time.Duration
 	Log       string
+	ErrorJSON string
 }
 
 func (s StepRun) FilterValue() string { return s.StepKey }
@@ -70,6 +75,7 @@ type Secret struct {
 	Name        string
 	Description string
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type ApiToken struct {
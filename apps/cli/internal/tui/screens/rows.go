@@ -129,7 +129,7 @@ func tokenRows(store *data.Store, now time.Time) ([]table.Row, []string) {
 		}
 		rows = append(rows, table.Row{
 			tok.Name,
-			strings.Join(tok.Scopes, ","),
+			tokenScopes(tok.Scopes),
 			utils.RelativeTime(now, tok.CreatedAt),
 			lastUsed,
 			status,
@@ -200,3 +200,10 @@ func fitColumns(columns []table.Column, width int) []table.Column {
 	}
 	return columns
 }
+
+func tokenScopes(scopes []string) string {
+	if len(scopes) == 0 {
+		return "all"
+	}
+	return strings.Join(scopes, ",")
+}
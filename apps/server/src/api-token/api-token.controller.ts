@@ -0,0 +1,66 @@
+import { Body, Controller, Get, Param, Post, Query } from '@nestjs/common';
+import { ApiBearerAuth, ApiTags } from '@nestjs/swagger';
+import {
+  ApiEnvelope,
+  ApiPaginatedEnvelope,
+} from 'src/common/swagger/envelope/api-envelope.decorator';
+import { ApiTokenService } from './api-token.service';
+import {
+  ApiTokenListQueryDto,
+  ApiTokenResDto,
+  CreateApiTokenReqDto,
+  CreateApiTokenResDto,
+} from './dto/api-token.dto';
+import type { ApiToken } from '@prisma/client';
+import { CurrentApiToken } from 'src/auth/current-api-token.decorator';
+import { TokenScope } from 'src/auth/token-scope.decorator';
+
+@ApiTags('API Tokens')
+@ApiBearerAuth('bearer')
+@Controller('api-tokens')
+@TokenScope('tokens')
+export class ApiTokenController {
+  constructor(private readonly service: ApiTokenService) {}
+
+  @ApiEnvelope(CreateApiTokenResDto, {
+    description: 'Create API token (the token is only returned once)',
+    errors: [400, 401, 403, 500],
+  })
+  @Post()
+  create(
+    @Body() body: CreateApiTokenReqDto,
+    @CurrentApiToken() current: ApiToken,
+  ) {
+    return this.service.create(
+      { name: body.name, scopes: body.scopes },
+      current.scopes,
+    );
+  }
+
+  @ApiPaginatedEnvelope(ApiTokenResDto, {
+    description: 'List API tokens',
+    errors: [401, 500],
+  })
+  @Get()
+  list(@Query() query: ApiTokenListQueryDto) {
+    return this.service.list(query);
+  }
+
+  @ApiEnvelope(ApiTokenResDto, {
+    description: 'Get API token',
+    errors: [401, 404, 500],
+  })
+  @Get(':id')
+  get(@Param('id') id: string) {
+    return this.service.get(id);
+  }
+
+  @ApiEnvelope(ApiTokenResDto, {
+    description: 'Revoke API token',
+    errors: [401, 404, 500],
+  })
+  @Post(':id/revoke')
+  revoke(@Param('id') id: string) {
+    return this.service.revoke(id);
+  }
+}
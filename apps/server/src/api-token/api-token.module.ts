@@ -2,9 +2,12 @@ import { Module } from '@nestjs/common';
 import { ApiTokenService } from './api-token.service';
 import { ApiTokenRepository } from '@lunie/db-access';
 import { PrismaModule } from 'src/prisma/prisma.module';
+import { CryptoModule } from 'src/crypto/crypto.module';
+import { ApiTokenController } from './api-token.controller';
 
 @Module({
-  imports: [PrismaModule],
+  imports: [PrismaModule, CryptoModule],
+  controllers: [ApiTokenController],
   providers: [ApiTokenService, ApiTokenRepository],
   exports: [ApiTokenService],
 })
@@ -12,18 +12,26 @@ import {
   createApiTokenListFixture,
 } from 'test/api-token/api-token.service.mock';
 import { AppError } from 'src/common/http/errors/app-error';
+import { CryptoService } from 'src/crypto/crypto.service';
+import {
+  createCryptoServiceMock,
+  CryptoServiceMock,
+} from 'test/crypto/crypto.service.mock';
 
 describe('ApiTokenService', () => {
   let service: ApiTokenService;
   let repo: ApiTokenRepositoryMock;
+  let crypto: CryptoServiceMock;
 
   beforeEach(async () => {
     repo = createApiTokenRepositoryMock();
+    crypto = createCryptoServiceMock();
 
     const moduleRef = await Test.createTestingModule({
       providers: [
         ApiTokenService,
         { provide: ApiTokenRepository, useValue: repo },
+        { provide: CryptoService, useValue: crypto },
       ],
     }).compile();
 
@@ -145,4 +153,84 @@ describe('ApiTokenService', () => {
       expect(result).toBe(revoked);
     });
   });
+
+  describe('list', () => {
+    it('returns tokens without their hashes', async () => {
+      repo.findPage.mockResolvedValue({
+        items: createApiTokenListFixture(2),
+        total: 2,
+      });
+
+      const result = await service.list({
+        page: 1,
+        pageSize: 20,
+        sortBy: 'createdAt',
+        sortOrder: 'desc',
+      });
+
+      expect(result.items).toHaveLength(2);
+      expect(result.items[0]).not.toHaveProperty('tokenHash');
+      expect(result.pagination.total).toBe(2);
+    });
+  });
+
+  describe('create', () => {
+    it('stores the hash and returns the raw token once', async () => {
+      crypto.generateApiToken.mockReturnValue('lunie_raw');
+      crypto.hashApiToken.mockReturnValue('hashed');
+      repo.create.mockResolvedValue(
+        createApiTokenFixture({
+          id: 't1',
+          name: 'ci',
+          tokenHash: 'hashed',
+          scopes: ['runs:read'],
+        }),
+      );
+
+      const result = await service.create({
+        name: 'ci',
+        scopes: ['runs:read'],
+      });
+
+      expect(repo.create).toHaveBeenCalledWith({
+        name: 'ci',
+        tokenHash: 'hashed',
+        scopes: ['runs:read'],
+      });
+      expect(result.token).toBe('lunie_raw');
+      expect(result).not.toHaveProperty('tokenHash');
+    });
+
+    it('lets a scoped token grant only its own scopes', async () => {
+      await expect(
+        service.create({ name: 'wide', scopes: ['secrets:read'] }, [
+          'tokens:write',
+        ]),
+      ).rejects.toBeInstanceOf(AppError);
+      await expect(
+        service.create({ name: 'full' }, ['tokens:write']),
+      ).rejects.toBeInstanceOf(AppError);
+      expect(repo.create).not.toHaveBeenCalled();
+    });
+  });
+
+  describe('revoke', () => {
+    it('throws not found for unknown tokens', async () => {
+      repo.findById.mockResolvedValue(null);
+
+      await expect(service.revoke('missing')).rejects.toBeInstanceOf(AppError);
+      expect(repo.revoke).not.toHaveBeenCalled();
+    });
+
+    it('does not revoke a token twice', async () => {
+      repo.findById.mockResolvedValue(
+        createRevokedApiTokenFixture({ id: 't1' }),
+      );
+
+      const result = await service.revoke('t1');
+
+      expect(result.revokedAt).not.toBeNull();
+      expect(repo.revoke).not.toHaveBeenCalled();
+    });
+  });
 });
@@ -3,10 +3,17 @@ import { ApiTokenRepository } from '@lunie/db-access';
 import { ApiToken } from '@prisma/client';
 import { AppError } from 'src/common/http/errors/app-error';
 import { ErrorDefinitions } from 'src/common/http/errors/error-codes';
+import { CryptoService } from 'src/crypto/crypto.service';
+import { buildPaginationMeta } from 'src/common/pagination/pagination';
+
+export type ApiTokenView = Omit<ApiToken, 'tokenHash'>;
 
 @Injectable()
 export class ApiTokenService {
-  constructor(private readonly repo: ApiTokenRepository) {}
+  constructor(
+    private readonly repo: ApiTokenRepository,
+    private readonly crypto: CryptoService,
+  ) {}
 
   async hasAnyActiveToken(): Promise<boolean> {
     const tokens = await this.repo.findActive();
@@ -40,4 +47,79 @@ export class ApiTokenService {
   async revokeToken(id: string): Promise<ApiToken> {
     return this.repo.revoke(id);
   }
+
+  async list(params: {
+    page: number;
+    pageSize: number;
+    sortBy: 'createdAt';
+    sortOrder: 'asc' | 'desc';
+  }): Promise<{
+    items: ApiTokenView[];
+    pagination: ReturnType<typeof buildPaginationMeta>;
+  }> {
+    const { items, total } = await this.repo.findPage(params);
+    return {
+      items: items.map(toView),
+      pagination: buildPaginationMeta({
+        page: params.page,
+        pageSize: params.pageSize,
+        total,
+        sortBy: params.sortBy,
+        sortOrder: params.sortOrder,
+      }),
+    };
+  }
+
+  async get(id: string): Promise<ApiTokenView> {
+    const token = await this.repo.findById(id);
+    if (!token) throw AppError.notFound(ErrorDefinitions.API_TOKEN.NOT_FOUND);
+    return toView(token);
+  }
+
+  // The raw token is only returned here; the database keeps its hash. A
+  // scoped token can only create tokens with a subset of its own scopes.
+  async create(
+    params: {
+      name: string;
+      scopes?: string[];
+    },
+    creatorScopes: string[] = [],
+  ): Promise<ApiTokenView & { token: string }> {
+    const scopes = [...new Set(params.scopes ?? [])];
+    if (
+      creatorScopes.length > 0 &&
+      (scopes.length === 0 ||
+        scopes.some((scope) => !creatorScopes.includes(scope)))
+    ) {
+      throw AppError.forbidden({
+        message: 'A scoped token cannot grant scopes it does not have',
+        scopes: creatorScopes,
+      });
+    }
+
+    const token = this.crypto.generateApiToken();
+    const created = await this.repo.create({
+      name: params.name,
+      tokenHash: this.crypto.hashApiToken(token),
+      scopes,
+    });
+    return { ...toView(created), token };
+  }
+
+  async revoke(id: string): Promise<ApiTokenView> {
+    const token = await this.get(id);
+    if (token.revokedAt) return token;
+    return toView(await this.repo.revoke(id));
+  }
+}
+
+function toView(token: ApiToken): ApiTokenView {
+  return {
+    id: token.id,
+    name: token.name,
+    scopes: token.scopes,
+    createdAt: token.createdAt,
+    lastUsedAt: token.lastUsedAt,
+    revokedAt: token.revokedAt,
+  };
 }
@@ -3,3 +3,26 @@ export type CreateApiTokenInput = {
   tokenHash: string;
   scopes?: string[];
 };
+
+// Scoped tokens need `<resource>:read` for GET requests and `<resource>:write`
+// for everything else. A token without scopes has full access.
+export const API_TOKEN_SCOPES = [
+  'workflows:read',
+  'workflows:write',
+  'runs:read',
+  'runs:write',
+  'triggers:read',
+  'triggers:write',
+  'secrets:read',
+  'secrets:write',
+  'tokens:read',
+  'tokens:write',
+] as const;
+
+export type ApiTokenScope = (typeof API_TOKEN_SCOPES)[number];
+export type ApiTokenScopeResource =
+  | 'workflows'
+  | 'runs'
+  | 'triggers'
+  | 'secrets'
+  | 'tokens';
@@ -0,0 +1,44 @@
+import { createZodDto } from 'nestjs-zod';
+import { z } from 'zod';
+import {
+  PaginationQuerySchema,
+  SortOrderSchema,
+} from 'src/common/dto/pagination.dto';
+import { API_TOKEN_SCOPES } from 'src/api-token/api-token.types';
+
+export const ApiTokenListSortBySchema = z.enum(['createdAt']);
+export const ApiTokenListQuerySchema = PaginationQuerySchema.extend({
+  sortBy: ApiTokenListSortBySchema.default('createdAt'),
+  sortOrder: SortOrderSchema.default('desc'),
+});
+export class ApiTokenListQueryDto extends createZodDto(
+  ApiTokenListQuerySchema,
+) {}
+
+export const ApiTokenResSchema = z.object({
+  id: z.string(),
+  name: z.string(),
+  scopes: z.array(z.string()),
+  createdAt: z.iso.datetime(),
+  lastUsedAt: z.iso.datetime().nullable(),
+  revokedAt: z.iso.datetime().nullable(),
+});
+
+export class ApiTokenResDto extends createZodDto(ApiTokenResSchema) {}
+
+export const CreateApiTokenResSchema = ApiTokenResSchema.extend({
+  token: z.string(),
+});
+
+export class CreateApiTokenResDto extends createZodDto(
+  CreateApiTokenResSchema,
+) {}
+
+export const CreateApiTokenReqSchema = z.object({
+  name: z.string().min(1).max(120),
+  scopes: z.array(z.enum(API_TOKEN_SCOPES)).max(50).optional(),
+});
+
+export class CreateApiTokenReqDto extends createZodDto(
+  CreateApiTokenReqSchema,
+) {}
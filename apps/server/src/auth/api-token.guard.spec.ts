@@ -0,0 +1,113 @@
+import { ExecutionContext } from '@nestjs/common';
+import { Reflector } from '@nestjs/core';
+import { ApiTokenGuard } from './api-token.guard';
+import { TokenScope } from './token-scope.decorator';
+import { ApiTokenService } from 'src/api-token/api-token.service';
+import { AppError } from 'src/common/http/errors/app-error';
+import { CryptoService } from 'src/crypto/crypto.service';
+import {
+  createApiTokenFixture,
+  createApiTokenServiceMock,
+  ApiTokenServiceMock,
+} from 'test/api-token/api-token.service.mock';
+import {
+  createCryptoServiceMock,
+  CryptoServiceMock,
+} from 'test/crypto/crypto.service.mock';
+
+@TokenScope('runs')
+class RunsController {
+  list() {}
+
+  @TokenScope('any')
+  whoami() {}
+}
+
+class UnscopedController {
+  list() {}
+}
+
+const httpContext = (
+  cls: object,
+  handler: () => void,
+  method: string,
+): ExecutionContext =>
+  ({
+    getClass: () => cls,
+    getHandler: () => handler,
+    switchToHttp: () => ({
+      getRequest: () => ({
+        method,
+        headers: { authorization: 'Bearer lunie_raw' },
+      }),
+    }),
+  }) as unknown as ExecutionContext;
+
+describe('ApiTokenGuard', () => {
+  let guard: ApiTokenGuard;
+  let tokens: ApiTokenServiceMock;
+  let crypto: CryptoServiceMock;
+
+  beforeEach(() => {
+    tokens = createApiTokenServiceMock();
+    crypto = createCryptoServiceMock();
+    crypto.hashApiToken.mockReturnValue('hashed');
+    guard = new ApiTokenGuard(
+      new Reflector(),
+      tokens as unknown as ApiTokenService,
+      crypto as unknown as CryptoService,
+    );
+  });
+
+  it('lets tokens without scopes through everywhere', async () => {
+    tokens.validateTokenHash.mockResolvedValue(createApiTokenFixture());
+
+    await expect(
+      guard.canActivate(
+        httpContext(
+          UnscopedController,
+          UnscopedController.prototype.list,
+          'POST',
+        ),
+      ),
+    ).resolves.toBe(true);
+  });
+
+  it('checks the read or write scope of the resource', async () => {
+    tokens.validateTokenHash.mockResolvedValue(
+      createApiTokenFixture({ scopes: ['runs:read'] }),
+    );
+
+    await expect(
+      guard.canActivate(
+        httpContext(RunsController, RunsController.prototype.list, 'GET'),
+      ),
+    ).resolves.toBe(true);
+    await expect(
+      guard.canActivate(
+        httpContext(RunsController, RunsController.prototype.list, 'POST'),
+      ),
+    ).rejects.toBeInstanceOf(AppError);
+  });
+
+  it('closes unannotated handlers to scoped tokens', async () => {
+    tokens.validateTokenHash.mockResolvedValue(
+      createApiTokenFixture({ scopes: ['runs:read', 'runs:write'] }),
+    );
+
+    await expect(
+      guard.canActivate(
+        httpContext(
+          UnscopedController,
+          UnscopedController.prototype.list,
+          'GET',
+        ),
+      ),
+    ).rejects.toBeInstanceOf(AppError);
+    await expect(
+      guard.canActivate(
+        httpContext(RunsController, RunsController.prototype.whoami, 'GET'),
+      ),
+    ).resolves.toBe(true);
+  });
+});
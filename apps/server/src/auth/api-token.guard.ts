@@ -2,12 +2,17 @@ import { CanActivate, ExecutionContext, Injectable } from '@nestjs/common';
 import { Reflector } from '@nestjs/core';
 import type { FastifyRequest } from 'fastify';
 
+import type { ApiToken } from '@prisma/client';
 import { ApiTokenService } from 'src/api-token/api-token.service';
+import type { ApiTokenScopeResource } from 'src/api-token/api-token.types';
 import { AppError } from 'src/common/http/errors/app-error';
 import { ErrorDefinitions } from 'src/common/http/errors/error-codes';
 import { CryptoService } from 'src/crypto/crypto.service';
 
 export const IS_PUBLIC_KEY = 'isPublic';
+export const TOKEN_SCOPE_KEY = 'tokenScope';
+
+const READ_METHODS = new Set(['GET', 'HEAD', 'OPTIONS']);
 
 @Injectable()
 export class ApiTokenGuard implements CanActivate {
@@ -38,10 +43,36 @@ export class ApiTokenGuard implements CanActivate {
       throw AppError.unauthorized(ErrorDefinitions.AUTH.INVALID_TOKEN);
     }
 
+    this.assertScope(context, req, apiToken);
+
     req.apiToken = apiToken;
     return true;
   }
 
+  // Handlers without @TokenScope are closed to scoped tokens, so a new
+  // controller never silently widens what a scoped token can do.
+  private assertScope(
+    context: ExecutionContext,
+    req: FastifyRequest,
+    apiToken: ApiToken,
+  ) {
+    if (apiToken.scopes.length === 0) return;
+
+    const resource = this.reflector.getAllAndOverride<
+      ApiTokenScopeResource | 'any' | undefined
+    >(TOKEN_SCOPE_KEY, [context.getHandler(), context.getClass()]);
+    if (resource === 'any') return;
+
+    const action = READ_METHODS.has(req.method) ? 'read' : 'write';
+    const required = resource ? `${resource}:${action}` : null;
+    if (!required || !apiToken.scopes.includes(required)) {
+      throw AppError.forbidden({
+        requiredScope: required ?? 'full access',
+        scopes: apiToken.scopes,
+      });
+    }
+  }
+
   private extractBearer(req: FastifyRequest): string | null {
     const header = req.headers.authorization;
     if (typeof header !== 'string') return null;
@@ -5,8 +5,10 @@ import { CurrentApiToken } from './current-api-token.decorator';
 import type { ApiToken } from '@prisma/client';
 import { WhoamiResDto } from './dto/auth.dto';
 import { ApiBearerAuth } from '@nestjs/swagger';
+import { TokenScope } from './token-scope.decorator';
 
 @Controller('auth')
+@TokenScope('any')
 @ApiBearerAuth('bearer')
 export class AuthController {
   constructor(private readonly authService: AuthService) {}
@@ -0,0 +1,7 @@
+import { SetMetadata } from '@nestjs/common';
+import type { ApiTokenScopeResource } from 'src/api-token/api-token.types';
+import { TOKEN_SCOPE_KEY } from './api-token.guard';
+
+// 'any' lets every valid token through, whatever its scopes.
+export const TokenScope = (resource: ApiTokenScopeResource | 'any') =>
+  SetMetadata(TOKEN_SCOPE_KEY, resource);
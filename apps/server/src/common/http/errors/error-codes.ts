@@ -97,6 +97,13 @@ export const ErrorDefinitions = {
     },
   },
 
+  API_TOKEN: {
+    NOT_FOUND: {
+      code: 'API_TOKEN_NOT_FOUND',
+      message: 'API token not found',
+    },
+  },
+
   SECRET: {
     NOT_FOUND: {
       code: 'SECRET_NOT_FOUND',
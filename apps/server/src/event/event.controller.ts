@@ -6,10 +6,12 @@ import {
 } from 'src/common/swagger/envelope/api-envelope.decorator';
 import { EventService } from './event.service';
 import { EventListQueryDto, EventResDto } from './dto/event.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Events')
 @ApiBearerAuth('bearer')
 @Controller('workflows/:workflowId/triggers/:triggerId/events')
+@TokenScope('triggers')
 export class EventController {
   constructor(private readonly service: EventService) {}
 
@@ -20,10 +20,12 @@ import {
   SecretResDto,
   UpdateSecretReqDto,
 } from './dto/secret.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Secrets')
 @ApiBearerAuth('bearer')
 @Controller('secrets')
+@TokenScope('secrets')
 export class SecretController {
   constructor(private readonly service: SecretService) {}
 
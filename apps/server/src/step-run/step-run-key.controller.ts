@@ -8,10 +8,12 @@ import { StepRunService } from './step-run.service';
 import { StepRunListQueryDto, StepRunResDto } from './dto/step-run.dto';
 import { WorkflowService } from 'src/workflow/workflow.service';
 import { WorkflowRunService } from 'src/workflow-run/workflow-run.service';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Step Runs')
 @ApiBearerAuth('bearer')
 @Controller('workflows/by-key/:workflowKey/runs/:runNumber/steps')
+@TokenScope('runs')
 export class StepRunKeyController {
   constructor(
     private readonly service: StepRunService,
@@ -6,10 +6,12 @@ import {
 } from 'src/common/swagger/envelope/api-envelope.decorator';
 import { StepRunService } from './step-run.service';
 import { StepRunListQueryDto, StepRunResDto } from './dto/step-run.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Step Runs')
 @ApiBearerAuth('bearer')
 @Controller('workflows/:workflowId/runs/:runId/steps')
+@TokenScope('runs')
 export class StepRunController {
   constructor(private readonly service: StepRunService) {}
 
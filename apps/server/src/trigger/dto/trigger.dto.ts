@@ -53,6 +53,8 @@ export class RotateWebhookKeyResDto extends createZodDto(
 
 export const TriggerWebhookIngressResSchema = z.object({
   status: z.enum(['accepted', 'trigger_inactive']),
+  workflowRunId: z.string().optional(),
+  workflowRunNumber: z.number().int().optional(),
 });
 export class TriggerWebhookIngressResDto extends createZodDto(
   TriggerWebhookIngressResSchema,
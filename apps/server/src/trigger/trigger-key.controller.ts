@@ -28,10 +28,12 @@ import {
   RunWorkflowReqDto,
   parseRunWorkflowReq,
 } from 'src/workflow/dto/workflow.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Triggers')
 @ApiBearerAuth('bearer')
 @Controller('workflows/by-key/:workflowKey/triggers')
+@TokenScope('triggers')
 export class TriggerKeyController {
   constructor(
     private readonly service: TriggerService,
@@ -135,6 +137,7 @@ export class TriggerKeyController {
   }
 
   @ApiTags('Triggers')
+  @TokenScope('runs')
   @Post('by-key/:triggerKey/webhook')
   async handleWebhook(
     @Param('workflowKey') workflowKey: string,
@@ -153,16 +156,17 @@ export class TriggerKeyController {
       throw new Error('Workflow has no versions');
     }
 
-    await this.orchestrationService.startWorkflow({
-      workflowId: workflow.id,
-      workflowVersionId: workflow.latestVersionId,
-      triggerId: trigger.id,
-      eventType: 'WEBHOOK',
-      eventPayload: input,
-      input,
-      overrides,
-    });
+    const { workflowRunId, workflowRunNumber } =
+      await this.orchestrationService.startWorkflow({
+        workflowId: workflow.id,
+        workflowVersionId: workflow.latestVersionId,
+        triggerId: trigger.id,
+        eventType: 'WEBHOOK',
+        eventPayload: input,
+        input,
+        overrides,
+      });
 
-    return { status: 'accepted' };
+    return { status: 'accepted', workflowRunId, workflowRunNumber };
   }
 }
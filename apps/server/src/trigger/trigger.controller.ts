@@ -28,10 +28,12 @@ import {
   RunWorkflowReqDto,
   parseRunWorkflowReq,
 } from 'src/workflow/dto/workflow.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Triggers')
 @ApiBearerAuth('bearer')
 @Controller('workflows/:workflowId/triggers')
+@TokenScope('triggers')
 export class TriggerController {
   constructor(
     private readonly service: TriggerService,
@@ -120,6 +122,7 @@ export class TriggerController {
   }
 
   @ApiTags('Triggers')
+  @TokenScope('runs')
   @Post(':id/webhook')
   async handleWebhook(
     @Param('workflowId') workflowId: string,
@@ -140,16 +143,17 @@ export class TriggerController {
       throw new Error('Workflow has no versions');
     }
 
-    await this.orchestrationService.startWorkflow({
-      workflowId,
-      workflowVersionId: workflow.latestVersionId,
-      triggerId,
-      eventType: 'WEBHOOK',
-      eventPayload: input,
-      input,
-      overrides,
-    });
+    const { workflowRunId, workflowRunNumber } =
+      await this.orchestrationService.startWorkflow({
+        workflowId,
+        workflowVersionId: workflow.latestVersionId,
+        triggerId,
+        eventType: 'WEBHOOK',
+        eventPayload: input,
+        input,
+        overrides,
+      });
 
-    return { status: 'accepted' };
+    return { status: 'accepted', workflowRunId, workflowRunNumber };
   }
 }
@@ -10,10 +10,12 @@ import {
   WorkflowRunResDto,
 } from './dto/workflow-run.dto';
 import { WorkflowService } from 'src/workflow/workflow.service';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Workflow Runs')
 @ApiBearerAuth('bearer')
 @Controller('workflows/by-key/:workflowKey/runs')
+@TokenScope('runs')
 export class WorkflowRunKeyController {
   constructor(
     private readonly service: WorkflowRunService,
@@ -9,10 +9,12 @@ import {
   WorkflowRunListQueryDto,
   WorkflowRunResDto,
 } from './dto/workflow-run.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Workflow Runs')
 @ApiBearerAuth('bearer')
 @Controller('workflows/:workflowId/runs')
+@TokenScope('runs')
 export class WorkflowRunController {
   constructor(private readonly service: WorkflowRunService) {}
 
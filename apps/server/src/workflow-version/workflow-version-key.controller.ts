@@ -10,10 +10,12 @@ import {
   WorkflowVersionResDto,
 } from './dto/workflow-version.dto';
 import { WorkflowService } from 'src/workflow/workflow.service';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Workflow Versions')
 @ApiBearerAuth('bearer')
 @Controller('workflows/by-key/:workflowKey/versions')
+@TokenScope('workflows')
 export class WorkflowVersionKeyController {
   constructor(
     private readonly service: WorkflowVersionService,
@@ -9,10 +9,12 @@ import {
   WorkflowVersionListQueryDto,
   WorkflowVersionResDto,
 } from './dto/workflow-version.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Workflow Versions')
 @ApiBearerAuth('bearer')
 @Controller('workflows/:workflowId/versions')
+@TokenScope('workflows')
 export class WorkflowVersionController {
   constructor(private readonly service: WorkflowVersionService) {}
 
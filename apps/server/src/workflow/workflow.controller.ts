@@ -32,10 +32,12 @@ import {
   ValidateWorkflowDefinitionReqDto,
   ValidateWorkflowDefinitionResDto,
 } from './dto/workflow-validation.dto';
+import { TokenScope } from 'src/auth/token-scope.decorator';
 
 @ApiTags('Workflows')
 @ApiBearerAuth('bearer')
 @Controller('workflows')
+@TokenScope('workflows')
 export class WorkflowController {
   constructor(
     private readonly service: WorkflowService,
@@ -200,6 +202,7 @@ export class WorkflowController {
     description: 'Start workflow manually',
     errors: [401, 404, 500],
   })
+  @TokenScope('runs')
   @Post('by-key/:workflowKey/run')
   async runManualByKey(
     @Param('workflowKey') workflowKey: string,
@@ -229,6 +232,7 @@ export class WorkflowController {
     description: 'Start workflow manually',
     errors: [401, 404, 500],
   })
+  @TokenScope('runs')
   @Post(':id/run')
   async runManual(
     @Param('id') workflowId: string,
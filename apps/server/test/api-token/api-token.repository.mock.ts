@@ -3,11 +3,19 @@ import { ApiTokenRepository } from '@lunie/db-access';
 export type ApiTokenRepositoryMock = jest.Mocked<
   Pick<
     ApiTokenRepository,
-    'findActive' | 'findByHash' | 'create' | 'updateLastUsed' | 'revoke'
+    | 'findPage'
+    | 'findById'
+    | 'findActive'
+    | 'findByHash'
+    | 'create'
+    | 'updateLastUsed'
+    | 'revoke'
   >
 >;
 
 export const createApiTokenRepositoryMock = (): ApiTokenRepositoryMock => ({
+  findPage: jest.fn(),
+  findById: jest.fn(),
   findActive: jest.fn(),
   findByHash: jest.fn(),
   create: jest.fn(),
@@ -126,6 +126,8 @@ describe('Trigger Webhook (integration e2e)', () => {
     const body = res.json();
     expect(body.ok).toBe(true);
     expect(body.data.status).toBe('accepted');
+    expect(body.data.workflowRunId).toBe('wfr_1');
+    expect(body.data.workflowRunNumber).toBe(5);
 
     expect(orchestration.startWorkflow).toHaveBeenCalledWith(
       expect.objectContaining({
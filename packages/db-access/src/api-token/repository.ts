@@ -22,6 +22,28 @@ export class ApiTokenRepository {
     });
   }
 
+  async findPage(params: {
+    page: number;
+    pageSize: number;
+    sortOrder: 'asc' | 'desc';
+  }): Promise<{ items: ApiToken[]; total: number }> {
+    const skip = (params.page - 1) * params.pageSize;
+    const [items, total] = await Promise.all([
+      this.prisma.apiToken.findMany({
+        orderBy: [{ createdAt: params.sortOrder }, { id: params.sortOrder }],
+        skip,
+        take: params.pageSize,
+      }),
+      this.prisma.apiToken.count(),
+    ]);
+
+    return { items, total };
+  }
+
+  findById(id: string): Promise<ApiToken | null> {
+    return this.prisma.apiToken.findUnique({ where: { id } });
+  }
+
   findByHash(tokenHash: string): Promise<ApiToken | null> {
     return this.prisma.apiToken.findUnique({
       where: { tokenHash },